@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"time"
+)
+
+// 權限申請的狀態，pending 為唯一的非終止狀態
+const (
+	AccessRequestPending   = "pending"
+	AccessRequestApproved  = "approved"
+	AccessRequestRejected  = "rejected"
+	AccessRequestExpired   = "expired"
+	AccessRequestCancelled = "cancelled"
+)
+
+const (
+	// AccessRequestResource 審核權限申請所需權限的資源，擁有 access_request:approve 的用戶為審核者
+	AccessRequestResource = "access_request"
+	// AccessRequestApproveAction 審核權限申請所需權限的操作
+	AccessRequestApproveAction = "approve"
+	// AccessRequestPendingTTL 申請待審核的期限，逾期未審核的申請轉為 expired
+	AccessRequestPendingTTL = 72 * time.Hour
+	// MaxAccessRequestDuration 申請角色的時長上限
+	MaxAccessRequestDuration = 30 * 24 * time.Hour
+	// MaxAccessRequestReasonLength 申請理由與審核意見的最大長度，與 access_requests 欄位一致
+	MaxAccessRequestReasonLength = 512
+)
+
+// accessRequestTransitions 申請狀態的合法轉換，終止狀態不可再變更
+var accessRequestTransitions = map[string][]string{
+	AccessRequestPending: {AccessRequestApproved, AccessRequestRejected, AccessRequestExpired, AccessRequestCancelled},
+}
+
+// AccessRequest 即時權限申請：用戶以理由申請在一段時長內擁有角色，經審核者核准後自動建立臨時角色分配
+type AccessRequest struct {
+	ID          int64 `json:"id"`
+	RequesterID int64 `json:"requester_id"`
+	RoleID      int64 `json:"role_id"`
+	// TenantID 申請的角色所屬租戶，GlobalTenantID 表示全域角色
+	TenantID int64 `json:"tenant_id"`
+	// DurationSeconds 核准後角色分配的有效秒數，自核准時起算
+	DurationSeconds int64  `json:"duration_seconds"`
+	Reason          string `json:"reason"`
+	Status          string `json:"status" example:"pending"`
+	// ReviewerID 核准或駁回的審核者，其他狀態為 nil
+	ReviewerID    *int64 `json:"reviewer_id,omitempty"`
+	ReviewComment string `json:"review_comment,omitempty"`
+	// ExpiresAt 待審核的期限，逾期未審核時轉為 expired
+	ExpiresAt time.Time `json:"expires_at"`
+	// DecidedAt 轉為終止狀態的時間
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+	// GrantedUntil 核准後角色分配的失效時間
+	GrantedUntil *time.Time `json:"granted_until,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// AccessRequestFilter 權限申請查詢條件，零值欄位不參與過濾
+type AccessRequestFilter struct {
+	Status      string
+	RequesterID int64
+	// TenantID 只返回該租戶內的申請
+	TenantID int64
+	Limit    int
+}
+
+// ValidAccessRequestStatus 判斷是否為已知的申請狀態
+func ValidAccessRequestStatus(status string) bool {
+	switch status {
+	case AccessRequestPending, AccessRequestApproved, AccessRequestRejected, AccessRequestExpired, AccessRequestCancelled:
+		return true
+	}
+	return false
+}
+
+// Duration 返回核准後角色分配的有效時長
+func (r *AccessRequest) Duration() time.Duration {
+	return time.Duration(r.DurationSeconds) * time.Second
+}
+
+// PendingExpired 判斷待審核的申請在 now 時是否已逾期
+func (r *AccessRequest) PendingExpired(now time.Time) bool {
+	return r.Status == AccessRequestPending && !now.Before(r.ExpiresAt)
+}
+
+// CanTransition 判斷申請能否從目前狀態轉為 to
+func (r *AccessRequest) CanTransition(to string) bool {
+	for _, next := range accessRequestTransitions[r.Status] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transition 轉換狀態並記錄時間，不合法的轉換返回 ErrAccessRequestNotPending
+func (r *AccessRequest) transition(to string, now time.Time) error {
+	if !r.CanTransition(to) {
+		return ErrAccessRequestNotPending
+	}
+	r.Status = to
+	r.DecidedAt = &now
+	return nil
+}
+
+// Approve 核准申請並返回應建立的臨時角色分配，逾期的申請返回 ErrAccessRequestExpired 且不變更狀態
+func (r *AccessRequest) Approve(reviewerID int64, comment string, now time.Time) (*UserRole, error) {
+	if r.PendingExpired(now) {
+		return nil, ErrAccessRequestExpired
+	}
+	if err := r.transition(AccessRequestApproved, now); err != nil {
+		return nil, err
+	}
+
+	grantedUntil := now.Add(r.Duration())
+	r.ReviewerID = &reviewerID
+	r.ReviewComment = comment
+	r.GrantedUntil = &grantedUntil
+	return &UserRole{
+		UserID:     r.RequesterID,
+		RoleID:     r.RoleID,
+		TenantID:   r.TenantID,
+		ValidUntil: &grantedUntil,
+	}, nil
+}
+
+// Reject 駁回申請，逾期的申請返回 ErrAccessRequestExpired 且不變更狀態
+func (r *AccessRequest) Reject(reviewerID int64, comment string, now time.Time) error {
+	if r.PendingExpired(now) {
+		return ErrAccessRequestExpired
+	}
+	if err := r.transition(AccessRequestRejected, now); err != nil {
+		return err
+	}
+
+	r.ReviewerID = &reviewerID
+	r.ReviewComment = comment
+	return nil
+}
+
+// Cancel 由申請人撤回待審核的申請，逾期的申請返回 ErrAccessRequestExpired 且不變更狀態
+func (r *AccessRequest) Cancel(now time.Time) error {
+	if r.PendingExpired(now) {
+		return ErrAccessRequestExpired
+	}
+	return r.transition(AccessRequestCancelled, now)
+}
+
+// Expire 將逾期未審核的申請標記為 expired，尚未逾期時返回 ErrAccessRequestNotPending
+func (r *AccessRequest) Expire(now time.Time) error {
+	if !r.PendingExpired(now) {
+		return ErrAccessRequestNotPending
+	}
+	return r.transition(AccessRequestExpired, now)
+}
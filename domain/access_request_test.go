@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPendingAccessRequest(now time.Time) *AccessRequest {
+	return &AccessRequest{
+		ID:              1,
+		RequesterID:     7,
+		RoleID:          2,
+		TenantID:        3,
+		DurationSeconds: int64((4 * time.Hour).Seconds()),
+		Reason:          "週年慶活動",
+		Status:          AccessRequestPending,
+		ExpiresAt:       now.Add(AccessRequestPendingTTL),
+	}
+}
+
+func TestAccessRequest_Approve(t *testing.T) {
+	now := time.Now()
+	request := newPendingAccessRequest(now)
+
+	assignment, err := request.Approve(9, "ok", now)
+
+	require.NoError(t, err)
+	assert.Equal(t, AccessRequestApproved, request.Status)
+	assert.Equal(t, int64(9), *request.ReviewerID)
+	assert.Equal(t, "ok", request.ReviewComment)
+	assert.Equal(t, now, *request.DecidedAt)
+	assert.Equal(t, now.Add(4*time.Hour), *request.GrantedUntil)
+	assert.Equal(t, &UserRole{UserID: 7, RoleID: 2, TenantID: 3, ValidUntil: request.GrantedUntil}, assignment)
+}
+
+func TestAccessRequest_Reject(t *testing.T) {
+	now := time.Now()
+	request := newPendingAccessRequest(now)
+
+	require.NoError(t, request.Reject(9, "不符合資格", now))
+
+	assert.Equal(t, AccessRequestRejected, request.Status)
+	assert.Equal(t, int64(9), *request.ReviewerID)
+	assert.Equal(t, "不符合資格", request.ReviewComment)
+	assert.Nil(t, request.GrantedUntil)
+}
+
+func TestAccessRequest_Cancel(t *testing.T) {
+	now := time.Now()
+	request := newPendingAccessRequest(now)
+
+	require.NoError(t, request.Cancel(now))
+
+	assert.Equal(t, AccessRequestCancelled, request.Status)
+	assert.Nil(t, request.ReviewerID)
+	assert.Equal(t, now, *request.DecidedAt)
+}
+
+func TestAccessRequest_PendingExpired(t *testing.T) {
+	now := time.Now()
+	request := newPendingAccessRequest(now)
+	deadline := request.ExpiresAt
+
+	// 尚未逾期時不可標記為 expired
+	assert.ErrorIs(t, request.Expire(now), ErrAccessRequestNotPending)
+	assert.Equal(t, AccessRequestPending, request.Status)
+
+	// 逾期後無法核准、駁回或撤回，狀態不變
+	_, err := request.Approve(9, "", deadline)
+	assert.ErrorIs(t, err, ErrAccessRequestExpired)
+	assert.ErrorIs(t, request.Reject(9, "", deadline), ErrAccessRequestExpired)
+	assert.ErrorIs(t, request.Cancel(deadline), ErrAccessRequestExpired)
+	assert.Equal(t, AccessRequestPending, request.Status)
+	assert.Nil(t, request.ReviewerID)
+
+	require.NoError(t, request.Expire(deadline))
+	assert.Equal(t, AccessRequestExpired, request.Status)
+	assert.Equal(t, deadline, *request.DecidedAt)
+}
+
+func TestAccessRequest_TerminalStates(t *testing.T) {
+	now := time.Now()
+	later := now.Add(AccessRequestPendingTTL)
+
+	transitions := map[string]func(*AccessRequest) error{
+		AccessRequestApproved: func(r *AccessRequest) error {
+			_, err := r.Approve(9, "", now)
+			return err
+		},
+		AccessRequestRejected:  func(r *AccessRequest) error { return r.Reject(9, "", now) },
+		AccessRequestCancelled: func(r *AccessRequest) error { return r.Cancel(now) },
+		AccessRequestExpired:   func(r *AccessRequest) error { return r.Expire(later) },
+	}
+
+	for from := range transitions {
+		for to, apply := range transitions {
+			t.Run(from+"->"+to, func(t *testing.T) {
+				request := newPendingAccessRequest(now)
+				request.Status = from
+
+				err := apply(request)
+
+				assert.ErrorIs(t, err, ErrAccessRequestNotPending)
+				assert.Equal(t, from, request.Status)
+				assert.False(t, request.CanTransition(to))
+			})
+		}
+	}
+}
+
+func TestValidAccessRequestStatus(t *testing.T) {
+	for _, status := range []string{AccessRequestPending, AccessRequestApproved, AccessRequestRejected, AccessRequestExpired, AccessRequestCancelled} {
+		assert.True(t, ValidAccessRequestStatus(status), status)
+	}
+	assert.False(t, ValidAccessRequestStatus("done"))
+	assert.False(t, ValidAccessRequestStatus(""))
+}
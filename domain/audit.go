@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 審計操作類型
+const (
+	AuditUserCreate           = "user.create"
+	AuditUserUpdate           = "user.update"
+	AuditUserDelete           = "user.delete"
+	AuditUserMetadataUpdate   = "user.update_metadata"
+	AuditAuthLogin            = "auth.login"
+	AuditAuthLogout           = "auth.logout"
+	AuditAuthAuthorize        = "auth.authorize"
+	AuditAuthBatchAuthorize   = "auth.batch_authorize"
+	AuditAuthRefresh          = "auth.refresh"
+	AuditAuthRevoke           = "auth.revoke"
+	AuditAuthBatchRevoke      = "auth.batch_revoke"
+	AuditSessionTerminate     = "session.terminate"
+	AuditRoleCreate           = "role.create"
+	AuditRoleUpdate           = "role.update"
+	AuditRoleDelete           = "role.delete"
+	AuditRoleSetParents       = "role.set_parents"
+	AuditPermissionCreate     = "permission.create"
+	AuditPermissionUpdate     = "permission.update"
+	AuditPermissionDelete     = "permission.delete"
+	AuditUserRoleAssign       = "user_role.assign"
+	AuditUserRoleRemove       = "user_role.remove"
+	AuditUserRoleExpire       = "user_role.expire"
+	AuditUserPermissionAssign = "user_permission.assign"
+	AuditUserPermissionRemove = "user_permission.remove"
+	AuditObjectGrantCreate    = "object_grant.create"
+	AuditObjectGrantDelete    = "object_grant.delete"
+	AuditResourceOwnerSet     = "resource_owner.set"
+	AuditResourceOwnerDelete  = "resource_owner.delete"
+	AuditOwnerRuleCreate      = "owner_rule.create"
+	AuditOwnerRuleDelete      = "owner_rule.delete"
+	AuditRolePermissionAssign = "role_permission.assign"
+	AuditRolePermissionRemove = "role_permission.remove"
+	AuditTenantCreate         = "tenant.create"
+	AuditTenantUpdate         = "tenant.update"
+	AuditTenantDelete         = "tenant.delete"
+	AuditTenantMemberAdd      = "tenant_member.add"
+	AuditTenantMemberRemove   = "tenant_member.remove"
+	AuditAccessRequestCreate  = "access_request.create"
+	AuditAccessRequestApprove = "access_request.approve"
+	AuditAccessRequestReject  = "access_request.reject"
+	AuditAccessRequestCancel  = "access_request.cancel"
+	AuditAccessRequestExpire  = "access_request.expire"
+)
+
+// 審計實體類型
+const (
+	AuditEntityUser          = "user"
+	AuditEntitySession       = "session"
+	AuditEntityRole          = "role"
+	AuditEntityPermission    = "permission"
+	AuditEntityObject        = "object"
+	AuditEntityOwnerRule     = "owner_rule"
+	AuditEntityTenant        = "tenant"
+	AuditEntityAccessRequest = "access_request"
+)
+
+// 審計操作結果
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+	AuditResultAllow   = "allow"
+	AuditResultDeny    = "deny"
+)
+
+// AuditLog 審計日誌
+type AuditLog struct {
+	ID              int64           `json:"id"`
+	Operation       string          `json:"operation"`        // 操作類型
+	EntityType      string          `json:"entity_type"`      // 操作的資源類型
+	EntityID        string          `json:"entity_id"`        // 操作的資源id
+	Operator        string          `json:"operator"`         // 操作者
+	OperationResult string          `json:"operation_result"` // 操作結果
+	Details         json.RawMessage `json:"details" gorm:"type:json" swaggertype:"object"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// AuditLogFilter 審計日誌查詢條件，零值欄位不參與過濾
+type AuditLogFilter struct {
+	Operator   string
+	EntityType string
+	EntityID   string
+	Operation  string
+	From       *time.Time
+	To         *time.Time
+	// Cursor 上一頁最後一筆的 ID，只返回比它更早的記錄
+	Cursor int64
+	Limit  int
+}
+
+// AuditLogPage 審計日誌分頁結果
+type AuditLogPage struct {
+	Items      []AuditLog `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
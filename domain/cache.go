@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Cache 快取介面，值為序列化後的位元組，由呼叫端負責編碼
+type Cache interface {
+	// Get 讀取快取，不存在或已過期時返回 false
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set 寫入快取，ttl 為 0 表示不過期
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete 刪除指定的鍵，不存在的鍵直接略過
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePrefix 刪除以 prefix 開頭的所有鍵
+	DeletePrefix(ctx context.Context, prefix string) error
+}
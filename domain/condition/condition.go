@@ -0,0 +1,248 @@
+// Package condition 解析並求值權限分配的條件運算式，不依賴其他套件。
+//
+// 運算式只能讀取屬性與字面值，不能呼叫函式或修改任何狀態，長度與巢狀深度皆有上限：
+//
+//	subject.region == request.region && env.hour >= 9 && env.hour < 18
+//	request.game in ['lol', 'aov'] || !(subject.level < 3)
+//
+// 屬性分為三個命名空間：subject（授權對象，來自用戶 metadata）、request（授權請求提供的屬性）
+// 與 env（求值時的環境，僅限 hour、minute、weekday、time、date）。
+// 字面值為單引號或雙引號字串、數字、true 與 false。
+// 比較的兩側皆為數字時以數值比較，否則以字串比較，因此 "09:30" 這類固定格式的時間可直接比較先後。
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 運算式的限制
+const (
+	// MaxLength 運算式的最大長度（位元組）
+	MaxLength = 1024
+	// maxDepth 括號與 ! 的最大巢狀深度
+	maxDepth = 32
+	// maxListSize in 列表的最大項目數
+	maxListSize = 64
+)
+
+// 屬性的命名空間
+const (
+	NamespaceSubject = "subject"
+	NamespaceRequest = "request"
+	NamespaceEnv     = "env"
+)
+
+var (
+	// ErrInvalidExpression 運算式語法錯誤或使用了不支援的屬性
+	ErrInvalidExpression = errors.New("invalid condition expression")
+	// ErrMissingAttribute 求值時缺少運算式引用的屬性
+	ErrMissingAttribute = errors.New("missing condition attribute")
+)
+
+// envNames env 命名空間支援的屬性
+var envNames = map[string]bool{"hour": true, "minute": true, "weekday": true, "time": true, "date": true}
+
+// Attributes 求值使用的屬性，鍵為含命名空間的完整名稱，例如 subject.region
+type Attributes map[string]string
+
+// Expression 已驗證的條件運算式，可安全地重複求值
+type Expression struct {
+	source string
+	root   node
+}
+
+// Parse 解析並驗證條件運算式
+func Parse(source string) (*Expression, error) {
+	if len(source) > MaxLength {
+		return nil, fmt.Errorf("%w: longer than %d bytes", ErrInvalidExpression, MaxLength)
+	}
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.unexpected(tok)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// Validate 檢查條件運算式是否有效
+func Validate(source string) error {
+	_, err := Parse(source)
+	return err
+}
+
+// String 返回原始運算式
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate 以指定的屬性求值。&& 與 || 會短路，未求值的一側缺少屬性不視為錯誤
+func (e *Expression) Evaluate(attributes Attributes) (bool, error) {
+	return e.root.eval(attributes)
+}
+
+// EnvAttributes 返回指定時間的 env 屬性，時間採 t 所在的時區
+func EnvAttributes(t time.Time) Attributes {
+	return Attributes{
+		NamespaceEnv + ".hour":    strconv.Itoa(t.Hour()),
+		NamespaceEnv + ".minute":  strconv.Itoa(t.Minute()),
+		NamespaceEnv + ".weekday": strings.ToLower(t.Weekday().String()[:3]),
+		NamespaceEnv + ".time":    t.Format("15:04"),
+		NamespaceEnv + ".date":    t.Format("2006-01-02"),
+	}
+}
+
+// node 運算式的節點
+type node interface {
+	eval(attributes Attributes) (bool, error)
+}
+
+// operand 比較的一側，attribute 不為空時為屬性，否則為字面值
+type operand struct {
+	attribute string
+	literal   string
+}
+
+func (o operand) value(attributes Attributes) (string, error) {
+	if o.attribute == "" {
+		return o.literal, nil
+	}
+	value, ok := attributes[o.attribute]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrMissingAttribute, o.attribute)
+	}
+	return value, nil
+}
+
+type literalNode bool
+
+func (n literalNode) eval(Attributes) (bool, error) {
+	return bool(n), nil
+}
+
+type notNode struct {
+	inner node
+}
+
+// eval 無法求值時返回 false，避免否定缺少屬性的比較而使條件成立
+func (n notNode) eval(attributes Attributes) (bool, error) {
+	result, err := n.inner.eval(attributes)
+	if err != nil {
+		return false, err
+	}
+	return !result, nil
+}
+
+type logicalNode struct {
+	and         bool
+	left, right node
+}
+
+func (n logicalNode) eval(attributes Attributes) (bool, error) {
+	left, err := n.left.eval(attributes)
+	if err != nil {
+		return false, err
+	}
+	if left != n.and {
+		return left, nil
+	}
+	right, err := n.right.eval(attributes)
+	if err != nil {
+		return false, err
+	}
+	return right, nil
+}
+
+type compareNode struct {
+	op          string
+	left, right operand
+}
+
+func (n compareNode) eval(attributes Attributes) (bool, error) {
+	left, err := n.left.value(attributes)
+	if err != nil {
+		return false, err
+	}
+	right, err := n.right.value(attributes)
+	if err != nil {
+		return false, err
+	}
+
+	result := compare(left, right)
+	switch n.op {
+	case "==":
+		return result == 0, nil
+	case "!=":
+		return result != 0, nil
+	case "<":
+		return result < 0, nil
+	case "<=":
+		return result <= 0, nil
+	case ">":
+		return result > 0, nil
+	default:
+		return result >= 0, nil
+	}
+}
+
+type inNode struct {
+	left operand
+	list []string
+}
+
+func (n inNode) eval(attributes Attributes) (bool, error) {
+	left, err := n.left.value(attributes)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range n.list {
+		if compare(left, item) == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// compare 兩側皆為數字時以數值比較，否則以字串比較
+func compare(a, b string) int {
+	if isNumber(a) && isNumber(b) {
+		x, _ := strconv.ParseFloat(a, 64)
+		y, _ := strconv.ParseFloat(b, 64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// isNumber 是否為十進位數字，可帶負號與小數點。不接受 ParseFloat 額外支援的 Inf、NaN 與指數等寫法
+func isNumber(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	digits, dot := 0, false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] >= '0' && s[i] <= '9':
+			digits++
+		case s[i] == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return digits > 0 && digits <= 15
+}
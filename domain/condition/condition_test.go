@@ -0,0 +1,183 @@
+package condition
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	attributes := Attributes{
+		"subject.region": "tw",
+		"subject.level":  "10",
+		"request.region": "tw",
+		"request.game":   "aov",
+		"env.hour":       "9",
+		"env.time":       "09:30",
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"subject.region == request.region", true},
+		{"subject.region != request.region", false},
+		{"subject.region == 'jp'", false},
+		{`subject.region == "tw"`, true},
+		// 兩側皆為數字時以數值比較，"10" > "9"
+		{"subject.level > env.hour", true},
+		{"subject.level >= 10 && subject.level <= 10.0", true},
+		{"env.hour >= 9 && env.hour < 18", true},
+		{"env.time >= '09:00' && env.time < '18:00'", true},
+		{"request.game in ['lol', 'aov']", true},
+		{"request.game in ['lol']", false},
+		{"subject.level in [1, 10.0]", true},
+		{"!(request.game in ['lol'])", true},
+		{"!!true", true},
+		{"false || subject.region == 'tw'", true},
+		{"subject.region == 'jp' || subject.region == 'kr' && true", false},
+		{"(subject.region == 'jp' || subject.region == 'tw') && request.game == 'aov'", true},
+		{"true", true},
+		{"false", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			expression, err := Parse(tt.expression)
+			require.NoError(t, err)
+			got, err := expression.Evaluate(attributes)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.expression, expression.String())
+		})
+	}
+}
+
+func TestEvaluate_MissingAttribute(t *testing.T) {
+	expression, err := Parse("subject.region == request.region")
+	require.NoError(t, err)
+
+	_, err = expression.Evaluate(Attributes{"subject.region": "tw"})
+	assert.ErrorIs(t, err, ErrMissingAttribute)
+	assert.Contains(t, err.Error(), "request.region")
+
+	// 短路時未求值的一側缺少屬性不是錯誤
+	expression, err = Parse("subject.region == 'jp' && request.region == 'jp'")
+	require.NoError(t, err)
+	got, err := expression.Evaluate(Attributes{"subject.region": "tw"})
+	require.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestEvaluate_MissingAttributeNegated(t *testing.T) {
+	// 否定或組合無法求值的比較時結果仍為 false，不因缺少屬性而成立
+	for _, source := range []string{
+		"!(request.region != subject.region)",
+		"!!(request.region == subject.region)",
+		"subject.region == 'tw' && !(request.region != subject.region)",
+		"subject.region == 'jp' || !(request.region != subject.region)",
+	} {
+		t.Run(source, func(t *testing.T) {
+			expression, err := Parse(source)
+			require.NoError(t, err)
+
+			got, err := expression.Evaluate(Attributes{"subject.region": "tw"})
+
+			assert.ErrorIs(t, err, ErrMissingAttribute)
+			assert.False(t, got)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"subject.region",
+		"subject.region ==",
+		"subject.region = 'tw'",
+		"region == 'tw'",
+		"user.region == 'tw'",
+		"env.timezone == 'UTC'",
+		"subject.a.b == 'x'",
+		"subject. == 'x'",
+		"'tw'",
+		"subject.region == 'tw",
+		"subject.region == 'tw')",
+		"(subject.region == 'tw'",
+		"subject.region in []",
+		"subject.region in ['tw',]",
+		"subject.region in [subject.region]",
+		"subject.region in 'tw'",
+		"subject.level > 1.2.3",
+		"subject.level > 1e9",
+		"len(subject.region) > 1",
+		"subject.region == 'tw' ; true",
+		"true true",
+		strings.Repeat("!", maxDepth) + "true",
+		strings.Repeat("(", maxDepth) + "true" + strings.Repeat(")", maxDepth),
+		"subject.region in [" + strings.TrimSuffix(strings.Repeat("'x',", maxListSize+1), ",") + "]",
+		"subject.region == '" + strings.Repeat("x", MaxLength) + "'",
+	}
+
+	for _, source := range invalid {
+		t.Run(source, func(t *testing.T) {
+			_, err := Parse(source)
+			assert.ErrorIs(t, err, ErrInvalidExpression)
+		})
+	}
+
+	// 上限以內的巢狀深度可正常解析
+	_, err := Parse(strings.Repeat("!", maxDepth-1) + "true")
+	assert.NoError(t, err)
+}
+
+func TestEnvAttributes(t *testing.T) {
+	now := time.Date(2024, 3, 8, 7, 5, 0, 0, time.UTC)
+	assert.Equal(t, Attributes{
+		"env.hour":    "7",
+		"env.minute":  "5",
+		"env.weekday": "fri",
+		"env.time":    "07:05",
+		"env.date":    "2024-03-08",
+	}, EnvAttributes(now))
+}
+
+func FuzzParse(f *testing.F) {
+	seeds := []string{
+		"subject.region == request.region",
+		"env.hour >= 9 && env.hour < 18",
+		"request.game in ['lol', 'aov'] || !(subject.level < 3)",
+		"((true))",
+		"subject.level > -1.5",
+		"'a' == \"a\"",
+	}
+	for _, seed := range seeds {
+		f.Add(seed, "tw", "9")
+	}
+
+	f.Fuzz(func(t *testing.T, source, region, hour string) {
+		expression, err := Parse(source)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidExpression) {
+				t.Fatalf("Parse(%q) error %v does not wrap ErrInvalidExpression", source, err)
+			}
+			return
+		}
+
+		// 有效的運算式求值不會 panic，只可能因缺少屬性而失敗
+		attributes := Attributes{"subject.region": region, "request.region": region, "env.hour": hour}
+		first, err := expression.Evaluate(attributes)
+		if err != nil && !errors.Is(err, ErrMissingAttribute) {
+			t.Fatalf("Evaluate(%q) error %v", source, err)
+		}
+		// 求值不改變任何狀態，結果可重現
+		second, secondErr := expression.Evaluate(attributes)
+		if first != second || (err == nil) != (secondErr == nil) {
+			t.Fatalf("Evaluate(%q) is not deterministic", source)
+		}
+	})
+}
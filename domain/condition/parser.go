@@ -0,0 +1,273 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value string
+	pos   int
+}
+
+// operators 依長度由長至短排列，確保 <= 不會被拆成 < 與 =
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ","}
+
+// tokenize 將運算式拆成記號
+func tokenize(source string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(source[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated string at %d", ErrInvalidExpression, i)
+			}
+			value := source[i+1 : i+1+end]
+			tokens = append(tokens, token{kind: tokenString, text: source[i : i+end+2], value: value, pos: i})
+			i += end + 2
+		case isDigit(c) || (c == '-' && i+1 < len(source) && isDigit(source[i+1])):
+			start := i
+			i++
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			text := source[start:i]
+			if !isNumber(text) {
+				return nil, fmt.Errorf("%w: invalid number %q at %d", ErrInvalidExpression, text, start)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, value: text, pos: start})
+		case isIdentStart(c):
+			start := i
+			for i < len(source) && (isIdentStart(source[i]) || isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			text := source[start:i]
+			tokens = append(tokens, token{kind: tokenIdent, text: text, value: text, pos: start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(source[i:], op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("%w: unexpected character %q at %d", ErrInvalidExpression, c, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// parser 遞迴下降解析器，優先順序由低至高為 ||、&&、!、比較
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept 下一個記號為指定運算子時取出
+func (p *parser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) unexpected(tok token) error {
+	if tok.kind == tokenEOF {
+		return fmt.Errorf("%w: unexpected end of expression", ErrInvalidExpression)
+	}
+	return fmt.Errorf("%w: unexpected %q at %d", ErrInvalidExpression, tok.text, tok.pos)
+}
+
+func (p *parser) parseOr(depth int) (node, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (node, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary(depth int) (node, error) {
+	if depth >= maxDepth {
+		return nil, fmt.Errorf("%w: nested deeper than %d", ErrInvalidExpression, maxDepth)
+	}
+	if p.accept("!") {
+		inner, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner: inner}, nil
+	}
+	if p.accept("(") {
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.unexpected(p.peek())
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	start := p.peek()
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenOperator && isComparison(tok.text):
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{op: tok.text, left: left, right: right}, nil
+	case tok.kind == tokenIdent && tok.text == "in":
+		p.next()
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return inNode{left: left, list: list}, nil
+	}
+
+	// 單獨的 true 或 false 為常數，屬性必須搭配比較
+	if start.kind == tokenIdent && left.attribute == "" {
+		return literalNode(left.literal == "true"), nil
+	}
+	return nil, fmt.Errorf("%w: expected comparison after %q at %d", ErrInvalidExpression, start.text, start.pos)
+}
+
+func (p *parser) parseList() ([]string, error) {
+	if !p.accept("[") {
+		return nil, p.unexpected(p.peek())
+	}
+	list := []string{}
+	for {
+		tok := p.next()
+		if tok.kind != tokenString && tok.kind != tokenNumber {
+			return nil, p.unexpected(tok)
+		}
+		if len(list) == maxListSize {
+			return nil, fmt.Errorf("%w: list longer than %d items", ErrInvalidExpression, maxListSize)
+		}
+		list = append(list, tok.value)
+		if p.accept("]") {
+			return list, nil
+		}
+		if !p.accept(",") {
+			return nil, p.unexpected(p.peek())
+		}
+	}
+}
+
+// parseOperand 解析屬性或字面值，屬性必須屬於已知的命名空間
+func (p *parser) parseOperand() (operand, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString, tokenNumber:
+		return operand{literal: tok.value}, nil
+	case tokenIdent:
+		if tok.text == "true" || tok.text == "false" {
+			return operand{literal: tok.text}, nil
+		}
+		if err := validateAttribute(tok.text); err != nil {
+			return operand{}, fmt.Errorf("%w at %d", err, tok.pos)
+		}
+		return operand{attribute: tok.text}, nil
+	default:
+		return operand{}, p.unexpected(tok)
+	}
+}
+
+// validateAttribute 屬性名稱須為 命名空間.名稱，env 只允許固定的名稱
+func validateAttribute(name string) error {
+	namespace, field, ok := strings.Cut(name, ".")
+	if !ok || field == "" || strings.Contains(field, ".") {
+		return fmt.Errorf("%w: unknown attribute %q", ErrInvalidExpression, name)
+	}
+	switch namespace {
+	case NamespaceSubject, NamespaceRequest:
+		return nil
+	case NamespaceEnv:
+		if envNames[field] {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown attribute %q", ErrInvalidExpression, name)
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
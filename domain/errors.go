@@ -9,9 +9,229 @@ var (
 	// ErrInvalidUserID 無效的用戶ID
 	ErrInvalidUserID = errors.New("invalid user ID")
 
+	// ErrInvalidUserFilter 無效的用戶列表查詢條件
+	ErrInvalidUserFilter = errors.New("invalid user filter")
+
+	// ErrUserDisabled 用戶已被停用
+	ErrUserDisabled = errors.New("user disabled")
+
 	// ErrInvalidJwt 無效的 JWT
 	ErrInvalidJwt = errors.New("jwt invalid")
 
+	// ErrTokenMalformed 令牌格式錯誤或 claims 型別不符
+	ErrTokenMalformed = errors.New("token malformed")
+
+	// ErrTokenMissingClaims 令牌缺少必要的 claims
+	ErrTokenMissingClaims = errors.New("token missing required claims")
+
+	// ErrTokenUnknownKey 令牌的簽章金鑰未知或已退役
+	ErrTokenUnknownKey = errors.New("token signing key unknown or retired")
+
+	// ErrTokenAlgorithmNotAllowed 令牌的簽章演算法不在允許清單中，或與金鑰不符
+	ErrTokenAlgorithmNotAllowed = errors.New("token signing algorithm not allowed")
+
+	// ErrTokenSignatureInvalid 令牌簽章驗證失敗
+	ErrTokenSignatureInvalid = errors.New("token signature invalid")
+
+	// ErrTokenExpired 令牌已過期
+	ErrTokenExpired = errors.New("token expired")
+
+	// ErrTokenNotYetValid 令牌尚未生效（nbf 或 iat 晚於目前時間）
+	ErrTokenNotYetValid = errors.New("token not yet valid")
+
+	// ErrTokenInvalidIssuer 令牌的簽發者不符
+	ErrTokenInvalidIssuer = errors.New("token issuer invalid")
+
+	// ErrTokenInvalidAudience 令牌的受眾不符
+	ErrTokenInvalidAudience = errors.New("token audience invalid")
+
 	// ErrInternalServerError 內部錯誤
 	ErrInternalServerError = errors.New("internal server error")
+
+	// ErrInvalidDescription 描述過長
+	ErrInvalidDescription = errors.New("invalid description")
+
+	// ErrRoleNotFound 角色未找到
+	ErrRoleNotFound = errors.New("role not found")
+
+	// ErrInvalidRoleID 無效的角色ID
+	ErrInvalidRoleID = errors.New("invalid role ID")
+
+	// ErrInvalidRoleName 無效的角色名稱
+	ErrInvalidRoleName = errors.New("invalid role name")
+
+	// ErrRoleAlreadyExists 角色名稱已存在
+	ErrRoleAlreadyExists = errors.New("role already exists")
+
+	// ErrRoleInUse 角色仍被分配給用戶
+	ErrRoleInUse = errors.New("role is still assigned to users")
+
+	// ErrInvalidRoleParents 無效的父角色列表
+	ErrInvalidRoleParents = errors.New("invalid parent roles")
+
+	// ErrRoleHierarchyCycle 角色繼承關係形成循環
+	ErrRoleHierarchyCycle = errors.New("role hierarchy would contain a cycle")
+
+	// ErrPermissionNotFound 權限未找到
+	ErrPermissionNotFound = errors.New("permission not found")
+
+	// ErrInvalidPermissionID 無效的權限ID
+	ErrInvalidPermissionID = errors.New("invalid permission ID")
+
+	// ErrInvalidPermission 無效的資源或操作
+	ErrInvalidPermission = errors.New("invalid permission resource or action")
+
+	// ErrPermissionAlreadyExists 相同資源與操作的權限已存在
+	ErrPermissionAlreadyExists = errors.New("permission already exists")
+
+	// ErrRoleAlreadyAssigned 用戶已擁有該角色
+	ErrRoleAlreadyAssigned = errors.New("role already assigned to user")
+
+	// ErrRoleNotAssigned 用戶未擁有該角色
+	ErrRoleNotAssigned = errors.New("role not assigned to user")
+
+	// ErrInvalidValidityPeriod 角色分配的有效期間無效，valid_until 須晚於 valid_from 與目前時間
+	ErrInvalidValidityPeriod = errors.New("invalid validity period")
+
+	// ErrInvalidExpiringWindow 查詢即將過期分配的時間範圍無效
+	ErrInvalidExpiringWindow = errors.New("invalid expiring window")
+
+	// ErrInvalidEffect 權限分配的效果須為 allow 或 deny
+	ErrInvalidEffect = errors.New("invalid permission effect")
+
+	// ErrInvalidCondition 權限分配的條件運算式無效
+	ErrInvalidCondition = errors.New("invalid permission condition")
+
+	// ErrInvalidUserMetadata 用戶屬性的鍵或值無效
+	ErrInvalidUserMetadata = errors.New("invalid user metadata")
+
+	// ErrPermissionAlreadyAssigned 角色已擁有該權限
+	ErrPermissionAlreadyAssigned = errors.New("permission already assigned to role")
+
+	// ErrPermissionNotAssigned 角色未擁有該權限
+	ErrPermissionNotAssigned = errors.New("permission not assigned to role")
+
+	// ErrUserPermissionAlreadyAssigned 用戶已被直接分配該權限
+	ErrUserPermissionAlreadyAssigned = errors.New("permission already assigned to user")
+
+	// ErrUserPermissionNotAssigned 用戶未被直接分配該權限
+	ErrUserPermissionNotAssigned = errors.New("permission not assigned to user")
+
+	// ErrInvalidObjectID 無效的資源實例 ID
+	ErrInvalidObjectID = errors.New("invalid object id")
+
+	// ErrInvalidObjectResource 實例授權與擁有者的資源類型無效，不可含萬用字元
+	ErrInvalidObjectResource = errors.New("invalid object resource or action")
+
+	// ErrObjectGrantNotFound 實例授權不存在
+	ErrObjectGrantNotFound = errors.New("object grant not found")
+
+	// ErrObjectGrantAlreadyExists 用戶已擁有相同的實例授權
+	ErrObjectGrantAlreadyExists = errors.New("object grant already exists")
+
+	// ErrResourceOwnerNotFound 資源實例未登記擁有者
+	ErrResourceOwnerNotFound = errors.New("resource owner not found")
+
+	// ErrOwnerRuleNotFound 擁有者規則不存在
+	ErrOwnerRuleNotFound = errors.New("owner rule not found")
+
+	// ErrOwnerRuleAlreadyExists 擁有者規則已存在
+	ErrOwnerRuleAlreadyExists = errors.New("owner rule already exists")
+
+	// ErrInvalidTenantID 無效的租戶ID
+	ErrInvalidTenantID = errors.New("invalid tenant id")
+
+	// ErrInvalidTenantCode 無效的租戶代碼
+	ErrInvalidTenantCode = errors.New("invalid tenant code")
+
+	// ErrInvalidTenantName 無效的租戶名稱
+	ErrInvalidTenantName = errors.New("invalid tenant name")
+
+	// ErrTenantNotFound 租戶不存在
+	ErrTenantNotFound = errors.New("tenant not found")
+
+	// ErrTenantAlreadyExists 租戶代碼已存在
+	ErrTenantAlreadyExists = errors.New("tenant already exists")
+
+	// ErrNotTenantMember 用戶不是該租戶的成員
+	ErrNotTenantMember = errors.New("user is not a member of the tenant")
+
+	// ErrTenantMemberAlreadyExists 用戶已是該租戶的成員
+	ErrTenantMemberAlreadyExists = errors.New("user is already a member of the tenant")
+
+	// ErrTenantMismatch 令牌綁定的租戶與請求指定的租戶不同
+	ErrTenantMismatch = errors.New("token is scoped to another tenant")
+
+	// ErrAccessRequestNotFound 權限申請不存在
+	ErrAccessRequestNotFound = errors.New("access request not found")
+
+	// ErrInvalidAccessRequestID 無效的權限申請ID
+	ErrInvalidAccessRequestID = errors.New("invalid access request id")
+
+	// ErrInvalidAccessRequestDuration 申請時長須為正值且不超過上限
+	ErrInvalidAccessRequestDuration = errors.New("invalid access request duration")
+
+	// ErrInvalidAccessRequestReason 申請理由不可為空且不超過長度上限
+	ErrInvalidAccessRequestReason = errors.New("invalid access request reason")
+
+	// ErrInvalidAccessRequestFilter 無效的權限申請查詢條件
+	ErrInvalidAccessRequestFilter = errors.New("invalid access request filter")
+
+	// ErrAccessRequestNotPending 申請已不在待審核狀態，無法再變更
+	ErrAccessRequestNotPending = errors.New("access request is not pending")
+
+	// ErrAccessRequestExpired 申請已逾期未審核
+	ErrAccessRequestExpired = errors.New("access request expired")
+
+	// ErrAccessRequestAlreadyPending 相同角色與租戶已有待審核的申請
+	ErrAccessRequestAlreadyPending = errors.New("access request already pending")
+
+	// ErrNotAccessRequestApprover 用戶沒有審核權限申請的權限
+	ErrNotAccessRequestApprover = errors.New("user is not an access request approver")
+
+	// ErrAccessRequestRoleHeld 申請人在申請的租戶內（含全域分配）已擁有該角色，臨時分配無法縮短或延長既有的分配
+	ErrAccessRequestRoleHeld = errors.New("requester already holds the role")
+
+	// ErrAccessRequestEscalation 申請的角色包含審核者本身沒有的權限，審核者不可核准
+	ErrAccessRequestEscalation = errors.New("role grants permissions the reviewer does not hold")
+
+	// ErrSelfReview 審核者不可審核自己的申請
+	ErrSelfReview = errors.New("cannot review own access request")
+
+	// ErrNotAccessRequestRequester 只有申請人可以撤回申請
+	ErrNotAccessRequestRequester = errors.New("only the requester can cancel the access request")
+
+	// ErrAccessRequestForbidden 只有申請人與審核者可以查看申請
+	ErrAccessRequestForbidden = errors.New("not allowed to view the access request")
+
+	// ErrInvalidAuditFilter 無效的審計日誌查詢條件
+	ErrInvalidAuditFilter = errors.New("invalid audit log filter")
+
+	// ErrInvalidRefreshToken 無效或已撤銷的刷新令牌
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+	// ErrRefreshTokenExpired 刷新令牌已過期
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+
+	// ErrRefreshTokenReused 已輪替的刷新令牌被重複使用
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+
+	// ErrTokenRevoked 訪問令牌已被撤銷
+	ErrTokenRevoked = errors.New("token revoked")
+
+	// ErrSessionNotFound 會話不存在或已過期
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrSigningKeyNotFound 簽章金鑰未載入
+	ErrSigningKeyNotFound = errors.New("signing key not found")
+
+	// ErrSigningKeyInUse 目前用於簽發的金鑰不可退役
+	ErrSigningKeyInUse = errors.New("signing key is current")
+
+	// ErrInvalidRevokeRequest 批量撤銷的請求內容無效
+	ErrInvalidRevokeRequest = errors.New("invalid revoke request")
+	// ErrRevokeForbidden 沒有 token:revoke 權限時撤銷其他用戶的令牌
+	ErrRevokeForbidden = errors.New("not allowed to revoke tokens of other users")
+	// ErrInvalidAuthorizeRequest 授權的請求內容無效，例如缺少資源或操作、資源實例 ID 格式錯誤
+	ErrInvalidAuthorizeRequest = errors.New("invalid authorize request")
 )
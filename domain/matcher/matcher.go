@@ -0,0 +1,136 @@
+// Package matcher 比對權限規則與請求的資源、操作，不依賴其他套件。
+//
+// 資源以 "/" 分層，例如 game/123/config。規則中的 "*" 必須佔滿一個層級：
+// 位於中間時比對任一個層級，位於結尾時比對其下所有層級（至少一層），單獨的 "*" 比對所有資源。
+// 操作不分層，"*" 比對所有操作。請求中的 "*" 只做字面比對，不具萬用字元的意義。
+//
+// 多條規則同時符合時採 deny-overrides：只要有一條 deny 規則符合即拒絕，否則有 allow 規則符合才允許。
+package matcher
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// Wildcard 萬用字元
+	Wildcard = "*"
+	// Separator 資源的層級分隔字元
+	Separator = "/"
+)
+
+// 規則的效果
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// ErrInvalidPattern 規則的資源或操作格式無效
+var ErrInvalidPattern = errors.New("invalid permission pattern")
+
+// Rule 一條授權規則
+type Rule struct {
+	Resource string
+	Action   string
+	// Effect 為 allow 或 deny，空字串視為 allow
+	Effect string
+}
+
+// Decision 比對結果，Rule 為決定結果的規則，沒有規則符合時為 nil
+type Decision struct {
+	Allowed bool
+	Rule    *Rule
+	// Index Rule 在規則列表中的位置，沒有規則符合時為 -1
+	Index int
+}
+
+// Denies 規則是否為 deny
+func (r Rule) Denies() bool {
+	return r.Effect == EffectDeny
+}
+
+// Matches 檢查規則是否涵蓋指定的資源與操作
+func (r Rule) Matches(resource, action string) bool {
+	return MatchAction(r.Action, action) && MatchResource(r.Resource, resource)
+}
+
+// Decide 以 deny-overrides 判定多條規則的結果：第一條符合的 deny 規則優先，其次為第一條符合的 allow 規則
+func Decide(rules []Rule, resource, action string) Decision {
+	allowedBy := -1
+	for i := range rules {
+		if !rules[i].Matches(resource, action) {
+			continue
+		}
+		if rules[i].Denies() {
+			return Decision{Allowed: false, Rule: &rules[i], Index: i}
+		}
+		if allowedBy < 0 {
+			allowedBy = i
+		}
+	}
+	if allowedBy < 0 {
+		return Decision{Index: -1}
+	}
+	return Decision{Allowed: true, Rule: &rules[allowedBy], Index: allowedBy}
+}
+
+// MatchAction 檢查操作是否符合規則，"*" 比對所有非空的操作
+func MatchAction(pattern, action string) bool {
+	if pattern == Wildcard {
+		return action != ""
+	}
+	return pattern == action
+}
+
+// MatchResource 檢查資源是否符合規則，萬用字元只比對非空的層級
+func MatchResource(pattern, resource string) bool {
+	for {
+		patternSegment, patternRest, patternMore := strings.Cut(pattern, Separator)
+		resourceSegment, resourceRest, resourceMore := strings.Cut(resource, Separator)
+
+		if patternSegment == Wildcard {
+			if !patternMore {
+				// 結尾的萬用字元比對其下所有層級
+				return !hasEmptySegment(resource)
+			}
+			if resourceSegment == "" {
+				return false
+			}
+		} else if patternSegment != resourceSegment {
+			return false
+		}
+
+		if !patternMore || !resourceMore {
+			return patternMore == resourceMore
+		}
+		pattern, resource = patternRest, resourceRest
+	}
+}
+
+// ValidatePattern 檢查規則格式：資源的每個層級不可為空，"*" 必須佔滿一個層級；操作不可含 "/"
+func ValidatePattern(resource, action string) error {
+	if hasEmptySegment(resource) {
+		return ErrInvalidPattern
+	}
+	for _, segment := range strings.Split(resource, Separator) {
+		if segment != Wildcard && strings.Contains(segment, Wildcard) {
+			return ErrInvalidPattern
+		}
+	}
+
+	if action == "" || strings.Contains(action, Separator) {
+		return ErrInvalidPattern
+	}
+	if action != Wildcard && strings.Contains(action, Wildcard) {
+		return ErrInvalidPattern
+	}
+	return nil
+}
+
+// hasEmptySegment 是否為空字串或含有空的層級
+func hasEmptySegment(resource string) bool {
+	return resource == "" ||
+		strings.HasPrefix(resource, Separator) ||
+		strings.HasSuffix(resource, Separator) ||
+		strings.Contains(resource, Separator+Separator)
+}
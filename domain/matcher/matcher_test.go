@@ -0,0 +1,189 @@
+package matcher
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchResource(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		resource string
+		want     bool
+	}{
+		{"notice", "notice", true},
+		{"notice", "notices", false},
+		{"notice", "notice/1", false},
+		{"*", "notice", true},
+		{"*", "game/123/config", true},
+		{"*", "", false},
+		{"game/*", "game/123", true},
+		{"game/*", "game/123/config", true},
+		{"game/*", "game", false},
+		{"game/*", "game/", false},
+		{"game/*", "game//config", false},
+		{"game/*/config", "game/123/config", true},
+		{"game/*/config", "game/123/stats", false},
+		{"game/*/config", "game/123/config/x", false},
+		{"game/*/config", "game//config", false},
+		{"game/123", "game/*", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.resource, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchResource(tt.pattern, tt.resource))
+		})
+	}
+}
+
+func TestMatchAction(t *testing.T) {
+	assert.True(t, MatchAction("view", "view"))
+	assert.False(t, MatchAction("view", "edit"))
+	assert.True(t, MatchAction("*", "edit"))
+	assert.False(t, MatchAction("*", ""))
+	// 請求中的 "*" 只做字面比對
+	assert.False(t, MatchAction("view", "*"))
+}
+
+func TestDecide_DenyOverrides(t *testing.T) {
+	rules := []Rule{
+		{Resource: "stats", Action: "view"},
+		{Resource: "*", Action: "*", Effect: EffectAllow},
+		{Resource: "stats", Action: "export", Effect: EffectDeny},
+	}
+
+	decision := Decide(rules, "stats", "view")
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, &rules[0], decision.Rule)
+	assert.Equal(t, 0, decision.Index)
+
+	// deny 規則優先於排在前面且符合的 allow 規則
+	decision = Decide(rules, "stats", "export")
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, &rules[2], decision.Rule)
+	assert.Equal(t, 2, decision.Index)
+
+	decision = Decide(rules[:1], "notice", "view")
+	assert.False(t, decision.Allowed)
+	assert.Nil(t, decision.Rule)
+	assert.Equal(t, -1, decision.Index)
+}
+
+func TestValidatePattern(t *testing.T) {
+	valid := [][2]string{
+		{"notice", "view"},
+		{"notice", "*"},
+		{"*", "view"},
+		{"game/*", "config"},
+		{"game/*/config", "edit"},
+	}
+	for _, pattern := range valid {
+		assert.NoError(t, ValidatePattern(pattern[0], pattern[1]), pattern)
+	}
+
+	invalid := [][2]string{
+		{"", "view"},
+		{"notice", ""},
+		{"game/", "view"},
+		{"/game", "view"},
+		{"game//config", "view"},
+		{"game*", "view"},
+		{"game/1*", "view"},
+		{"notice", "pub*"},
+		{"notice", "view/all"},
+	}
+	for _, pattern := range invalid {
+		assert.ErrorIs(t, ValidatePattern(pattern[0], pattern[1]), ErrInvalidPattern, pattern)
+	}
+}
+
+// referenceResourcePattern 以正規表達式實作相同的比對規則，作為模糊測試的對照
+func referenceResourcePattern(pattern string) *regexp.Regexp {
+	segments := strings.Split(pattern, Separator)
+	var b strings.Builder
+	b.WriteString(`\A`)
+	for i, segment := range segments {
+		if i > 0 {
+			b.WriteString(regexp.QuoteMeta(Separator))
+		}
+		switch {
+		case segment == Wildcard && i == len(segments)-1:
+			b.WriteString(`[^/]+(?:/[^/]+)*`)
+		case segment == Wildcard:
+			b.WriteString(`[^/]+`)
+		default:
+			b.WriteString(regexp.QuoteMeta(segment))
+		}
+	}
+	b.WriteString(`\z`)
+	return regexp.MustCompile(b.String())
+}
+
+func FuzzMatchResource(f *testing.F) {
+	seeds := [][2]string{
+		{"game/*", "game/123/config"},
+		{"game/*/config", "game/1/config"},
+		{"*", "a/b/c"},
+		{"*", ""},
+		{"a//*", "a//b"},
+		{"game/*", "game//x"},
+		{"*/*", "a/"},
+		{"a/*b", "a/*b"},
+	}
+	for _, seed := range seeds {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, pattern, resource string) {
+		got := MatchResource(pattern, resource)
+
+		// 正規表達式無法處理非 UTF-8 的規則，此時只檢查其餘性質
+		if utf8.ValidString(pattern) {
+			if want := referenceResourcePattern(pattern).MatchString(resource); got != want {
+				t.Fatalf("MatchResource(%q, %q) = %v, reference = %v", pattern, resource, got, want)
+			}
+		}
+		// 任何字串都符合自己
+		if !MatchResource(resource, resource) {
+			t.Fatalf("MatchResource(%q, %q) = false", resource, resource)
+		}
+		// 不含萬用字元層級的規則只做完全比對
+		if !strings.Contains(Separator+pattern+Separator, Separator+Wildcard+Separator) && got != (pattern == resource) {
+			t.Fatalf("MatchResource(%q, %q) = %v without wildcard", pattern, resource, got)
+		}
+	})
+}
+
+func FuzzDecide(f *testing.F) {
+	f.Add("stats", "export", "stats", "*", "stats", "export")
+	f.Add("*", "*", "game/*", "config", "game/1/config", "config")
+	f.Add("notice", "view", "notice", "edit", "notice", "view")
+
+	f.Fuzz(func(t *testing.T, allowResource, allowAction, denyResource, denyAction, resource, action string) {
+		allow := Rule{Resource: allowResource, Action: allowAction, Effect: EffectAllow}
+		deny := Rule{Resource: denyResource, Action: denyAction, Effect: EffectDeny}
+
+		// 規則的順序不影響結果
+		first := Decide([]Rule{allow, deny}, resource, action)
+		second := Decide([]Rule{deny, allow}, resource, action)
+		if first.Allowed != second.Allowed {
+			t.Fatalf("decision depends on rule order: %v vs %v", first, second)
+		}
+
+		denied := deny.Matches(resource, action)
+		allowed := allow.Matches(resource, action)
+		if first.Allowed != (allowed && !denied) {
+			t.Fatalf("Decide = %v, allow matches %v, deny matches %v", first.Allowed, allowed, denied)
+		}
+		if (first.Rule == nil) != (!allowed && !denied) {
+			t.Fatalf("Decide rule = %v, allow matches %v, deny matches %v", first.Rule, allowed, denied)
+		}
+		if denied && !first.Rule.Denies() {
+			t.Fatalf("Decide rule = %v, want the deny rule", first.Rule)
+		}
+	})
+}
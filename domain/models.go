@@ -1,5 +1,14 @@
 package domain
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"rbac-service/domain/condition"
+	"rbac-service/domain/matcher"
+)
+
 // UserWithRoles 擴展用戶模型，包含角色
 // 原 User strcut by design，待修改
 type UserWithRoles struct {
@@ -10,16 +19,188 @@ type UserWithRoles struct {
 
 // Role 角色模型
 type Role struct {
-	ID          string       `json:"id"`
+	ID          int64        `json:"id"`
 	Name        string       `json:"name"`
 	Description string       `json:"description"`
-	Permissions []Permission `json:"permissions"`
+	Permissions []Permission `json:"permissions,omitempty" gorm:"many2many:role_permissions;"`
+	// Parents 直接繼承的父角色，只在查詢單一角色時載入
+	Parents   []Role    `json:"parents,omitempty" gorm:"many2many:role_parents;joinForeignKey:RoleID;joinReferences:ParentID"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// 權限分配的效果
+const (
+	EffectAllow = matcher.EffectAllow
+	EffectDeny  = matcher.EffectDeny
+)
+
 // Permission 權限模型
 type Permission struct {
-	ID          string `json:"id"`
-	Resource    string `json:"resource"`
-	Action      string `json:"action"`
-	Description string `json:"description"`
+	ID          int64     `json:"id"`
+	Resource    string    `json:"resource"`
+	Action      string    `json:"action"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Effect 經由分配取得時的效果（allow 或 deny），來自關聯表，直接查詢權限時為空
+	Effect string `json:"effect,omitempty" gorm:"->;-:migration"`
+	// Condition 經由角色分配取得時的條件運算式，來自關聯表，空字串表示無條件
+	Condition string `json:"condition,omitempty" gorm:"column:condition_expr;->;-:migration"`
+}
+
+// UserRole 用戶角色關聯
+type UserRole struct {
+	UserID int64 `json:"user_id" gorm:"primaryKey"`
+	RoleID int64 `json:"role_id" gorm:"primaryKey"`
+	// TenantID 分配所屬的租戶，GlobalTenantID 表示在所有租戶中皆生效
+	TenantID int64 `json:"tenant_id" gorm:"primaryKey"`
+	// ValidFrom 分配開始生效的時間，nil 表示立即生效
+	ValidFrom *time.Time `json:"valid_from,omitempty"`
+	// ValidUntil 分配失效的時間，nil 表示永久有效；過期後不再參與權限解析，並由背景工作清除
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ActiveAt 判斷分配在指定時間是否生效
+func (a UserRole) ActiveAt(t time.Time) bool {
+	if a.ValidFrom != nil && t.Before(*a.ValidFrom) {
+		return false
+	}
+	return a.ValidUntil == nil || t.Before(*a.ValidUntil)
+}
+
+// ValidateValidity 驗證分配的有效期間：valid_until 須晚於 valid_from 與 now
+func (a UserRole) ValidateValidity(now time.Time) error {
+	if a.ValidUntil == nil {
+		return nil
+	}
+	if !a.ValidUntil.After(now) {
+		return ErrInvalidValidityPeriod
+	}
+	if a.ValidFrom != nil && !a.ValidUntil.After(*a.ValidFrom) {
+		return ErrInvalidValidityPeriod
+	}
+	return nil
+}
+
+// RoleAssignment 用戶角色分配的明細，包含用戶名稱與角色名稱
+type RoleAssignment struct {
+	UserID     int64      `json:"user_id"`
+	Username   string     `json:"username"`
+	RoleID     int64      `json:"role_id"`
+	RoleName   string     `json:"role_name"`
+	TenantID   int64      `json:"tenant_id"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until"`
+}
+
+// RolePermission 角色權限關聯
+type RolePermission struct {
+	RoleID       int64  `json:"role_id" gorm:"primaryKey"`
+	PermissionID int64  `json:"permission_id" gorm:"primaryKey"`
+	Effect       string `json:"effect"`
+	// Condition 分配生效的條件運算式，語法見 condition 套件，空字串表示無條件
+	Condition string    `json:"condition" gorm:"column:condition_expr"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserPermission 直接分配給用戶的權限，不經由角色
+type UserPermission struct {
+	UserID       int64 `json:"user_id" gorm:"primaryKey"`
+	PermissionID int64 `json:"permission_id" gorm:"primaryKey"`
+	// TenantID 分配所屬的租戶，GlobalTenantID 表示在所有租戶中皆生效
+	TenantID  int64     `json:"tenant_id" gorm:"primaryKey"`
+	Effect    string    `json:"effect"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NormalizeEffect 驗證權限分配的效果，空字串視為 allow
+func NormalizeEffect(effect string) (string, error) {
+	switch effect = strings.ToLower(strings.TrimSpace(effect)); effect {
+	case "":
+		return EffectAllow, nil
+	case EffectAllow, EffectDeny:
+		return effect, nil
+	default:
+		return "", ErrInvalidEffect
+	}
+}
+
+// NormalizeCondition 驗證權限分配的條件運算式，空字串表示無條件
+func NormalizeCondition(expression string) (string, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return "", nil
+	}
+	if err := condition.Validate(expression); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidCondition, err)
+	}
+	return expression, nil
+}
+
+// RoleNames 取出角色名稱列表
+func RoleNames(roles []Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+// Matches 檢查權限是否涵蓋指定的資源與操作，支援萬用字元與分層資源，規則見 matcher 套件
+func (p Permission) Matches(resource, action string) bool {
+	return p.Rule().Matches(resource, action)
+}
+
+// Rule 轉換為比對用的授權規則，未經由分配取得的權限視為 allow
+func (p Permission) Rule() matcher.Rule {
+	effect := p.Effect
+	if effect == "" {
+		effect = EffectAllow
+	}
+	return matcher.Rule{Resource: p.Resource, Action: p.Action, Effect: effect}
+}
+
+// Denies 權限分配是否為 deny
+func (p Permission) Denies() bool {
+	return p.Effect == EffectDeny
+}
+
+// PermissionCheck 授權檢查的資源與操作，Attributes 為條件運算式 request 命名空間的屬性；
+// ResourceID 不為空時檢查單一資源實例，權限分配未允許時再依實例授權與擁有者規則判定
+type PermissionCheck struct {
+	Resource   string
+	Action     string
+	ResourceID string
+	Attributes map[string]string
+}
+
+// Key 返回 "resource:action" 格式的權限表示
+func (p Permission) Key() string {
+	return p.Resource + ":" + p.Action
+}
+
+// ParsePermissionKey 解析 "resource:action" 格式的權限表示
+func ParsePermissionKey(key string) (resource string, action string, err error) {
+	resource, action, found := strings.Cut(strings.TrimSpace(key), ":")
+	if !found || resource == "" || action == "" || strings.Contains(action, ":") {
+		return "", "", ErrInvalidPermission
+	}
+	return resource, action, nil
+}
+
+// RoleSeed 角色種子資料，對應 configs/permissions.json 的一筆設定
+type RoleSeed struct {
+	Role        string   `json:"role"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// SeedReport 種子資料同步結果
+type SeedReport struct {
+	Added     []string `json:"added"`
+	Changed   []string `json:"changed"`
+	Unchanged []string `json:"unchanged"`
+	Removed   []string `json:"removed"`
 }
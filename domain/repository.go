@@ -1,22 +1,213 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // BaseRepository 定義基礎倉儲方法
 type BaseRepository interface {
 	GetByID(ctx context.Context, id string) (*User, error)
 	GetByUsername(ctx context.Context, username string) (*User, error)
 	UpdateUser(ctx context.Context, username string, updateFields map[string]interface{}) error
-	DeleteUserJwt(ctx context.Context, jwt string) error
 	CreateUser(ctx context.Context, user *User) (*User, error)
-	DeleteUser(ctx context.Context, username string) error
+	// DeleteUser 刪除用戶及其關聯資料，並於同一交易寫入用戶層級的撤銷記錄 revocation、
+	// 將用戶待審核的權限申請標記為 expired
+	DeleteUser(ctx context.Context, username string, revocation *UserTokenRevocation) error
 }
 
 type UserRepository interface {
 	BaseRepository
-	// 可以添加特定於用戶的額外方法
+	// List 依條件查詢用戶並返回符合條件的總筆數，Limit 為實際查詢筆數
+	List(ctx context.Context, filter UserListFilter) ([]User, int64, error)
 }
 
 type AuthRepository interface {
 	BaseRepository
+	// GetRolesByUserID 獲取用戶在租戶內生效的角色：全域分配加上該租戶的分配，
+	// tenantID 為 GlobalTenantID 時只返回全域分配
+	GetRolesByUserID(ctx context.Context, userID, tenantID int64) ([]Role, error)
+	// NextRoleAssignmentChange 返回用戶在租戶內晚於 now 的最早一個分配生效或過期時間，
+	// 沒有時返回 nil；GetRolesByUserID 的結果在此之前不會改變，供快取決定有效期限
+	NextRoleAssignmentChange(ctx context.Context, userID, tenantID int64, now time.Time) (*time.Time, error)
+	// GetPermissionsByRoleIDs 獲取多個角色擁有的權限及其效果，相同的權限與效果只返回一筆
+	GetPermissionsByRoleIDs(ctx context.Context, roleIDs []int64) ([]Permission, error)
+	// GetPermissionsByUserID 獲取用戶在租戶內生效的直接分配權限及其效果：全域分配加上該租戶的分配，
+	// tenantID 為 GlobalTenantID 時只返回全域分配
+	GetPermissionsByUserID(ctx context.Context, userID, tenantID int64) ([]Permission, error)
+	// ListRoleParents 列出所有角色繼承關聯
+	ListRoleParents(ctx context.Context) ([]RoleParent, error)
+	// ListObjectGrants 獲取用戶在租戶內對某類資源的實例授權（全域加上該租戶的授權），
+	// objectID 不為空時只返回該實例的授權
+	ListObjectGrants(ctx context.Context, userID, tenantID int64, resource, objectID string) ([]ObjectGrant, error)
+	// GetResourceOwner 獲取資源實例在租戶內的擁有者，租戶內有登記時以其為準，否則使用全域登記；
+	// 皆未登記時返回 ErrResourceOwnerNotFound
+	GetResourceOwner(ctx context.Context, tenantID int64, resource, objectID string) (*ResourceOwner, error)
+	// ListOwnedObjectIDs 獲取用戶在租戶內擁有的某類資源實例 ID，判定方式與 GetResourceOwner 相同
+	ListOwnedObjectIDs(ctx context.Context, ownerID, tenantID int64, resource string) ([]string, error)
+	// ListOwnerRules 列出所有擁有者規則
+	ListOwnerRules(ctx context.Context) ([]OwnerRule, error)
+	// GetTenantByCode 以代碼獲取租戶，不存在時返回 ErrTenantNotFound
+	GetTenantByCode(ctx context.Context, code string) (*Tenant, error)
+	// IsTenantMember 檢查用戶是否為租戶成員
+	IsTenantMember(ctx context.Context, tenantID, userID int64) (bool, error)
+}
+
+// RoleRepository 角色倉儲
+type RoleRepository interface {
+	GetRoleByID(ctx context.Context, id int64) (*Role, error)
+	GetRoleByName(ctx context.Context, name string) (*Role, error)
+	ListRoles(ctx context.Context) ([]Role, error)
+	CreateRole(ctx context.Context, role *Role) (*Role, error)
+	UpdateRole(ctx context.Context, id int64, updateFields map[string]interface{}) error
+	// DeleteRole 刪除角色，同時移除其用戶分配、權限分配與繼承關聯
+	DeleteRole(ctx context.Context, id int64) error
+	// CountRoleUsers 統計被分配該角色的用戶數
+	CountRoleUsers(ctx context.Context, id int64) (int64, error)
+	// AssignRoleToUser 依 assignment 的租戶與有效期間為用戶分配角色，TenantID 為 GlobalTenantID 時為全域分配；
+	// 已過期但尚未清除的相同分配會被取代
+	AssignRoleToUser(ctx context.Context, assignment *UserRole) error
+	// RemoveRoleFromUser 移除用戶在租戶內的角色分配，不影響其他租戶與全域分配
+	RemoveRoleFromUser(ctx context.Context, userID, roleID, tenantID int64) error
+	// ListExpiringUserRoles 列出在 (now, before] 期間內過期的角色分配，依過期時間排序
+	ListExpiringUserRoles(ctx context.Context, now, before time.Time) ([]RoleAssignment, error)
+	// DeleteExpiredUserRoles 清除在 now 之前已過期的角色分配，返回被清除的分配
+	DeleteExpiredUserRoles(ctx context.Context, now time.Time) ([]UserRole, error)
+	// AssignPermissionToRole 以指定的效果（allow 或 deny）與條件運算式為角色分配權限，condition 為空表示無條件
+	AssignPermissionToRole(ctx context.Context, roleID, permissionID int64, effect, condition string) error
+	RemovePermissionFromRole(ctx context.Context, roleID, permissionID int64) error
+	// AssignPermissionToUser 依 assignment 的租戶與效果直接為用戶分配權限，TenantID 為 GlobalTenantID 時為全域分配
+	AssignPermissionToUser(ctx context.Context, assignment *UserPermission) error
+	// RemovePermissionFromUser 移除用戶在租戶內的直接權限分配，不影響其他租戶與全域分配
+	RemovePermissionFromUser(ctx context.Context, userID, permissionID, tenantID int64) error
+	// ListRoleParents 列出所有角色繼承關聯
+	ListRoleParents(ctx context.Context) ([]RoleParent, error)
+	// SetRoleParents 以 parentIDs 取代角色的直接父角色，形成循環時返回 ErrRoleHierarchyCycle
+	SetRoleParents(ctx context.Context, roleID int64, parentIDs []int64) error
+}
+
+// ObjectRepository 資源實例授權、擁有者與擁有者規則的管理
+type ObjectRepository interface {
+	// ListUserObjectGrants 列出用戶的所有實例授權
+	ListUserObjectGrants(ctx context.Context, userID int64) ([]ObjectGrant, error)
+	// CreateObjectGrant 新增實例授權，相同的用戶、租戶、資源、實例與操作已存在時返回 ErrObjectGrantAlreadyExists
+	CreateObjectGrant(ctx context.Context, grant *ObjectGrant) error
+	// DeleteObjectGrant 刪除用戶的實例授權，不存在時返回 ErrObjectGrantNotFound
+	DeleteObjectGrant(ctx context.Context, userID, grantID int64) error
+	// GetResourceOwner 獲取資源實例在指定租戶（或全域）的擁有者登記，不回退至全域登記
+	GetResourceOwner(ctx context.Context, tenantID int64, resource, objectID string) (*ResourceOwner, error)
+	// SetResourceOwner 登記或變更資源實例在租戶內的擁有者
+	SetResourceOwner(ctx context.Context, owner *ResourceOwner) error
+	// DeleteResourceOwner 移除資源實例在租戶內的擁有者，未登記時返回 ErrResourceOwnerNotFound
+	DeleteResourceOwner(ctx context.Context, tenantID int64, resource, objectID string) error
+	ListOwnerRules(ctx context.Context) ([]OwnerRule, error)
+	// CreateOwnerRule 新增擁有者規則，相同的資源與操作已存在時返回 ErrOwnerRuleAlreadyExists
+	CreateOwnerRule(ctx context.Context, rule *OwnerRule) error
+	// DeleteOwnerRule 刪除擁有者規則，不存在時返回 ErrOwnerRuleNotFound
+	DeleteOwnerRule(ctx context.Context, id int64) error
+}
+
+// TenantRepository 租戶與成員的管理
+type TenantRepository interface {
+	ListTenants(ctx context.Context) ([]Tenant, error)
+	GetTenantByID(ctx context.Context, id int64) (*Tenant, error)
+	// CreateTenant 創建租戶，代碼已存在時返回 ErrTenantAlreadyExists
+	CreateTenant(ctx context.Context, tenant *Tenant) error
+	UpdateTenant(ctx context.Context, id int64, updateFields map[string]interface{}) error
+	// DeleteTenant 刪除租戶，同時移除其成員與租戶內的角色分配、直接權限分配、實例授權與擁有者登記
+	DeleteTenant(ctx context.Context, id int64) error
+	// ListTenantMembers 列出租戶的成員，依用戶 ID 排序
+	ListTenantMembers(ctx context.Context, tenantID int64) ([]User, error)
+	// AddTenantMember 加入成員，已是成員時返回 ErrTenantMemberAlreadyExists
+	AddTenantMember(ctx context.Context, tenantID, userID int64) error
+	// RemoveTenantMember 移除成員及其在該租戶內的角色分配、直接權限分配與實例授權，不是成員時返回 ErrNotTenantMember
+	RemoveTenantMember(ctx context.Context, tenantID, userID int64) error
+}
+
+// AccessRequestRepository 權限申請倉儲
+type AccessRequestRepository interface {
+	CreateAccessRequest(ctx context.Context, request *AccessRequest) error
+	// GetAccessRequestByID 獲取權限申請，不存在時返回 ErrAccessRequestNotFound
+	GetAccessRequestByID(ctx context.Context, id int64) (*AccessRequest, error)
+	// ListAccessRequests 依條件列出權限申請，新的在前
+	ListAccessRequests(ctx context.Context, filter AccessRequestFilter) ([]AccessRequest, error)
+	// HasPendingAccessRequest 檢查用戶對同一租戶內的角色是否已有待審核的申請
+	HasPendingAccessRequest(ctx context.Context, requesterID, roleID, tenantID int64) (bool, error)
+	// UpdateAccessRequestStatus 在申請仍為待審核時寫入其狀態與審核結果，已被其他請求變更時返回 ErrAccessRequestNotPending
+	UpdateAccessRequestStatus(ctx context.Context, request *AccessRequest) error
+	// ApproveAccessRequest 在同一交易中寫入核准結果並建立臨時角色分配
+	ApproveAccessRequest(ctx context.Context, request *AccessRequest, assignment *UserRole) error
+	// ExpirePendingAccessRequests 將在 now 之前逾期未審核的申請標記為 expired，返回被標記的申請
+	ExpirePendingAccessRequests(ctx context.Context, now time.Time) ([]AccessRequest, error)
+}
+
+// PermissionRepository 權限倉儲
+type PermissionRepository interface {
+	GetPermissionByID(ctx context.Context, id int64) (*Permission, error)
+	GetPermissionByResourceAction(ctx context.Context, resource, action string) (*Permission, error)
+	// ListPermissions 列出權限，resourcePrefix 不為空時只返回資源以其開頭的權限
+	ListPermissions(ctx context.Context, resourcePrefix string) ([]Permission, error)
+	CreatePermission(ctx context.Context, permission *Permission) (*Permission, error)
+	UpdatePermission(ctx context.Context, id int64, updateFields map[string]interface{}) error
+	// DeletePermission 刪除權限，同時移除其角色與用戶分配
+	DeletePermission(ctx context.Context, id int64) error
+}
+
+// AuditLogRepository 審計日誌倉儲
+type AuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, log *AuditLog) error
+	// ListAuditLogs 依 ID 由新到舊返回符合條件的記錄
+	ListAuditLogs(ctx context.Context, filter AuditLogFilter) ([]AuditLog, error)
+}
+
+// RefreshTokenRepository 刷新令牌倉儲
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	// RotateRefreshToken 將尚未輪替且未撤銷的令牌標記為已輪替，返回是否標記成功
+	RotateRefreshToken(ctx context.Context, id int64) (bool, error)
+	// RevokeRefreshTokenFamily 撤銷同一 family 下所有尚未撤銷的令牌
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	// RevokeUserRefreshTokens 撤銷用戶所有尚未撤銷的令牌
+	RevokeUserRefreshTokens(ctx context.Context, userID int64) error
+}
+
+// SigningKeyRepository JWT 簽章金鑰輪替狀態倉儲
+type SigningKeyRepository interface {
+	// ListSigningKeys 列出所有記錄過狀態的金鑰
+	ListSigningKeys(ctx context.Context) ([]SigningKey, error)
+	// PromoteSigningKey 將金鑰設為 current，原本的 current 降為 active
+	PromoteSigningKey(ctx context.Context, kid string) error
+	// RetireSigningKey 將金鑰設為 retired 並記錄退役時間
+	RetireSigningKey(ctx context.Context, kid string, retireAt time.Time) error
+}
+
+// TokenRevocationRepository 訪問令牌撤銷記錄倉儲
+type TokenRevocationRepository interface {
+	// RevokeToken 記錄已撤銷的 jti，重複撤銷不視為錯誤
+	RevokeToken(ctx context.Context, token *RevokedToken) error
+	// RevokeUserTokens 寫入或更新用戶層級的撤銷時間點
+	RevokeUserTokens(ctx context.Context, revocation *UserTokenRevocation) error
+	// IsTokenRevoked 檢查 jti 是否已撤銷，或其簽發時間是否早於用戶的撤銷時間點
+	IsTokenRevoked(ctx context.Context, jti string, username string, issuedAt time.Time) (bool, error)
+	// RevokeSessions 記錄已終止的會話，其訪問令牌在 expiresAt 前一律失效
+	RevokeSessions(ctx context.Context, sessionIDs []string, expiresAt time.Time) error
+	// ListActiveRevocations 列出尚未過期的令牌、用戶與會話撤銷記錄
+	ListActiveRevocations(ctx context.Context, now time.Time) (*ActiveRevocations, error)
+	// DeleteExpiredRevocations 清除已過期的撤銷記錄，返回清除筆數
+	DeleteExpiredRevocations(ctx context.Context, now time.Time) (int64, error)
+}
+
+// SessionRepository 登入會話倉儲
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *Session) error
+	GetSessionByID(ctx context.Context, id string) (*Session, error)
+	// ListUserSessions 返回用戶尚未過期的會話，依最後活動時間由新到舊排序
+	ListUserSessions(ctx context.Context, userID int64) ([]Session, error)
+	// TouchSession 更新會話的最後活動時間與過期時間
+	TouchSession(ctx context.Context, id string, lastSeenAt time.Time, expiresAt time.Time) error
+	DeleteSession(ctx context.Context, id string) error
+	DeleteUserSessions(ctx context.Context, userID int64) error
+	// DeleteExpiredSessions 清除已過期的會話，返回清除筆數
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error)
 }
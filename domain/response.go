@@ -9,15 +9,70 @@ type Response struct {
 
 // LoginResponse 登錄響應
 type LoginResponse struct {
-	User  string `json:"user"`
-	Token string `json:"token"`
+	User         string `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
 }
 
-// AuthorizeResponse 授權響應
+// AuthorizeResponse 授權響應，Rule 為決定結果的權限規則，沒有規則符合時省略；
+// Reason 說明因條件不成立而未獲授權的原因
 type AuthorizeResponse struct {
-	Authorized   bool  `json:"authorized"`
-	NeedsRefresh bool  `json:"needsRefresh,omitempty"`
-	ExpiresIn    int64 `json:"expiresIn,omitempty"`
+	Authorized   bool          `json:"authorized"`
+	Rule         *DecidingRule `json:"rule,omitempty"`
+	Reason       string        `json:"reason,omitempty"`
+	NeedsRefresh bool          `json:"needsRefresh,omitempty"`
+	ExpiresIn    int64         `json:"expiresIn,omitempty"`
+}
+
+// DecidingRule 決定授權結果的規則。來自權限分配時 Source 省略並帶 PermissionID；
+// 來自實例授權或擁有者規則時 Source 為 object_grant 或 owner，RuleID 為該授權或規則的 ID
+type DecidingRule struct {
+	PermissionID int64  `json:"permission_id,omitempty"`
+	Source       string `json:"source,omitempty" example:"owner"`
+	RuleID       int64  `json:"rule_id,omitempty"`
+	Permission   string `json:"permission" example:"stats:export"`
+	Effect       string `json:"effect" example:"deny"`
+	// Condition 該分配的條件運算式，無條件時省略
+	Condition string `json:"condition,omitempty" example:"subject.region == request.region"`
+}
+
+// AuthorizeDecision 單一資源與操作的判定結果，Rule 為決定結果的權限規則，沒有規則符合時為 nil；
+// 沒有規則符合且有 allow 分配因條件不成立而略過時，Reason 說明第一個不成立的條件
+type AuthorizeDecision struct {
+	Resource   string        `json:"resource"`
+	ResourceID string        `json:"resource_id,omitempty"`
+	Action     string        `json:"action"`
+	Allowed    bool          `json:"allowed"`
+	Rule       *DecidingRule `json:"rule,omitempty"`
+	Reason     string        `json:"reason,omitempty" example:"condition of permission users:edit not satisfied: subject.region == request.region"`
+}
+
+// BatchAuthorizeResult 批量授權結果，Decisions 與請求的順序一致
+type BatchAuthorizeResult struct {
+	Decisions []AuthorizeDecision `json:"decisions"`
+	// Permissions 用戶完整的權限列表（"resource:action"），僅在請求時返回，供客戶端快取
+	Permissions []string `json:"permissions,omitempty"`
+	// DeniedPermissions 用戶被明確拒絕的權限，優先於 Permissions，與其一併返回
+	DeniedPermissions []string `json:"denied_permissions,omitempty"`
+	// ConditionalPermissions 帶條件的分配，結果取決於請求屬性，不列入上述兩個列表，須逐次向服務端授權
+	ConditionalPermissions []string `json:"conditional_permissions,omitempty"`
+	NeedsRefresh           bool     `json:"needsRefresh,omitempty"`
+	ExpiresIn              int64    `json:"expiresIn,omitempty"`
+}
+
+// PermissionSource 有效權限的來源角色
+type PermissionSource struct {
+	RoleID   int64  `json:"role_id"`
+	RoleName string `json:"role_name"`
+	// Path 從查詢的角色到來源角色的最短繼承路徑（角色名稱），直接擁有時只有角色本身
+	Path []string `json:"path"`
+}
+
+// EffectivePermission 角色的有效權限，Sources 列出所有以相同效果直接擁有該權限的角色
+type EffectivePermission struct {
+	Permission
+	Sources []PermissionSource `json:"sources"`
 }
 
 // NewResponse 創建一個成功的響應
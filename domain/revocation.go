@@ -0,0 +1,19 @@
+package domain
+
+import "context"
+
+// 撤銷記錄在實例間的傳播方式
+const (
+	// RevocationPropagationPoll 各實例定期查詢資料庫
+	RevocationPropagationPoll = "poll"
+	// RevocationPropagationRedis 透過 Redis pub/sub 即時通知，並保留定期查詢作為補償
+	RevocationPropagationRedis = "redis"
+)
+
+// RevocationBus 在實例間傳播撤銷記錄，stateless 驗證模式據此更新記憶體撤銷清單
+type RevocationBus interface {
+	// Publish 通知其他實例新增的撤銷記錄，記錄須已寫入資料庫
+	Publish(ctx context.Context, revocations *ActiveRevocations) error
+	// Subscribe 開始接收撤銷記錄並交由 handler 處理，訂閱建立後即返回，ctx 結束時停止接收
+	Subscribe(ctx context.Context, handler func(*ActiveRevocations)) error
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// RoleParent 角色繼承關聯，RoleID 繼承 ParentID 的所有權限
+type RoleParent struct {
+	RoleID    int64     `json:"role_id" gorm:"primaryKey"`
+	ParentID  int64     `json:"parent_id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RoleHierarchy 角色繼承關係，鍵為角色 ID，值為其直接父角色 ID（已排序）
+type RoleHierarchy map[int64][]int64
+
+// NewRoleHierarchy 由繼承關聯建立角色繼承關係
+func NewRoleHierarchy(edges []RoleParent) RoleHierarchy {
+	hierarchy := RoleHierarchy{}
+	for _, edge := range edges {
+		hierarchy[edge.RoleID] = append(hierarchy[edge.RoleID], edge.ParentID)
+	}
+	for _, parents := range hierarchy {
+		sort.Slice(parents, func(i, j int) bool { return parents[i] < parents[j] })
+	}
+	return hierarchy
+}
+
+// Expand 返回角色本身及其所有祖先角色的 ID，依廣度優先順序且不重複
+func (h RoleHierarchy) Expand(roleIDs []int64) []int64 {
+	visited := make(map[int64]bool, len(roleIDs))
+	expanded := make([]int64, 0, len(roleIDs))
+	queue := append([]int64(nil), roleIDs...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		expanded = append(expanded, id)
+		queue = append(queue, h[id]...)
+	}
+	return expanded
+}
+
+// Paths 返回從角色到其本身與每個祖先角色的最短繼承路徑，依廣度優先順序排列。
+// 每條路徑以 roleID 開頭、以該祖先結尾
+func (h RoleHierarchy) Paths(roleID int64) [][]int64 {
+	visited := map[int64]bool{roleID: true}
+	paths := [][]int64{{roleID}}
+	for i := 0; i < len(paths); i++ {
+		path := paths[i]
+		for _, parentID := range h[path[len(path)-1]] {
+			if visited[parentID] {
+				continue
+			}
+			visited[parentID] = true
+			next := append(append(make([]int64, 0, len(path)+1), path...), parentID)
+			paths = append(paths, next)
+		}
+	}
+	return paths
+}
+
+// WouldCycle 檢查將 roleID 的父角色設為 parentIDs 後是否形成循環。
+// 既有的繼承關係不含循環，因此只需確認 roleID 不是任一新父角色本身或其祖先
+func (h RoleHierarchy) WouldCycle(roleID int64, parentIDs []int64) bool {
+	for _, id := range h.Expand(parentIDs) {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
+}
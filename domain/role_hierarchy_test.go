@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleHierarchy_Expand(t *testing.T) {
+	// admin(1) -> operator(2) -> cs(3)，auditor(4) -> cs(3)
+	hierarchy := NewRoleHierarchy([]RoleParent{
+		{RoleID: 1, ParentID: 2},
+		{RoleID: 2, ParentID: 3},
+		{RoleID: 4, ParentID: 3},
+	})
+
+	assert.Equal(t, []int64{1, 2, 3}, hierarchy.Expand([]int64{1}))
+	assert.Equal(t, []int64{4, 1, 3, 2}, hierarchy.Expand([]int64{4, 1}))
+	assert.Equal(t, []int64{5}, hierarchy.Expand([]int64{5}))
+	assert.Equal(t, [][]int64{{1}, {1, 2}, {1, 2, 3}}, hierarchy.Paths(1))
+}
+
+func TestRoleHierarchy_WouldCycle(t *testing.T) {
+	hierarchy := NewRoleHierarchy([]RoleParent{
+		{RoleID: 1, ParentID: 2},
+		{RoleID: 2, ParentID: 3},
+	})
+
+	tests := []struct {
+		name      string
+		roleID    int64
+		parentIDs []int64
+		want      bool
+	}{
+		{name: "繼承自身", roleID: 1, parentIDs: []int64{1}, want: true},
+		{name: "直接循環", roleID: 2, parentIDs: []int64{1}, want: true},
+		{name: "經由祖先循環", roleID: 3, parentIDs: []int64{1}, want: true},
+		{name: "菱形繼承", roleID: 1, parentIDs: []int64{2, 3}, want: false},
+		{name: "取代既有的父角色", roleID: 2, parentIDs: []int64{4}, want: false},
+		{name: "清除父角色", roleID: 1, parentIDs: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hierarchy.WouldCycle(tt.roleID, tt.parentIDs))
+		})
+	}
+}
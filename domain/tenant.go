@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+const (
+	// TenantHeader 指定請求所屬租戶的標頭，值為租戶代碼；令牌已綁定租戶時只能省略或與其相同
+	TenantHeader = "X-Tenant"
+	// GlobalTenantID 全域角色分配的 tenant_id，在所有租戶中皆生效
+	GlobalTenantID int64 = 0
+	// MaxTenantNameLength 租戶名稱最大長度，與 tenants.name 欄位一致
+	MaxTenantNameLength = 64
+)
+
+// tenantCodePattern 租戶代碼為小寫英數字、"-" 與 "_"，用於標頭與令牌
+var tenantCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Tenant 租戶，通常為一款遊戲或一個專案。Code 建立後不可變更，令牌與標頭以其識別租戶
+type Tenant struct {
+	ID          int64     `json:"id"`
+	Code        string    `json:"code" example:"game-a"`
+	Name        string    `json:"name" example:"Game A"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TenantMember 租戶成員，用戶須為成員才能在該租戶被分配角色或以其身分請求
+type TenantMember struct {
+	TenantID  int64     `json:"tenant_id" gorm:"primaryKey"`
+	UserID    int64     `json:"user_id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TenantRoles 用戶在單一租戶內被分配的角色
+type TenantRoles struct {
+	TenantID int64  `json:"tenant_id"`
+	Tenant   string `json:"tenant" example:"game-a"`
+	Roles    []Role `json:"roles"`
+}
+
+// ScopeID 返回角色解析使用的 tenant_id，未指定租戶（nil）時為 GlobalTenantID
+func (t *Tenant) ScopeID() int64 {
+	if t == nil {
+		return GlobalTenantID
+	}
+	return t.ID
+}
+
+// ValidateTenantCode 檢查租戶代碼格式
+func ValidateTenantCode(code string) error {
+	if !tenantCodePattern.MatchString(code) {
+		return ErrInvalidTenantCode
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package domain
+
+import "time"
+
+// RefreshToken 刷新令牌，只保存雜湊值。
+// 同一次登入後續輪替出的令牌共用 FamilyID（即會話 ID），用於偵測重複使用時整組撤銷
+type RefreshToken struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	FamilyID  string     `json:"family_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RevokedToken 已撤銷的訪問令牌，以 jti 識別，令牌過期後即可清除
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"column:jti;primaryKey"`
+	Username  string    `json:"username"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserTokenRevocation 用戶層級的撤銷：簽發時間不晚於 RevokedBefore 的令牌一律失效。
+// 在此之前簽發的令牌最晚於 ExpiresAt 過期，之後記錄即可清除
+type UserTokenRevocation struct {
+	Username      string    `json:"username" gorm:"primaryKey"`
+	RevokedBefore time.Time `json:"revoked_before"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// RevokedSession 已終止的會話，其訪問令牌在 ExpiresAt 前仍可能被使用，供 stateless 驗證拒絕
+type RevokedSession struct {
+	SessionID string    `json:"session_id" gorm:"column:session_id;primaryKey"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ActiveRevocations 尚未過期的撤銷記錄，stateless 驗證模式定期載入至記憶體，
+// 也作為實例間傳播新增撤銷記錄的訊息內容
+type ActiveRevocations struct {
+	Tokens   []RevokedToken        `json:"tokens,omitempty"`
+	Users    []UserTokenRevocation `json:"users,omitempty"`
+	Sessions []RevokedSession      `json:"sessions,omitempty"`
+}
+
+// 訪問令牌的驗證模式
+const (
+	// ValidationModeStrict 每個請求都查詢資料庫確認撤銷狀態與會話
+	ValidationModeStrict = "strict"
+	// ValidationModeStateless 只驗證簽章與效期，撤銷狀態改查定期同步的記憶體清單
+	ValidationModeStateless = "stateless"
+)
+
+// BatchRevokeResult 批量撤銷結果
+type BatchRevokeResult struct {
+	Revoked []string             `json:"revoked"`
+	Failed  []BatchRevokeFailure `json:"failed"`
+}
+
+// BatchRevokeFailure 批量撤銷中失敗的項目
+type BatchRevokeFailure struct {
+	Target string `json:"target"`
+	Error  string `json:"error"`
+}
+
+// Session 用戶的一次登入，ID 同時作為該次登入刷新令牌的 FamilyID
+type Session struct {
+	ID        string `json:"id" gorm:"primaryKey"`
+	UserID    int64  `json:"user_id"`
+	UserAgent string `json:"user_agent"`
+	IP        string `json:"ip"`
+	// Tenant 登入時選擇的租戶代碼，以該會話簽發的令牌皆綁定此租戶，空字串表示未綁定
+	Tenant     string    `json:"tenant,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current 是否為發出請求的會話，僅用於列表回應
+	Current bool `json:"current" gorm:"-"`
+}
+
+// SessionClient 登入時記錄的客戶端資訊
+type SessionClient struct {
+	UserAgent string
+	IP        string
+}
+
+// 簽章金鑰狀態
+const (
+	// SigningKeyStatusCurrent 用於簽發新令牌，同一時間只有一把
+	SigningKeyStatusCurrent = "current"
+	// SigningKeyStatusActive 只用於驗證，通常是剛輪替下來或預先佈署的金鑰
+	SigningKeyStatusActive = "active"
+	// SigningKeyStatusRetired 已排定退役，RetireAt 之後以其簽發的令牌一律拒絕
+	SigningKeyStatusRetired = "retired"
+)
+
+// SigningKey JWT 簽章金鑰的輪替狀態，金鑰內容只存在設定檔中
+type SigningKey struct {
+	Kid       string     `json:"kid" gorm:"column:kid;primaryKey"`
+	Status    string     `json:"status"`
+	RetireAt  *time.Time `json:"retire_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -1,14 +1,152 @@
 package domain
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
 
-// User 領域模型
+// 用戶狀態
+const (
+	UserStatusActive   = "active"
+	UserStatusDisabled = "disabled"
+)
+
+// 用戶列表的匹配與排序方式
+const (
+	UsernameMatchPrefix   = "prefix"
+	UsernameMatchContains = "contains"
+
+	UserSortCreatedAt = "created_at"
+	UserSortUpdatedAt = "updated_at"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// User 領域模型，密碼雜湊不會輸出到 JSON
 type User struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	Password  string    `json:"password"`
-	Jwt       string    `json:"jwt"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Roles     []string  `json:"roles,omitempty" gorm:"type:json"`
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Password string `json:"-"`
+	Status   string `json:"status" gorm:"default:active"`
+	// Metadata 用戶屬性，供權限條件以 subject.<鍵> 引用
+	Metadata  UserMetadata `json:"metadata,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	// Roles 全域分配的角色，在所有租戶中皆生效
+	Roles []Role `json:"roles,omitempty" gorm:"-"`
+	// TenantRoles 依租戶分配的角色，只在該租戶內生效
+	TenantRoles []TenantRoles `json:"tenant_roles,omitempty" gorm:"-"`
+}
+
+// 用戶屬性的限制
+const (
+	MaxUserMetadataKeys        = 32
+	MaxUserMetadataValueLength = 256
+)
+
+// userMetadataKeyPattern 屬性鍵須能作為條件運算式中的屬性名稱
+var userMetadataKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+
+// UserMetadata 用戶屬性，以 JSON 儲存
+type UserMetadata map[string]string
+
+// GormDataType 讓 gorm 將屬性視為 JSON 欄位
+func (UserMetadata) GormDataType() string {
+	return "json"
+}
+
+// Value 實作 driver.Valuer，空屬性存為 NULL
+func (m UserMetadata) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(map[string]string(m))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 實作 sql.Scanner
+func (m *UserMetadata) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported user metadata type %T", value)
+	}
+	if len(data) == 0 {
+		*m = nil
+		return nil
+	}
+	return json.Unmarshal(data, (*map[string]string)(m))
+}
+
+// Validate 檢查屬性的數量、鍵的格式與值的長度。id 與 username 保留給用戶本身的欄位
+func (m UserMetadata) Validate() error {
+	if len(m) > MaxUserMetadataKeys {
+		return fmt.Errorf("%w: more than %d keys", ErrInvalidUserMetadata, MaxUserMetadataKeys)
+	}
+	for key, value := range m {
+		if !userMetadataKeyPattern.MatchString(key) || key == "id" || key == "username" {
+			return fmt.Errorf("%w: invalid key %q", ErrInvalidUserMetadata, key)
+		}
+		if len(value) > MaxUserMetadataValueLength {
+			return fmt.Errorf("%w: value of %q longer than %d bytes", ErrInvalidUserMetadata, key, MaxUserMetadataValueLength)
+		}
+	}
+	return nil
+}
+
+// SubjectAttributes 返回條件運算式 subject 命名空間的屬性，包含 id、username 與所有用戶屬性
+func (u *User) SubjectAttributes() map[string]string {
+	attributes := make(map[string]string, len(u.Metadata)+2)
+	for key, value := range u.Metadata {
+		attributes[key] = value
+	}
+	attributes["id"] = strconv.FormatInt(u.ID, 10)
+	attributes["username"] = u.Username
+	return attributes
+}
+
+// UserCursor 游標分頁的位置，為上一頁最後一筆的排序欄位值與 ID
+type UserCursor struct {
+	SortValue time.Time
+	ID        int64
+}
+
+// UserListFilter 用戶列表查詢條件，零值欄位不參與過濾
+type UserListFilter struct {
+	Username string
+	// UsernameMatch 為 prefix 或 contains
+	UsernameMatch string
+	RoleID        int64
+	// TenantID 只返回該租戶的成員
+	TenantID int64
+	Status   string
+	// SortBy 為 created_at 或 updated_at，SortOrder 為 asc 或 desc
+	SortBy    string
+	SortOrder string
+	// Offset 與 After 擇一使用，After 為游標分頁的起點（不含）
+	Offset int
+	After  *UserCursor
+	Limit  int
+}
+
+// UserPage 用戶列表分頁結果，Total 為符合條件的總筆數
+type UserPage struct {
+	Items      []User `json:"items"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"rbac-service/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheUnderTest 受測的快取實作，advance 讓時間前進以測試過期
+type cacheUnderTest struct {
+	cache   domain.Cache
+	advance func(d time.Duration)
+}
+
+// cacheImplementations 返回所有快取實作，Redis 以 miniredis 代替
+func cacheImplementations(t *testing.T) map[string]func(t *testing.T) cacheUnderTest {
+	return map[string]func(t *testing.T) cacheUnderTest{
+		"lru": func(t *testing.T) cacheUnderTest {
+			now := time.Now()
+			lru := NewLRUCache(100).(*LRUCache)
+			lru.now = func() time.Time { return now }
+			return cacheUnderTest{cache: lru, advance: func(d time.Duration) { now = now.Add(d) }}
+		},
+		"redis": func(t *testing.T) cacheUnderTest {
+			server := miniredis.RunT(t)
+			client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+			t.Cleanup(func() { _ = client.Close() })
+			return cacheUnderTest{cache: NewRedisCache(client, "rbac:"), advance: server.FastForward}
+		},
+	}
+}
+
+func TestCache_SetGetDelete(t *testing.T) {
+	for name, newCache := range cacheImplementations(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newCache(t).cache
+
+			_, ok, err := c.Get(ctx, "user:name:alice")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, c.Set(ctx, "user:name:alice", []byte("v1"), time.Minute))
+			value, ok, err := c.Get(ctx, "user:name:alice")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, []byte("v1"), value)
+
+			// 覆寫既有的鍵
+			require.NoError(t, c.Set(ctx, "user:name:alice", []byte("v2"), time.Minute))
+			value, _, _ = c.Get(ctx, "user:name:alice")
+			assert.Equal(t, []byte("v2"), value)
+
+			require.NoError(t, c.Delete(ctx, "user:name:alice", "missing"))
+			_, ok, err = c.Get(ctx, "user:name:alice")
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestCache_Expiry(t *testing.T) {
+	for name, newCache := range cacheImplementations(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			under := newCache(t)
+
+			require.NoError(t, under.cache.Set(ctx, "short", []byte("v"), time.Minute))
+			require.NoError(t, under.cache.Set(ctx, "forever", []byte("v"), 0))
+			under.advance(2 * time.Minute)
+
+			_, ok, err := under.cache.Get(ctx, "short")
+			require.NoError(t, err)
+			assert.False(t, ok)
+			_, ok, err = under.cache.Get(ctx, "forever")
+			require.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestCache_DeletePrefix(t *testing.T) {
+	for name, newCache := range cacheImplementations(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newCache(t).cache
+
+			// 超過單次 SCAN 的數量，確認會逐批刪除
+			for i := 0; i < 2*redisScanCount+5; i++ {
+				require.NoError(t, c.Set(ctx, fmt.Sprintf("perms:roles:%d", i), []byte("v"), time.Minute))
+			}
+			require.NoError(t, c.Set(ctx, "perms:rolesX", []byte("v"), time.Minute))
+			require.NoError(t, c.Set(ctx, "user:name:a*", []byte("v"), time.Minute))
+			require.NoError(t, c.Set(ctx, "user:name:ab", []byte("v"), time.Minute))
+
+			require.NoError(t, c.DeletePrefix(ctx, "perms:roles:"))
+			// glob 特殊字元只做字面比對
+			require.NoError(t, c.DeletePrefix(ctx, "user:name:a*"))
+
+			_, ok, _ := c.Get(ctx, "perms:roles:0")
+			assert.False(t, ok)
+			_, ok, _ = c.Get(ctx, fmt.Sprintf("perms:roles:%d", 2*redisScanCount+4))
+			assert.False(t, ok)
+			_, ok, _ = c.Get(ctx, "perms:rolesX")
+			assert.True(t, ok)
+			_, ok, _ = c.Get(ctx, "user:name:a*")
+			assert.False(t, ok)
+			_, ok, _ = c.Get(ctx, "user:name:ab")
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(2)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("a"), 0))
+	require.NoError(t, c.Set(ctx, "b", []byte("b"), 0))
+	// 讀取 a 後 b 成為最久未使用
+	_, _, _ = c.Get(ctx, "a")
+	require.NoError(t, c.Set(ctx, "c", []byte("c"), 0))
+
+	_, ok, _ := c.Get(ctx, "b")
+	assert.False(t, ok)
+	_, ok, _ = c.Get(ctx, "a")
+	assert.True(t, ok)
+	_, ok, _ = c.Get(ctx, "c")
+	assert.True(t, ok)
+}
+
+func TestRedisCache_Unavailable(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	c := NewRedisCache(client, "rbac:")
+	server.Close()
+
+	// 連線失敗須返回錯誤，由呼叫端決定是否改查資料庫
+	_, ok, err := c.Get(context.Background(), "key")
+	assert.Error(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"rbac-service/domain"
+)
+
+// LRUCache 行程內的 LRU 快取，超過容量時移除最久未使用的項目。
+// 只在單一實例內有效，多實例部署時其他實例的寫入要等項目過期才會反映
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	// order 由新到舊排列，Front 為最近使用的項目
+	order *list.List
+	now   func() time.Time
+}
+
+// lruEntry LRU 快取的項目
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUCache 創建容量為 capacity 的 LRU 快取
+func NewLRUCache(capacity int) domain.Cache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get 讀取快取並標記為最近使用，已過期的項目會被移除
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.removeElement(element)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+	return append([]byte(nil), entry.value...), true, nil
+}
+
+// Set 寫入快取，超過容量時移除最久未使用的項目
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &lruEntry{key: key, value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = c.now().Add(ttl)
+	}
+
+	if element, ok := c.items[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+	return nil
+}
+
+// Delete 刪除指定的鍵
+func (c *LRUCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.items[key]; ok {
+			c.removeElement(element)
+		}
+	}
+	return nil
+}
+
+// DeletePrefix 刪除以 prefix 開頭的所有鍵
+func (c *LRUCache) DeletePrefix(ctx context.Context, prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.removeElement(element)
+		}
+	}
+	return nil
+}
+
+// removeElement 從索引與順序中移除項目，呼叫端須持有鎖
+func (c *LRUCache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*lruEntry).key)
+}
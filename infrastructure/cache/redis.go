@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"rbac-service/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisScanCount DeletePrefix 每次 SCAN 取回的鍵數
+const redisScanCount = 100
+
+// RedisCache 以 Redis 實作的共享快取，所有鍵都加上 prefix 以便與其他服務共用同一個 Redis
+type RedisCache struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisCache 創建 Redis 快取
+func NewRedisCache(client redis.UniversalClient, prefix string) domain.Cache {
+	return &RedisCache{client: client, prefix: prefix}
+}
+
+// Get 讀取快取，鍵不存在時返回 false
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set 寫入快取，ttl 為 0 表示不過期
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+// Delete 刪除指定的鍵
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, c.prefix+key)
+	}
+	return c.client.Del(ctx, prefixed...).Err()
+}
+
+// DeletePrefix 以 SCAN 逐批找出以 prefix 開頭的鍵並刪除，避免 KEYS 阻塞 Redis
+func (c *RedisCache) DeletePrefix(ctx context.Context, prefix string) error {
+	pattern := escapeGlob(c.prefix+prefix) + "*"
+
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, pattern, redisScanCount).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := c.client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// globEscaper 跳脫 Redis glob 樣式的特殊字元
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// escapeGlob 跳脫鍵中的 glob 特殊字元，使其只做字面比對
+func escapeGlob(s string) string {
+	return globEscaper.Replace(s)
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// DefaultAuthConfigPath 認證設定的預設路徑
+const DefaultAuthConfigPath = "configs/auth.json"
+
+// AuthConfig 認證相關設定
+type AuthConfig struct {
+	// MaxSessionsPerUser 每位用戶同時存在的會話上限，超過時移除最久未活動的會話；0 表示不限制
+	MaxSessionsPerUser int `json:"maxSessionsPerUser"`
+	// JWT 簽章金鑰組，未設定時使用啟動時隨機產生的臨時金鑰
+	JWT *JWTConfig `json:"jwt,omitempty"`
+	// Token 訪問令牌的簽發與驗證設定
+	Token TokenConfig `json:"token"`
+	// ValidationMode 訪問令牌的驗證模式：strict 每個請求查詢資料庫，stateless 改查記憶體撤銷清單
+	ValidationMode string `json:"validationMode"`
+	// RevocationSyncSeconds stateless 模式同步撤銷清單的間隔秒數
+	RevocationSyncSeconds int `json:"revocationSyncSeconds"`
+	// RevocationPropagation 撤銷記錄在實例間的傳播方式：poll 只定期查詢資料庫，redis 另以 pub/sub 即時通知
+	RevocationPropagation string `json:"revocationPropagation"`
+}
+
+// TokenConfig 訪問令牌的簽發與驗證設定
+type TokenConfig struct {
+	// Issuer 令牌的 iss，設為空字串則不驗證
+	Issuer string `json:"issuer"`
+	// Audience 令牌的 aud，設為空字串則不驗證
+	Audience string `json:"audience"`
+	// LeewaySeconds 驗證 exp、nbf、iat 時容許的時鐘誤差秒數
+	LeewaySeconds int `json:"leewaySeconds"`
+	// Algorithms 允許的簽章演算法，未設定時允許 HS256、RS256、ES256
+	Algorithms []string `json:"algorithms,omitempty"`
+}
+
+// JWTConfig JWT 簽章金鑰組設定，輪替期間新舊金鑰需同時列出
+type JWTConfig struct {
+	// CurrentKid 資料庫尚未記錄輪替狀態時用於簽發的金鑰，預設為第一把
+	CurrentKid string `json:"currentKid,omitempty"`
+	// Keys 所有可用於驗證的金鑰
+	Keys []JWTKeyConfig `json:"keys"`
+}
+
+// JWTKeyConfig JWT 簽章金鑰設定
+type JWTKeyConfig struct {
+	// Kid 寫入令牌標頭的金鑰識別碼
+	Kid string `json:"kid"`
+	// Algorithm 簽章演算法：HS256、RS256 或 ES256
+	Algorithm string `json:"algorithm"`
+	// PrivateKeyFile PEM 格式私鑰的檔案路徑，RS256 與 ES256 使用
+	PrivateKeyFile string `json:"privateKeyFile,omitempty"`
+	// PrivateKey PEM 格式私鑰內容，與 PrivateKeyFile 擇一
+	PrivateKey string `json:"privateKey,omitempty"`
+	// Secret HS256 的共享密鑰
+	Secret string `json:"secret,omitempty"`
+}
+
+// KeyMaterial 返回建立金鑰所需的內容：HS256 為共享密鑰，其餘為 PEM 私鑰
+func (c JWTKeyConfig) KeyMaterial() ([]byte, error) {
+	if c.Algorithm == "HS256" {
+		if c.Secret == "" {
+			return nil, fmt.Errorf("金鑰 %s 缺少 secret", c.Kid)
+		}
+		return []byte(c.Secret), nil
+	}
+
+	if c.PrivateKey != "" {
+		return []byte(c.PrivateKey), nil
+	}
+	if c.PrivateKeyFile == "" {
+		return nil, fmt.Errorf("金鑰 %s 缺少 privateKey 或 privateKeyFile", c.Kid)
+	}
+	data, err := os.ReadFile(c.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("讀取私鑰檔案失敗: %v", err)
+	}
+	return data, nil
+}
+
+// DefaultAuthConfig 返回預設的認證設定
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		MaxSessionsPerUser:    5,
+		ValidationMode:        "strict",
+		RevocationSyncSeconds: 10,
+		RevocationPropagation: "poll",
+		Token: TokenConfig{
+			Issuer:        "rbac-service",
+			Audience:      "rbac-service",
+			LeewaySeconds: 30,
+		},
+	}
+}
+
+// LoadAuthConfig 讀取認證設定，檔案不存在時使用預設值
+func LoadAuthConfig(path string) (AuthConfig, error) {
+	authConfig := DefaultAuthConfig()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return authConfig, nil
+		}
+		return authConfig, fmt.Errorf("讀取認證配置檔案失敗: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &authConfig); err != nil {
+		return authConfig, fmt.Errorf("解析認證配置失敗: %v", err)
+	}
+	if authConfig.MaxSessionsPerUser < 0 {
+		return authConfig, fmt.Errorf("maxSessionsPerUser 不可為負數: %d", authConfig.MaxSessionsPerUser)
+	}
+	if authConfig.ValidationMode != "strict" && authConfig.ValidationMode != "stateless" {
+		return authConfig, fmt.Errorf("validationMode 須為 strict 或 stateless: %s", authConfig.ValidationMode)
+	}
+	if authConfig.RevocationSyncSeconds <= 0 {
+		return authConfig, fmt.Errorf("revocationSyncSeconds 須大於 0: %d", authConfig.RevocationSyncSeconds)
+	}
+	if authConfig.RevocationPropagation != "poll" && authConfig.RevocationPropagation != "redis" {
+		return authConfig, fmt.Errorf("revocationPropagation 須為 poll 或 redis: %s", authConfig.RevocationPropagation)
+	}
+	if authConfig.Token.LeewaySeconds < 0 {
+		return authConfig, fmt.Errorf("token.leewaySeconds 不可為負數: %d", authConfig.Token.LeewaySeconds)
+	}
+	if jwtConfig := authConfig.JWT; jwtConfig != nil {
+		if len(jwtConfig.Keys) == 0 {
+			return authConfig, errors.New("jwt.keys 至少需要一把金鑰")
+		}
+		if jwtConfig.CurrentKid == "" {
+			jwtConfig.CurrentKid = jwtConfig.Keys[0].Kid
+		}
+	}
+
+	return authConfig, nil
+}
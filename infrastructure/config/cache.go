@@ -0,0 +1,67 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// DefaultCacheConfigPath 快取設定的預設路徑
+const DefaultCacheConfigPath = "configs/cache.json"
+
+// 快取的實作方式
+const (
+	CacheDriverNone   = "none"
+	CacheDriverMemory = "memory"
+	CacheDriverRedis  = "redis"
+)
+
+// CacheConfig 用戶與有效權限查詢的快取設定
+type CacheConfig struct {
+	// Driver 快取實作：none 不快取、memory 為行程內 LRU、redis 為多實例共享，連線設定見 RedisConfig
+	Driver string `json:"driver"`
+	// Capacity memory 快取的項目上限
+	Capacity int `json:"capacity"`
+	// TTLSeconds 快取項目的存活秒數，其他實例的寫入最多延遲此時間才會反映
+	TTLSeconds int `json:"ttlSeconds"`
+}
+
+// DefaultCacheConfig 返回預設的快取設定
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Driver:     CacheDriverMemory,
+		Capacity:   10000,
+		TTLSeconds: 30,
+	}
+}
+
+// LoadCacheConfig 讀取快取設定，檔案不存在時使用預設值
+func LoadCacheConfig(path string) (CacheConfig, error) {
+	cacheConfig := DefaultCacheConfig()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cacheConfig, nil
+		}
+		return cacheConfig, fmt.Errorf("讀取快取配置檔案失敗: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &cacheConfig); err != nil {
+		return cacheConfig, fmt.Errorf("解析快取配置失敗: %v", err)
+	}
+	switch cacheConfig.Driver {
+	case CacheDriverNone, CacheDriverMemory, CacheDriverRedis:
+	default:
+		return cacheConfig, fmt.Errorf("driver 須為 none、memory 或 redis: %s", cacheConfig.Driver)
+	}
+	if cacheConfig.Capacity <= 0 {
+		return cacheConfig, fmt.Errorf("capacity 須大於 0: %d", cacheConfig.Capacity)
+	}
+	if cacheConfig.TTLSeconds <= 0 {
+		return cacheConfig, fmt.Errorf("ttlSeconds 須大於 0: %d", cacheConfig.TTLSeconds)
+	}
+
+	return cacheConfig, nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"rbac-service/domain"
+)
+
+// DefaultPermissionsPath 角色與權限種子設定的預設路徑
+const DefaultPermissionsPath = "configs/permissions.json"
+
+// LoadRoleSeeds 讀取角色與權限種子設定
+func LoadRoleSeeds(path string) ([]domain.RoleSeed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("讀取權限配置檔案失敗: %v", err)
+	}
+
+	var seeds []domain.RoleSeed
+	if err := json.Unmarshal(data, &seeds); err != nil {
+		return nil, fmt.Errorf("解析權限配置失敗: %v", err)
+	}
+
+	return seeds, nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// DefaultRedisConfigPath Redis 連線設定的預設路徑
+const DefaultRedisConfigPath = "configs/redis.json"
+
+// RedisConfig Redis 連線設定，快取與撤銷傳播共用同一個連線
+type RedisConfig struct {
+	Addr     string `json:"addr"`
+	Password string `json:"password,omitempty"`
+	DB       int    `json:"db"`
+	// Prefix 快取鍵與 channel 名稱的前綴，與其他服務共用 Redis 時避免衝突
+	Prefix string `json:"prefix"`
+}
+
+// DefaultRedisConfig 返回預設的 Redis 連線設定
+func DefaultRedisConfig() RedisConfig {
+	return RedisConfig{
+		Addr:   "localhost:6379",
+		Prefix: "rbac:",
+	}
+}
+
+// LoadRedisConfig 讀取 Redis 連線設定，檔案不存在時使用預設值
+func LoadRedisConfig(path string) (RedisConfig, error) {
+	redisConfig := DefaultRedisConfig()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return redisConfig, nil
+		}
+		return redisConfig, fmt.Errorf("讀取 Redis 配置檔案失敗: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &redisConfig); err != nil {
+		return redisConfig, fmt.Errorf("解析 Redis 配置失敗: %v", err)
+	}
+	if redisConfig.Addr == "" {
+		return redisConfig, errors.New("addr 不可為空")
+	}
+
+	return redisConfig, nil
+}
@@ -0,0 +1,55 @@
+package pubsub
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"rbac-service/domain"
+)
+
+// PollingRevocationBus 以定期查詢資料庫傳播撤銷記錄，其他實例的撤銷最晚於一個間隔後生效。
+// 撤銷記錄本身已寫入資料庫，因此 Publish 不需做任何事
+type PollingRevocationBus struct {
+	repo     domain.TokenRevocationRepository
+	interval time.Duration
+}
+
+// NewPollingRevocationBus 創建定期查詢資料庫的撤銷傳播
+func NewPollingRevocationBus(repo domain.TokenRevocationRepository, interval time.Duration) domain.RevocationBus {
+	return &PollingRevocationBus{repo: repo, interval: interval}
+}
+
+// Publish 撤銷記錄已在資料庫中，由其他實例的下次查詢取得
+func (b *PollingRevocationBus) Publish(ctx context.Context, revocations *domain.ActiveRevocations) error {
+	return nil
+}
+
+// Subscribe 先同步載入一次尚未過期的撤銷記錄，之後每個間隔重新查詢
+func (b *PollingRevocationBus) Subscribe(ctx context.Context, handler func(*domain.ActiveRevocations)) error {
+	revocations, err := b.repo.ListActiveRevocations(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	handler(revocations)
+
+	go func() {
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				revocations, err := b.repo.ListActiveRevocations(ctx, time.Now())
+				if err != nil {
+					log.Printf("Failed to sync token revocations: %v", err)
+					continue
+				}
+				handler(revocations)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,64 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"rbac-service/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisRevocationBus 以 Redis pub/sub 即時傳播撤銷記錄。
+// pub/sub 不保證送達，斷線期間的訊息會遺失，須搭配 PollingRevocationBus 補償
+type RedisRevocationBus struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewRedisRevocationBus 創建以 Redis channel 傳播的撤銷傳播
+func NewRedisRevocationBus(client redis.UniversalClient, channel string) domain.RevocationBus {
+	return &RedisRevocationBus{client: client, channel: channel}
+}
+
+// Publish 將撤銷記錄以 JSON 發布至 channel，發布者自己也會收到
+func (b *RedisRevocationBus) Publish(ctx context.Context, revocations *domain.ActiveRevocations) error {
+	payload, err := json.Marshal(revocations)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+// Subscribe 確認訂閱成功後於背景接收訊息，斷線時由 go-redis 自動重新連線並訂閱
+func (b *RedisRevocationBus) Subscribe(ctx context.Context, handler func(*domain.ActiveRevocations)) error {
+	sub := b.client.Subscribe(ctx, b.channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return err
+	}
+
+	go func() {
+		defer sub.Close()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var revocations domain.ActiveRevocations
+				if err := json.Unmarshal([]byte(message.Payload), &revocations); err != nil {
+					log.Printf("Failed to decode token revocation message: %v", err)
+					continue
+				}
+				handler(&revocations)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,534 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"rbac-service/domain"
+)
+
+// 快取鍵的前綴，用戶倉儲與授權倉儲共用相同的鍵，任一方的寫入都會使另一方的快取失效
+const (
+	cacheKeyUserByName     = "user:name:"
+	cacheKeyUserByID       = "user:id:"
+	cacheKeyUserRoles      = "roles:user:"
+	cacheKeyRolePermission = "perms:roles:"
+	cacheKeyUserPermission = "perms:user:"
+	cacheKeyRoleParents    = "roles:parents"
+	cacheKeyTenantByCode   = "tenant:code:"
+	cacheKeyTenantMember   = "tenant:member:"
+)
+
+// readThrough 先讀取快取，未命中時呼叫 load 並寫回快取。
+// 快取失效或解碼失敗時直接改查資料庫，查詢錯誤不會被快取
+func readThrough[T any](ctx context.Context, cache domain.Cache, ttl time.Duration, key string, load func() (T, error)) (T, error) {
+	return readThroughWithTTL(ctx, cache, key, func() (T, time.Duration, error) {
+		value, err := load()
+		return value, ttl, err
+	})
+}
+
+// readThroughWithTTL 與 readThrough 相同，但由 load 依查詢結果決定快取的有效期限，不為正值時不寫回快取
+func readThroughWithTTL[T any](ctx context.Context, cache domain.Cache, key string, load func() (T, time.Duration, error)) (T, error) {
+	if data, ok, err := cache.Get(ctx, key); err != nil {
+		log.Printf("讀取快取 %s 失敗: %v", key, err)
+	} else if ok {
+		var value T
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err == nil {
+			return value, nil
+		}
+		log.Printf("解碼快取 %s 失敗: %v", key, err)
+	}
+
+	value, ttl, err := load()
+	if err != nil || ttl <= 0 {
+		return value, err
+	}
+
+	// 以 gob 編碼以保留 JSON 不輸出的欄位，例如密碼雜湊
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		log.Printf("編碼快取 %s 失敗: %v", key, err)
+		return value, nil
+	}
+	if err := cache.Set(ctx, key, buf.Bytes(), ttl); err != nil {
+		log.Printf("寫入快取 %s 失敗: %v", key, err)
+	}
+	return value, nil
+}
+
+// invalidate 刪除指定的鍵，失敗時只記錄日誌，殘留的項目會在 TTL 後過期
+func invalidate(ctx context.Context, cache domain.Cache, keys ...string) {
+	if err := cache.Delete(ctx, keys...); err != nil {
+		log.Printf("清除快取 %s 失敗: %v", strings.Join(keys, ", "), err)
+	}
+}
+
+// invalidatePrefix 刪除以指定前綴開頭的所有鍵，失敗時只記錄日誌
+func invalidatePrefix(ctx context.Context, cache domain.Cache, prefixes ...string) {
+	for _, prefix := range prefixes {
+		if err := cache.DeletePrefix(ctx, prefix); err != nil {
+			log.Printf("清除快取 %s* 失敗: %v", prefix, err)
+		}
+	}
+}
+
+// userIDKey 返回用戶 ID 的快取鍵，非數字的 ID 不快取以免與正規化後的鍵不一致
+func userIDKey(id string) (string, bool) {
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return "", false
+	}
+	return cacheKeyUserByID + strconv.FormatInt(userID, 10), true
+}
+
+// userRolesPrefix 返回用戶角色快取鍵的前綴，角色依租戶分鍵，清除時須涵蓋所有租戶
+func userRolesPrefix(userID int64) string {
+	return cacheKeyUserRoles + strconv.FormatInt(userID, 10) + ":"
+}
+
+// userPermissionsPrefix 返回用戶直接分配權限快取鍵的前綴，權限依租戶分鍵，清除時須涵蓋所有租戶
+func userPermissionsPrefix(userID int64) string {
+	return cacheKeyUserPermission + strconv.FormatInt(userID, 10) + ":"
+}
+
+// tenantMemberKey 返回租戶成員資格的快取鍵
+func tenantMemberKey(tenantID, userID int64) string {
+	return cacheKeyTenantMember + strconv.FormatInt(tenantID, 10) + ":" + strconv.FormatInt(userID, 10)
+}
+
+// userCache 用戶查詢與失效邏輯，由用戶倉儲與授權倉儲共用
+type userCache struct {
+	cache domain.Cache
+	ttl   time.Duration
+}
+
+func (c userCache) getByID(ctx context.Context, repo domain.BaseRepository, id string) (*domain.User, error) {
+	key, ok := userIDKey(id)
+	if !ok {
+		return repo.GetByID(ctx, id)
+	}
+	return readThrough(ctx, c.cache, c.ttl, key, func() (*domain.User, error) {
+		return repo.GetByID(ctx, id)
+	})
+}
+
+func (c userCache) getByUsername(ctx context.Context, repo domain.BaseRepository, username string) (*domain.User, error) {
+	return readThrough(ctx, c.cache, c.ttl, cacheKeyUserByName+username, func() (*domain.User, error) {
+		return repo.GetByUsername(ctx, username)
+	})
+}
+
+// updateUser 更新後清除用戶的名稱與 ID 快取，改名時一併清除新名稱
+func (c userCache) updateUser(ctx context.Context, repo domain.BaseRepository, username string, updateFields map[string]interface{}) error {
+	keys, _ := c.userKeys(ctx, repo, username)
+	if newName, ok := updateFields["username"].(string); ok {
+		keys = append(keys, cacheKeyUserByName+newName)
+	}
+
+	if err := repo.UpdateUser(ctx, username, updateFields); err != nil {
+		return err
+	}
+	invalidate(ctx, c.cache, keys...)
+	return nil
+}
+
+// deleteUser 刪除後清除用戶與其角色、直接分配權限的快取
+func (c userCache) deleteUser(ctx context.Context, repo domain.BaseRepository, username string, revocation *domain.UserTokenRevocation) error {
+	keys, user := c.userKeys(ctx, repo, username)
+
+	if err := repo.DeleteUser(ctx, username, revocation); err != nil {
+		return err
+	}
+	invalidate(ctx, c.cache, keys...)
+	if user != nil {
+		invalidatePrefix(ctx, c.cache, userRolesPrefix(user.ID), userPermissionsPrefix(user.ID))
+	}
+	return nil
+}
+
+// userKeys 在寫入前查出用戶，返回該用戶除角色與權限外所有的快取鍵；用戶不存在時 user 為 nil
+func (c userCache) userKeys(ctx context.Context, repo domain.BaseRepository, username string) ([]string, *domain.User) {
+	keys := []string{cacheKeyUserByName + username}
+	user, err := repo.GetByUsername(ctx, username)
+	if err != nil {
+		return keys, nil
+	}
+	return append(keys, cacheKeyUserByID+strconv.FormatInt(user.ID, 10)), user
+}
+
+// CachedUserRepository 為用戶倉儲加上讀取快取
+type CachedUserRepository struct {
+	domain.UserRepository
+	users userCache
+}
+
+// NewCachedUserRepository 創建帶快取的用戶倉儲
+func NewCachedUserRepository(repo domain.UserRepository, cache domain.Cache, ttl time.Duration) domain.UserRepository {
+	return &CachedUserRepository{UserRepository: repo, users: userCache{cache: cache, ttl: ttl}}
+}
+
+// GetByID 根據用戶 ID 獲取用戶信息，優先讀取快取
+func (r *CachedUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.users.getByID(ctx, r.UserRepository, id)
+}
+
+// GetByUsername 根據用戶 username 獲取用戶信息，優先讀取快取
+func (r *CachedUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.users.getByUsername(ctx, r.UserRepository, username)
+}
+
+// UpdateUser 更新用戶並清除快取
+func (r *CachedUserRepository) UpdateUser(ctx context.Context, username string, updateFields map[string]interface{}) error {
+	return r.users.updateUser(ctx, r.UserRepository, username, updateFields)
+}
+
+// DeleteUser 刪除用戶並清除快取
+func (r *CachedUserRepository) DeleteUser(ctx context.Context, username string, revocation *domain.UserTokenRevocation) error {
+	return r.users.deleteUser(ctx, r.UserRepository, username, revocation)
+}
+
+// CachedAuthRepository 為授權倉儲加上讀取快取，涵蓋用戶查詢與有效權限查詢
+type CachedAuthRepository struct {
+	domain.AuthRepository
+	users userCache
+}
+
+// NewCachedAuthRepository 創建帶快取的授權倉儲
+func NewCachedAuthRepository(repo domain.AuthRepository, cache domain.Cache, ttl time.Duration) domain.AuthRepository {
+	return &CachedAuthRepository{AuthRepository: repo, users: userCache{cache: cache, ttl: ttl}}
+}
+
+// GetByID 根據用戶 ID 獲取用戶信息，優先讀取快取
+func (r *CachedAuthRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.users.getByID(ctx, r.AuthRepository, id)
+}
+
+// GetByUsername 根據用戶 username 獲取用戶信息，優先讀取快取
+func (r *CachedAuthRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.users.getByUsername(ctx, r.AuthRepository, username)
+}
+
+// UpdateUser 更新用戶並清除快取
+func (r *CachedAuthRepository) UpdateUser(ctx context.Context, username string, updateFields map[string]interface{}) error {
+	return r.users.updateUser(ctx, r.AuthRepository, username, updateFields)
+}
+
+// DeleteUser 刪除用戶並清除快取
+func (r *CachedAuthRepository) DeleteUser(ctx context.Context, username string, revocation *domain.UserTokenRevocation) error {
+	return r.users.deleteUser(ctx, r.AuthRepository, username, revocation)
+}
+
+// GetRolesByUserID 獲取用戶在租戶內生效的角色，優先讀取快取，以用戶與租戶 ID 作為快取鍵。
+// 快取的有效期限不超過下一個分配生效或過期的時間，臨時角色不需等到清除或失效通知即可反映，
+// 其他實例的快取同樣到期
+func (r *CachedAuthRepository) GetRolesByUserID(ctx context.Context, userID, tenantID int64) ([]domain.Role, error) {
+	key := userRolesPrefix(userID) + strconv.FormatInt(tenantID, 10)
+	roles, err := readThroughWithTTL(ctx, r.users.cache, key, func() ([]domain.Role, time.Duration, error) {
+		now := time.Now()
+		roles, err := r.AuthRepository.GetRolesByUserID(ctx, userID, tenantID)
+		if err != nil {
+			return nil, 0, err
+		}
+		next, err := r.AuthRepository.NextRoleAssignmentChange(ctx, userID, tenantID, now)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		ttl := r.users.ttl
+		if next != nil && next.Sub(now) < ttl {
+			ttl = next.Sub(now)
+		}
+		return roles, ttl, nil
+	})
+	// gob 不保留空切片，與資料庫查詢一致地返回空切片
+	if err == nil && roles == nil {
+		roles = []domain.Role{}
+	}
+	return roles, err
+}
+
+// GetPermissionsByRoleIDs 獲取多個角色擁有的權限，以排序後的角色 ID 組合作為快取鍵
+func (r *CachedAuthRepository) GetPermissionsByRoleIDs(ctx context.Context, roleIDs []int64) ([]domain.Permission, error) {
+	if len(roleIDs) == 0 {
+		return r.AuthRepository.GetPermissionsByRoleIDs(ctx, roleIDs)
+	}
+
+	sorted := append([]int64(nil), roleIDs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	ids := make([]string, 0, len(sorted))
+	for _, id := range sorted {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+
+	key := cacheKeyRolePermission + strings.Join(ids, ",")
+	permissions, err := readThrough(ctx, r.users.cache, r.users.ttl, key, func() ([]domain.Permission, error) {
+		return r.AuthRepository.GetPermissionsByRoleIDs(ctx, roleIDs)
+	})
+	if err == nil && permissions == nil {
+		permissions = []domain.Permission{}
+	}
+	return permissions, err
+}
+
+// GetPermissionsByUserID 獲取用戶在租戶內生效的直接分配權限，優先讀取快取，以用戶與租戶 ID 作為快取鍵
+func (r *CachedAuthRepository) GetPermissionsByUserID(ctx context.Context, userID, tenantID int64) ([]domain.Permission, error) {
+	key := userPermissionsPrefix(userID) + strconv.FormatInt(tenantID, 10)
+	permissions, err := readThrough(ctx, r.users.cache, r.users.ttl, key, func() ([]domain.Permission, error) {
+		return r.AuthRepository.GetPermissionsByUserID(ctx, userID, tenantID)
+	})
+	if err == nil && permissions == nil {
+		permissions = []domain.Permission{}
+	}
+	return permissions, err
+}
+
+// ListRoleParents 列出所有角色繼承關聯，整份關係以單一鍵快取
+func (r *CachedAuthRepository) ListRoleParents(ctx context.Context) ([]domain.RoleParent, error) {
+	edges, err := readThrough(ctx, r.users.cache, r.users.ttl, cacheKeyRoleParents, func() ([]domain.RoleParent, error) {
+		return r.AuthRepository.ListRoleParents(ctx)
+	})
+	if err == nil && edges == nil {
+		edges = []domain.RoleParent{}
+	}
+	return edges, err
+}
+
+// GetTenantByCode 以代碼獲取租戶，優先讀取快取；代碼不可變更，僅刪除租戶時清除
+func (r *CachedAuthRepository) GetTenantByCode(ctx context.Context, code string) (*domain.Tenant, error) {
+	return readThrough(ctx, r.users.cache, r.users.ttl, cacheKeyTenantByCode+code, func() (*domain.Tenant, error) {
+		return r.AuthRepository.GetTenantByCode(ctx, code)
+	})
+}
+
+// IsTenantMember 檢查用戶是否為租戶成員，優先讀取快取
+func (r *CachedAuthRepository) IsTenantMember(ctx context.Context, tenantID, userID int64) (bool, error) {
+	return readThrough(ctx, r.users.cache, r.users.ttl, tenantMemberKey(tenantID, userID), func() (bool, error) {
+		return r.AuthRepository.IsTenantMember(ctx, tenantID, userID)
+	})
+}
+
+// CachedRoleRepository 在角色與分配變更時清除相關的用戶與權限快取，本身不快取讀取
+type CachedRoleRepository struct {
+	domain.RoleRepository
+	cache domain.Cache
+}
+
+// NewCachedRoleRepository 創建會清除快取的角色倉儲
+func NewCachedRoleRepository(repo domain.RoleRepository, cache domain.Cache) domain.RoleRepository {
+	return &CachedRoleRepository{RoleRepository: repo, cache: cache}
+}
+
+// UpdateRole 更新角色，用戶快取中內含角色資料，因此清除所有用戶的角色快取
+func (r *CachedRoleRepository) UpdateRole(ctx context.Context, id int64, updateFields map[string]interface{}) error {
+	if err := r.RoleRepository.UpdateRole(ctx, id, updateFields); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, cacheKeyUserByID, cacheKeyUserRoles)
+	return nil
+}
+
+// DeleteRole 刪除角色並清除所有角色、權限與繼承關係快取
+func (r *CachedRoleRepository) DeleteRole(ctx context.Context, id int64) error {
+	if err := r.RoleRepository.DeleteRole(ctx, id); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, cacheKeyUserByID, cacheKeyUserRoles, cacheKeyRolePermission)
+	invalidate(ctx, r.cache, cacheKeyRoleParents)
+	return nil
+}
+
+// SetRoleParents 更新繼承關係並清除其快取，權限快取以展開後的角色 ID 為鍵，不受影響
+func (r *CachedRoleRepository) SetRoleParents(ctx context.Context, roleID int64, parentIDs []int64) error {
+	if err := r.RoleRepository.SetRoleParents(ctx, roleID, parentIDs); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, cacheKeyRoleParents)
+	return nil
+}
+
+// AssignRoleToUser 分配角色並清除該用戶的快取
+func (r *CachedRoleRepository) AssignRoleToUser(ctx context.Context, assignment *domain.UserRole) error {
+	if err := r.RoleRepository.AssignRoleToUser(ctx, assignment); err != nil {
+		return err
+	}
+	r.invalidateUser(ctx, assignment.UserID)
+	return nil
+}
+
+// RemoveRoleFromUser 移除角色並清除該用戶的快取
+func (r *CachedRoleRepository) RemoveRoleFromUser(ctx context.Context, userID, roleID, tenantID int64) error {
+	if err := r.RoleRepository.RemoveRoleFromUser(ctx, userID, roleID, tenantID); err != nil {
+		return err
+	}
+	r.invalidateUser(ctx, userID)
+	return nil
+}
+
+// AssignPermissionToRole 分配權限並清除角色權限快取
+func (r *CachedRoleRepository) AssignPermissionToRole(ctx context.Context, roleID, permissionID int64, effect, condition string) error {
+	if err := r.RoleRepository.AssignPermissionToRole(ctx, roleID, permissionID, effect, condition); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, cacheKeyRolePermission)
+	return nil
+}
+
+// RemovePermissionFromRole 移除權限並清除角色權限快取
+func (r *CachedRoleRepository) RemovePermissionFromRole(ctx context.Context, roleID, permissionID int64) error {
+	if err := r.RoleRepository.RemovePermissionFromRole(ctx, roleID, permissionID); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, cacheKeyRolePermission)
+	return nil
+}
+
+// AssignPermissionToUser 直接為用戶分配權限並清除該用戶在所有租戶的權限快取，全域分配影響每個租戶
+func (r *CachedRoleRepository) AssignPermissionToUser(ctx context.Context, assignment *domain.UserPermission) error {
+	if err := r.RoleRepository.AssignPermissionToUser(ctx, assignment); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, userPermissionsPrefix(assignment.UserID))
+	return nil
+}
+
+// RemovePermissionFromUser 移除用戶的權限並清除該用戶在所有租戶的權限快取
+func (r *CachedRoleRepository) RemovePermissionFromUser(ctx context.Context, userID, permissionID, tenantID int64) error {
+	if err := r.RoleRepository.RemovePermissionFromUser(ctx, userID, permissionID, tenantID); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, userPermissionsPrefix(userID))
+	return nil
+}
+
+// DeleteExpiredUserRoles 清除過期的角色分配並清除受影響用戶的快取
+func (r *CachedRoleRepository) DeleteExpiredUserRoles(ctx context.Context, now time.Time) ([]domain.UserRole, error) {
+	expired, err := r.RoleRepository.DeleteExpiredUserRoles(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	invalidated := make(map[int64]bool, len(expired))
+	for _, assignment := range expired {
+		if !invalidated[assignment.UserID] {
+			invalidated[assignment.UserID] = true
+			r.invalidateUser(ctx, assignment.UserID)
+		}
+	}
+	return expired, nil
+}
+
+// invalidateUser 清除用戶在所有租戶的角色快取與內含角色的 ID 快取，全域分配影響每個租戶
+func (r *CachedRoleRepository) invalidateUser(ctx context.Context, userID int64) {
+	invalidate(ctx, r.cache, cacheKeyUserByID+strconv.FormatInt(userID, 10))
+	invalidatePrefix(ctx, r.cache, userRolesPrefix(userID))
+}
+
+// CachedPermissionRepository 在權限變更時清除角色與用戶的權限快取，本身不快取讀取
+type CachedPermissionRepository struct {
+	domain.PermissionRepository
+	cache domain.Cache
+}
+
+// NewCachedPermissionRepository 創建會清除快取的權限倉儲
+func NewCachedPermissionRepository(repo domain.PermissionRepository, cache domain.Cache) domain.PermissionRepository {
+	return &CachedPermissionRepository{PermissionRepository: repo, cache: cache}
+}
+
+// UpdatePermission 更新權限並清除角色與用戶的權限快取
+func (r *CachedPermissionRepository) UpdatePermission(ctx context.Context, id int64, updateFields map[string]interface{}) error {
+	if err := r.PermissionRepository.UpdatePermission(ctx, id, updateFields); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, cacheKeyRolePermission, cacheKeyUserPermission)
+	return nil
+}
+
+// DeletePermission 刪除權限並清除角色與用戶的權限快取
+func (r *CachedPermissionRepository) DeletePermission(ctx context.Context, id int64) error {
+	if err := r.PermissionRepository.DeletePermission(ctx, id); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, cacheKeyRolePermission, cacheKeyUserPermission)
+	return nil
+}
+
+// CachedTenantRepository 在租戶與成員變更時清除租戶、成員資格與用戶角色快取，本身不快取讀取
+type CachedTenantRepository struct {
+	domain.TenantRepository
+	cache domain.Cache
+}
+
+// NewCachedTenantRepository 創建會清除快取的租戶倉儲
+func NewCachedTenantRepository(repo domain.TenantRepository, cache domain.Cache) domain.TenantRepository {
+	return &CachedTenantRepository{TenantRepository: repo, cache: cache}
+}
+
+// UpdateTenant 更新租戶並清除租戶快取
+func (r *CachedTenantRepository) UpdateTenant(ctx context.Context, id int64, updateFields map[string]interface{}) error {
+	if err := r.TenantRepository.UpdateTenant(ctx, id, updateFields); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, cacheKeyTenantByCode)
+	return nil
+}
+
+// DeleteTenant 刪除租戶，其成員資格與租戶內的角色及權限分配一併移除，因此清除所有用戶的角色與權限快取
+func (r *CachedTenantRepository) DeleteTenant(ctx context.Context, id int64) error {
+	if err := r.TenantRepository.DeleteTenant(ctx, id); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache,
+		cacheKeyTenantByCode,
+		cacheKeyTenantMember+strconv.FormatInt(id, 10)+":",
+		cacheKeyUserByID,
+		cacheKeyUserRoles,
+		cacheKeyUserPermission,
+	)
+	return nil
+}
+
+// AddTenantMember 加入成員並清除其成員資格快取
+func (r *CachedTenantRepository) AddTenantMember(ctx context.Context, tenantID, userID int64) error {
+	if err := r.TenantRepository.AddTenantMember(ctx, tenantID, userID); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, tenantMemberKey(tenantID, userID))
+	return nil
+}
+
+// RemoveTenantMember 移除成員並清除其成員資格、角色與權限快取
+func (r *CachedTenantRepository) RemoveTenantMember(ctx context.Context, tenantID, userID int64) error {
+	if err := r.TenantRepository.RemoveTenantMember(ctx, tenantID, userID); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, tenantMemberKey(tenantID, userID), cacheKeyUserByID+strconv.FormatInt(userID, 10))
+	invalidatePrefix(ctx, r.cache, userRolesPrefix(userID), userPermissionsPrefix(userID))
+	return nil
+}
+
+// CachedAccessRequestRepository 在核准申請建立角色分配時清除申請人的角色快取，本身不快取讀取
+type CachedAccessRequestRepository struct {
+	domain.AccessRequestRepository
+	cache domain.Cache
+}
+
+// NewCachedAccessRequestRepository 創建會清除快取的權限申請倉儲
+func NewCachedAccessRequestRepository(repo domain.AccessRequestRepository, cache domain.Cache) domain.AccessRequestRepository {
+	return &CachedAccessRequestRepository{AccessRequestRepository: repo, cache: cache}
+}
+
+// ApproveAccessRequest 核准申請並清除申請人的角色快取
+func (r *CachedAccessRequestRepository) ApproveAccessRequest(ctx context.Context, request *domain.AccessRequest, assignment *domain.UserRole) error {
+	if err := r.AccessRequestRepository.ApproveAccessRequest(ctx, request, assignment); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, cacheKeyUserByID+strconv.FormatInt(assignment.UserID, 10))
+	invalidatePrefix(ctx, r.cache, userRolesPrefix(assignment.UserID))
+	return nil
+}
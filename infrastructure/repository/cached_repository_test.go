@@ -0,0 +1,436 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"rbac-service/domain"
+	"rbac-service/infrastructure/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuthRepository 記憶體中的授權倉儲，記錄每個方法被呼叫的次數
+type fakeAuthRepository struct {
+	domain.AuthRepository
+	users       map[string]*domain.User
+	userRoles   map[int64][]domain.Role
+	permissions map[int64][]domain.Permission
+	parents     []domain.RoleParent
+	// direct 直接分配給用戶的權限，依用戶 ID 分組
+	direct map[int64][]domain.UserPermission
+	// nextChange 下一個分配生效或過期的時間，nil 表示沒有
+	nextChange *time.Time
+	calls      map[string]int
+}
+
+func newFakeAuthRepository() *fakeAuthRepository {
+	return &fakeAuthRepository{
+		users: map[string]*domain.User{
+			"alice": {ID: 1, Username: "alice", Password: "hash", Status: domain.UserStatusActive},
+		},
+		userRoles:   map[int64][]domain.Role{1: {{ID: 10, Name: "editor"}}},
+		permissions: map[int64][]domain.Permission{10: {{ID: 100, Resource: "articles", Action: "read"}}},
+		direct:      map[int64][]domain.UserPermission{},
+		calls:       map[string]int{},
+	}
+}
+
+func (r *fakeAuthRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	r.calls["GetByID"]++
+	for _, user := range r.users {
+		if strconv.FormatInt(user.ID, 10) == id {
+			copied := *user
+			copied.Roles = r.userRoles[user.ID]
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *fakeAuthRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	r.calls["GetByUsername"]++
+	user, ok := r.users[username]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeAuthRepository) UpdateUser(ctx context.Context, username string, updateFields map[string]interface{}) error {
+	user, ok := r.users[username]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	if status, ok := updateFields["status"].(string); ok {
+		user.Status = status
+	}
+	if newName, ok := updateFields["username"].(string); ok {
+		delete(r.users, username)
+		user.Username = newName
+		r.users[newName] = user
+	}
+	return nil
+}
+
+func (r *fakeAuthRepository) DeleteUser(ctx context.Context, username string, revocation *domain.UserTokenRevocation) error {
+	user, ok := r.users[username]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(r.users, username)
+	delete(r.userRoles, user.ID)
+	return nil
+}
+
+func (r *fakeAuthRepository) GetRolesByUserID(ctx context.Context, userID, tenantID int64) ([]domain.Role, error) {
+	r.calls["GetRolesByUserID"]++
+	return append([]domain.Role{}, r.userRoles[userID]...), nil
+}
+
+func (r *fakeAuthRepository) NextRoleAssignmentChange(ctx context.Context, userID, tenantID int64, now time.Time) (*time.Time, error) {
+	return r.nextChange, nil
+}
+
+func (r *fakeAuthRepository) GetPermissionsByRoleIDs(ctx context.Context, roleIDs []int64) ([]domain.Permission, error) {
+	r.calls["GetPermissionsByRoleIDs"]++
+	permissions := []domain.Permission{}
+	for _, id := range roleIDs {
+		permissions = append(permissions, r.permissions[id]...)
+	}
+	return permissions, nil
+}
+
+func (r *fakeAuthRepository) GetPermissionsByUserID(ctx context.Context, userID, tenantID int64) ([]domain.Permission, error) {
+	r.calls["GetPermissionsByUserID"]++
+	permissions := []domain.Permission{}
+	for _, assignment := range r.direct[userID] {
+		if assignment.TenantID == domain.GlobalTenantID || assignment.TenantID == tenantID {
+			permissions = append(permissions, domain.Permission{ID: assignment.PermissionID, Effect: assignment.Effect})
+		}
+	}
+	return permissions, nil
+}
+
+func (r *fakeAuthRepository) ListRoleParents(ctx context.Context) ([]domain.RoleParent, error) {
+	r.calls["ListRoleParents"]++
+	return append([]domain.RoleParent{}, r.parents...), nil
+}
+
+// fakeRoleRepository 直接修改 fakeAuthRepository 的分配資料
+type fakeRoleRepository struct {
+	domain.RoleRepository
+	auth *fakeAuthRepository
+	// timeBound 有效期間限定的分配，供 DeleteExpiredUserRoles 清除
+	timeBound []domain.UserRole
+}
+
+func (r *fakeRoleRepository) AssignRoleToUser(ctx context.Context, assignment *domain.UserRole) error {
+	r.auth.userRoles[assignment.UserID] = append(r.auth.userRoles[assignment.UserID], domain.Role{ID: assignment.RoleID})
+	if assignment.ValidUntil != nil {
+		r.timeBound = append(r.timeBound, *assignment)
+	}
+	return nil
+}
+
+func (r *fakeRoleRepository) DeleteExpiredUserRoles(ctx context.Context, now time.Time) ([]domain.UserRole, error) {
+	expired := []domain.UserRole{}
+	for _, assignment := range r.timeBound {
+		if assignment.ValidUntil.After(now) {
+			continue
+		}
+		expired = append(expired, assignment)
+		roles := []domain.Role{}
+		for _, role := range r.auth.userRoles[assignment.UserID] {
+			if role.ID != assignment.RoleID {
+				roles = append(roles, role)
+			}
+		}
+		r.auth.userRoles[assignment.UserID] = roles
+	}
+	return expired, nil
+}
+
+func (r *fakeRoleRepository) AssignPermissionToRole(ctx context.Context, roleID, permissionID int64, effect, condition string) error {
+	r.auth.permissions[roleID] = append(r.auth.permissions[roleID], domain.Permission{ID: permissionID, Effect: effect, Condition: condition})
+	return nil
+}
+
+func (r *fakeRoleRepository) AssignPermissionToUser(ctx context.Context, assignment *domain.UserPermission) error {
+	r.auth.direct[assignment.UserID] = append(r.auth.direct[assignment.UserID], *assignment)
+	return nil
+}
+
+func (r *fakeRoleRepository) SetRoleParents(ctx context.Context, roleID int64, parentIDs []int64) error {
+	edges := []domain.RoleParent{}
+	for _, edge := range r.auth.parents {
+		if edge.RoleID != roleID {
+			edges = append(edges, edge)
+		}
+	}
+	for _, parentID := range parentIDs {
+		edges = append(edges, domain.RoleParent{RoleID: roleID, ParentID: parentID})
+	}
+	r.auth.parents = edges
+	return nil
+}
+
+func newCachedRepositories(t *testing.T) (*fakeAuthRepository, domain.AuthRepository, domain.RoleRepository) {
+	lru := cache.NewLRUCache(100)
+	fake := newFakeAuthRepository()
+	return fake, NewCachedAuthRepository(fake, lru, time.Minute), NewCachedRoleRepository(&fakeRoleRepository{auth: fake}, lru)
+}
+
+func TestCachedAuthRepository_GetByUsername(t *testing.T) {
+	fake, repo, _ := newCachedRepositories(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		user, err := repo.GetByUsername(ctx, "alice")
+		require.NoError(t, err)
+		// 密碼雜湊不輸出到 JSON，但快取須保留以供登入驗證
+		assert.Equal(t, "hash", user.Password)
+	}
+	assert.Equal(t, 1, fake.calls["GetByUsername"])
+
+	// 查詢錯誤不快取
+	for i := 0; i < 2; i++ {
+		_, err := repo.GetByUsername(ctx, "bob")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	}
+	assert.Equal(t, 3, fake.calls["GetByUsername"])
+}
+
+func TestCachedAuthRepository_UpdateUserInvalidates(t *testing.T) {
+	_, repo, _ := newCachedRepositories(t)
+	ctx := context.Background()
+
+	_, err := repo.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UpdateUser(ctx, "alice", map[string]interface{}{"status": domain.UserStatusDisabled}))
+
+	user, err := repo.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserStatusDisabled, user.Status)
+	user, err = repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserStatusDisabled, user.Status)
+
+	// 改名後舊名稱不再命中快取
+	require.NoError(t, repo.UpdateUser(ctx, "alice", map[string]interface{}{"username": "alicia"}))
+	_, err = repo.GetByUsername(ctx, "alice")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	user, err = repo.GetByUsername(ctx, "alicia")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), user.ID)
+}
+
+func TestCachedAuthRepository_DeleteUserInvalidates(t *testing.T) {
+	_, repo, _ := newCachedRepositories(t)
+	ctx := context.Background()
+
+	_, err := repo.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	roles, err := repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+
+	require.NoError(t, repo.DeleteUser(ctx, "alice", &domain.UserTokenRevocation{Username: "alice"}))
+
+	_, err = repo.GetByUsername(ctx, "alice")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	_, err = repo.GetByID(ctx, "1")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	roles, err = repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	assert.NotNil(t, roles)
+	assert.Empty(t, roles)
+}
+
+func TestCachedAuthRepository_RoleChangesInvalidate(t *testing.T) {
+	fake, repo, roleRepo := newCachedRepositories(t)
+	ctx := context.Background()
+
+	roles, err := repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	permissions, err := repo.GetPermissionsByRoleIDs(ctx, []int64{10})
+	require.NoError(t, err)
+	require.Len(t, permissions, 1)
+
+	// 命中快取時不查詢資料庫
+	_, err = repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	_, err = repo.GetPermissionsByRoleIDs(ctx, []int64{10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.calls["GetRolesByUserID"])
+	assert.Equal(t, 1, fake.calls["GetPermissionsByRoleIDs"])
+
+	require.NoError(t, roleRepo.AssignRoleToUser(ctx, &domain.UserRole{UserID: 1, RoleID: 20}))
+	roles, err = repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	assert.Len(t, roles, 2)
+	user, err := repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Len(t, user.Roles, 2)
+
+	// 角色 ID 的順序不影響快取鍵
+	_, err = repo.GetPermissionsByRoleIDs(ctx, []int64{20, 10})
+	require.NoError(t, err)
+	_, err = repo.GetPermissionsByRoleIDs(ctx, []int64{10, 20})
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.calls["GetPermissionsByRoleIDs"])
+
+	require.NoError(t, roleRepo.AssignPermissionToRole(ctx, 10, 101, domain.EffectAllow, ""))
+	permissions, err = repo.GetPermissionsByRoleIDs(ctx, []int64{10, 20})
+	require.NoError(t, err)
+	assert.Len(t, permissions, 2)
+	assert.Equal(t, 3, fake.calls["GetPermissionsByRoleIDs"])
+}
+
+func TestCachedAuthRepository_SetRoleParentsInvalidates(t *testing.T) {
+	fake, repo, roleRepo := newCachedRepositories(t)
+	ctx := context.Background()
+
+	edges, err := repo.ListRoleParents(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, edges)
+	assert.Empty(t, edges)
+	_, err = repo.ListRoleParents(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.calls["ListRoleParents"])
+
+	require.NoError(t, roleRepo.SetRoleParents(ctx, 10, []int64{20}))
+	edges, err = repo.ListRoleParents(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.RoleParent{{RoleID: 10, ParentID: 20}}, edges)
+	assert.Equal(t, 2, fake.calls["ListRoleParents"])
+}
+
+func TestCachedAuthRepository_RolesCachedPerTenant(t *testing.T) {
+	fake, repo, roleRepo := newCachedRepositories(t)
+	ctx := context.Background()
+
+	// 不同租戶使用不同的快取鍵
+	_, err := repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	_, err = repo.GetRolesByUserID(ctx, 1, 5)
+	require.NoError(t, err)
+	_, err = repo.GetRolesByUserID(ctx, 1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.calls["GetRolesByUserID"])
+
+	// 全域分配影響所有租戶，須清除每個租戶的角色快取
+	require.NoError(t, roleRepo.AssignRoleToUser(ctx, &domain.UserRole{UserID: 1, RoleID: 20}))
+	roles, err := repo.GetRolesByUserID(ctx, 1, 5)
+	require.NoError(t, err)
+	assert.Len(t, roles, 2)
+	assert.Equal(t, 3, fake.calls["GetRolesByUserID"])
+}
+
+func TestCachedAuthRepository_DirectPermissionsCachedPerTenant(t *testing.T) {
+	fake, repo, roleRepo := newCachedRepositories(t)
+	ctx := context.Background()
+
+	// 不同租戶使用不同的快取鍵，租戶內的分配不影響其他租戶
+	require.NoError(t, roleRepo.AssignPermissionToUser(ctx, &domain.UserPermission{UserID: 1, PermissionID: 100, TenantID: 5}))
+	permissions, err := repo.GetPermissionsByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	assert.Empty(t, permissions)
+	permissions, err = repo.GetPermissionsByUserID(ctx, 1, 5)
+	require.NoError(t, err)
+	assert.Len(t, permissions, 1)
+	_, err = repo.GetPermissionsByUserID(ctx, 1, 5)
+	require.NoError(t, err)
+	_, err = repo.GetPermissionsByUserID(ctx, 10, domain.GlobalTenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, fake.calls["GetPermissionsByUserID"])
+
+	// 全域分配影響所有租戶，須清除該用戶每個租戶的權限快取，但不影響 ID 前綴相同的其他用戶
+	require.NoError(t, roleRepo.AssignPermissionToUser(ctx, &domain.UserPermission{UserID: 1, PermissionID: 101}))
+	permissions, err = repo.GetPermissionsByUserID(ctx, 1, 5)
+	require.NoError(t, err)
+	assert.Len(t, permissions, 2)
+	_, err = repo.GetPermissionsByUserID(ctx, 10, domain.GlobalTenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, fake.calls["GetPermissionsByUserID"])
+}
+
+func TestCachedRoleRepository_DeleteExpiredUserRolesInvalidates(t *testing.T) {
+	fake, repo, roleRepo := newCachedRepositories(t)
+	ctx := context.Background()
+
+	validUntil := time.Now().Add(time.Hour)
+	require.NoError(t, roleRepo.AssignRoleToUser(ctx, &domain.UserRole{UserID: 1, RoleID: 20, ValidUntil: &validUntil}))
+	roles, err := repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	assert.Len(t, roles, 2)
+
+	// 尚未過期時不清除
+	expired, err := roleRepo.DeleteExpiredUserRoles(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+
+	expired, err = roleRepo.DeleteExpiredUserRoles(ctx, validUntil)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, int64(20), expired[0].RoleID)
+
+	roles, err = repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Role{{ID: 10, Name: "editor"}}, roles)
+	assert.Equal(t, 2, fake.calls["GetRolesByUserID"])
+}
+
+// ttlRecordingCache 記錄每個鍵寫入時的有效期限
+type ttlRecordingCache struct {
+	domain.Cache
+	ttls map[string]time.Duration
+}
+
+func (c *ttlRecordingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.ttls[key] = ttl
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func TestCachedAuthRepository_RolesTTLCappedAtNextChange(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeAuthRepository()
+	recorder := &ttlRecordingCache{Cache: cache.NewLRUCache(100), ttls: map[string]time.Duration{}}
+	repo := NewCachedAuthRepository(fake, recorder, time.Hour)
+	key := userRolesPrefix(1) + "0"
+
+	// 沒有臨時分配時使用設定的 TTL
+	_, err := repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, recorder.ttls[key])
+
+	// 分配在 TTL 內生效或過期時，快取不超過該時間
+	invalidate(ctx, recorder, key)
+	next := time.Now().Add(10 * time.Minute)
+	fake.nextChange = &next
+	_, err = repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, recorder.ttls[key], 10*time.Minute)
+	assert.Greater(t, recorder.ttls[key], 9*time.Minute)
+
+	// 變化時間已到時不寫入快取，每次都查詢資料庫
+	invalidate(ctx, recorder, key)
+	delete(recorder.ttls, key)
+	past := time.Now()
+	fake.nextChange = &past
+	calls := fake.calls["GetRolesByUserID"]
+	_, err = repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	_, err = repo.GetRolesByUserID(ctx, 1, domain.GlobalTenantID)
+	require.NoError(t, err)
+	assert.NotContains(t, recorder.ttls, key)
+	assert.Equal(t, calls+2, fake.calls["GetRolesByUserID"])
+}
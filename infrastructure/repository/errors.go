@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlErrDuplicateEntry MySQL 唯一鍵衝突的錯誤碼
+const mysqlErrDuplicateEntry = 1062
+
+// isDuplicateKeyError 判斷是否為唯一鍵衝突
+func isDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
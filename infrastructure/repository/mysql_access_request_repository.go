@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+)
+
+// MySQLAccessRequestRepository MySQL 權限申請倉儲實作
+type MySQLAccessRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLAccessRequestRepository 創建 MySQL 權限申請倉儲
+func NewMySQLAccessRequestRepository(db *gorm.DB) domain.AccessRequestRepository {
+	return &MySQLAccessRequestRepository{db: db}
+}
+
+// CreateAccessRequest 創建權限申請
+func (r *MySQLAccessRequestRepository) CreateAccessRequest(ctx context.Context, request *domain.AccessRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+// GetAccessRequestByID 根據 ID 獲取權限申請
+func (r *MySQLAccessRequestRepository) GetAccessRequestByID(ctx context.Context, id int64) (*domain.AccessRequest, error) {
+	var request domain.AccessRequest
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&request)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAccessRequestNotFound
+		}
+		return nil, result.Error
+	}
+	return &request, nil
+}
+
+// ListAccessRequests 依條件列出權限申請，新的在前
+func (r *MySQLAccessRequestRepository) ListAccessRequests(ctx context.Context, filter domain.AccessRequestFilter) ([]domain.AccessRequest, error) {
+	query := r.db.WithContext(ctx).Model(&domain.AccessRequest{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.RequesterID != 0 {
+		query = query.Where("requester_id = ?", filter.RequesterID)
+	}
+	if filter.TenantID > 0 {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
+
+	requests := []domain.AccessRequest{}
+	if err := query.Order("id DESC").Limit(filter.Limit).Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// HasPendingAccessRequest 檢查用戶對同一租戶內的角色是否已有待審核的申請
+func (r *MySQLAccessRequestRepository) HasPendingAccessRequest(ctx context.Context, requesterID, roleID, tenantID int64) (bool, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&domain.AccessRequest{}).
+		Where("requester_id = ? AND role_id = ? AND tenant_id = ? AND status = ?",
+			requesterID, roleID, tenantID, domain.AccessRequestPending).
+		Count(&count)
+	return count > 0, result.Error
+}
+
+// UpdateAccessRequestStatus 以申請仍為待審核為條件寫入狀態與審核結果
+func (r *MySQLAccessRequestRepository) UpdateAccessRequestStatus(ctx context.Context, request *domain.AccessRequest) error {
+	return updateAccessRequestStatus(r.db.WithContext(ctx), request)
+}
+
+// ApproveAccessRequest 在同一交易中寫入核准結果並建立臨時角色分配，任一失敗時皆不生效
+func (r *MySQLAccessRequestRepository) ApproveAccessRequest(ctx context.Context, request *domain.AccessRequest, assignment *domain.UserRole) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateAccessRequestStatus(tx, request); err != nil {
+			return err
+		}
+		return assignUserRole(tx, assignment)
+	})
+}
+
+// ExpirePendingAccessRequests 將逾期未審核的申請標記為 expired，只更新標記時仍為待審核的申請
+func (r *MySQLAccessRequestRepository) ExpirePendingAccessRequests(ctx context.Context, now time.Time) ([]domain.AccessRequest, error) {
+	var expired []domain.AccessRequest
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var pending []domain.AccessRequest
+		err := tx.Where("status = ? AND expires_at <= ?", domain.AccessRequestPending, now).
+			Order("id").
+			Find(&pending).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range pending {
+			request := &pending[i]
+			if err := request.Expire(now); err != nil {
+				return err
+			}
+			err := updateAccessRequestStatus(tx, request)
+			if errors.Is(err, domain.ErrAccessRequestNotPending) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			expired = append(expired, *request)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// updateAccessRequestStatus 以 status = pending 為條件更新申請，避免並行的審核互相覆寫
+func updateAccessRequestStatus(db *gorm.DB, request *domain.AccessRequest) error {
+	result := db.Model(&domain.AccessRequest{}).
+		Where("id = ? AND status = ?", request.ID, domain.AccessRequestPending).
+		Updates(map[string]interface{}{
+			"status":         request.Status,
+			"reviewer_id":    request.ReviewerID,
+			"review_comment": request.ReviewComment,
+			"decided_at":     request.DecidedAt,
+			"granted_until":  request.GrantedUntil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAccessRequestNotPending
+	}
+	return nil
+}
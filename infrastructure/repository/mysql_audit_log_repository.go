@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+)
+
+// MySQLAuditLogRepository MySQL 審計日誌倉儲實作
+type MySQLAuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLAuditLogRepository 創建 MySQL 審計日誌倉儲
+func NewMySQLAuditLogRepository(db *gorm.DB) domain.AuditLogRepository {
+	return &MySQLAuditLogRepository{db: db}
+}
+
+// CreateAuditLog 寫入一筆審計日誌
+func (r *MySQLAuditLogRepository) CreateAuditLog(ctx context.Context, log *domain.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// ListAuditLogs 依條件查詢審計日誌，以 ID 作為游標由新到舊分頁
+func (r *MySQLAuditLogRepository) ListAuditLogs(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLog, error) {
+	query := r.db.WithContext(ctx).Model(&domain.AuditLog{})
+
+	if filter.Operator != "" {
+		query = query.Where("operator = ?", filter.Operator)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Operation != "" {
+		query = query.Where("operation = ?", filter.Operation)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.Cursor > 0 {
+		query = query.Where("id < ?", filter.Cursor)
+	}
+
+	logs := []domain.AuditLog{}
+	result := query.Order("id DESC").Limit(filter.Limit).Find(&logs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return logs, nil
+}
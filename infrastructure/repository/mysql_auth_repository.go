@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+)
+
+// MySQLAuthRepository MySQL 授權倉儲實作，在用戶倉儲之上提供角色與權限的查詢
+type MySQLAuthRepository struct {
+	*MySQLUserRepository
+}
+
+// NewMySQLAuthRepository 創建 MySQL 授權倉儲
+func NewMySQLAuthRepository(db *gorm.DB) domain.AuthRepository {
+	return &MySQLAuthRepository{
+		MySQLUserRepository: &MySQLUserRepository{db: db},
+	}
+}
+
+// GetRolesByUserID 透過 user_roles 關聯表獲取用戶目前生效的全域角色與租戶內的角色，
+// 其他租戶與不在有效期間內的分配一律排除；同一角色同時為全域與租戶內分配時只返回一筆
+func (r *MySQLAuthRepository) GetRolesByUserID(ctx context.Context, userID, tenantID int64) ([]domain.Role, error) {
+	var roles []domain.Role
+	result := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.Model(&domain.UserRole{}).
+			Select("role_id").
+			Where("user_id = ? AND tenant_id IN ?", userID, []int64{domain.GlobalTenantID, tenantID}).
+			Scopes(activeUserRoles(time.Now()))).
+		Order("id").
+		Find(&roles)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return roles, nil
+}
+
+// NextRoleAssignmentChange 查出用戶在全域與租戶內尚未開始或尚未過期的分配，返回其中最早的生效或過期時間
+func (r *MySQLAuthRepository) NextRoleAssignmentChange(ctx context.Context, userID, tenantID int64, now time.Time) (*time.Time, error) {
+	var assignments []domain.UserRole
+	result := r.db.WithContext(ctx).
+		Select("valid_from", "valid_until").
+		Where("user_id = ? AND tenant_id IN ?", userID, []int64{domain.GlobalTenantID, tenantID}).
+		Where("valid_from > ? OR valid_until > ?", now, now).
+		Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var next *time.Time
+	for _, assignment := range assignments {
+		for _, boundary := range []*time.Time{assignment.ValidFrom, assignment.ValidUntil} {
+			if boundary != nil && boundary.After(now) && (next == nil || boundary.Before(*next)) {
+				next = boundary
+			}
+		}
+	}
+	return next, nil
+}
+
+// GetPermissionsByRoleIDs 透過 role_permissions 關聯表獲取角色的權限及其效果
+func (r *MySQLAuthRepository) GetPermissionsByRoleIDs(ctx context.Context, roleIDs []int64) ([]domain.Permission, error) {
+	if len(roleIDs) == 0 {
+		return []domain.Permission{}, nil
+	}
+
+	var permissions []domain.Permission
+	result := r.db.WithContext(ctx).
+		Distinct("permissions.*", "role_permissions.effect", "role_permissions.condition_expr").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Where("role_permissions.role_id IN ?", roleIDs).
+		Order("permissions.id").
+		Find(&permissions)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return permissions, nil
+}
+
+// GetPermissionsByUserID 透過 user_permissions 關聯表獲取用戶的全域與租戶內的直接分配權限及其效果，
+// 其他租戶的分配一律排除；相同的權限與效果同時為全域與租戶內分配時只返回一筆
+func (r *MySQLAuthRepository) GetPermissionsByUserID(ctx context.Context, userID, tenantID int64) ([]domain.Permission, error) {
+	permissions := []domain.Permission{}
+	result := r.db.WithContext(ctx).
+		Distinct("permissions.*", "user_permissions.effect").
+		Joins("JOIN user_permissions ON user_permissions.permission_id = permissions.id").
+		Where("user_permissions.user_id = ? AND user_permissions.tenant_id IN ?", userID, []int64{domain.GlobalTenantID, tenantID}).
+		Order("permissions.id").
+		Find(&permissions)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return permissions, nil
+}
+
+// ListRoleParents 列出所有角色繼承關聯
+func (r *MySQLAuthRepository) ListRoleParents(ctx context.Context) ([]domain.RoleParent, error) {
+	return listRoleParents(r.db.WithContext(ctx))
+}
+
+// ListObjectGrants 獲取用戶在全域與租戶內對某類資源的實例授權，objectID 不為空時只返回該實例的授權
+func (r *MySQLAuthRepository) ListObjectGrants(ctx context.Context, userID, tenantID int64, resource, objectID string) ([]domain.ObjectGrant, error) {
+	query := r.db.WithContext(ctx).
+		Where("user_id = ? AND tenant_id IN ? AND resource = ?", userID, []int64{domain.GlobalTenantID, tenantID}, resource)
+	if objectID != "" {
+		query = query.Where("object_id = ?", objectID)
+	}
+
+	grants := []domain.ObjectGrant{}
+	if err := query.Order("object_id, action").Find(&grants).Error; err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// GetResourceOwner 獲取資源實例在租戶內的擁有者，租戶內的登記排序在前，因此優先於全域登記
+func (r *MySQLAuthRepository) GetResourceOwner(ctx context.Context, tenantID int64, resource, objectID string) (*domain.ResourceOwner, error) {
+	return getResourceOwner(r.db.WithContext(ctx).
+		Where("tenant_id IN ?", []int64{domain.GlobalTenantID, tenantID}).
+		Order("tenant_id DESC"), resource, objectID)
+}
+
+// ListOwnedObjectIDs 獲取用戶在租戶內擁有的某類資源實例 ID：租戶內登記為其擁有者的實例，
+// 以及全域登記為其擁有者且租戶內沒有另行登記的實例
+func (r *MySQLAuthRepository) ListOwnedObjectIDs(ctx context.Context, ownerID, tenantID int64, resource string) ([]string, error) {
+	db := r.db.WithContext(ctx)
+	overridden := db.Table("resource_owners AS scoped").
+		Select("1").
+		Where("scoped.tenant_id = ? AND scoped.resource = owners.resource AND scoped.object_id = owners.object_id", tenantID)
+
+	objectIDs := []string{}
+	result := db.Table("resource_owners AS owners").
+		Where("owners.owner_id = ? AND owners.resource = ?", ownerID, resource).
+		Where("owners.tenant_id = ? OR (owners.tenant_id = ? AND NOT EXISTS (?))", tenantID, domain.GlobalTenantID, overridden).
+		Order("owners.object_id").
+		Pluck("owners.object_id", &objectIDs)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return objectIDs, nil
+}
+
+// ListOwnerRules 列出所有擁有者規則
+func (r *MySQLAuthRepository) ListOwnerRules(ctx context.Context) ([]domain.OwnerRule, error) {
+	return listOwnerRules(r.db.WithContext(ctx))
+}
+
+// GetTenantByCode 以代碼獲取租戶
+func (r *MySQLAuthRepository) GetTenantByCode(ctx context.Context, code string) (*domain.Tenant, error) {
+	return getTenantByCode(r.db.WithContext(ctx), code)
+}
+
+// IsTenantMember 檢查用戶是否為租戶成員
+func (r *MySQLAuthRepository) IsTenantMember(ctx context.Context, tenantID, userID int64) (bool, error) {
+	return isTenantMember(r.db.WithContext(ctx), tenantID, userID)
+}
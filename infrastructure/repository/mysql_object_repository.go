@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MySQLObjectRepository MySQL 資源實例授權倉儲實作
+type MySQLObjectRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLObjectRepository 創建 MySQL 資源實例授權倉儲
+func NewMySQLObjectRepository(db *gorm.DB) domain.ObjectRepository {
+	return &MySQLObjectRepository{db: db}
+}
+
+// ListUserObjectGrants 列出用戶的所有實例授權
+func (r *MySQLObjectRepository) ListUserObjectGrants(ctx context.Context, userID int64) ([]domain.ObjectGrant, error) {
+	grants := []domain.ObjectGrant{}
+	result := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("tenant_id, resource, object_id, action").
+		Find(&grants)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return grants, nil
+}
+
+// CreateObjectGrant 新增實例授權
+func (r *MySQLObjectRepository) CreateObjectGrant(ctx context.Context, grant *domain.ObjectGrant) error {
+	result := r.db.WithContext(ctx).Create(grant)
+	if result.Error != nil {
+		if isDuplicateKeyError(result.Error) {
+			return domain.ErrObjectGrantAlreadyExists
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// DeleteObjectGrant 刪除用戶的實例授權
+func (r *MySQLObjectRepository) DeleteObjectGrant(ctx context.Context, userID, grantID int64) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", grantID, userID).
+		Delete(&domain.ObjectGrant{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrObjectGrantNotFound
+	}
+	return nil
+}
+
+// GetResourceOwner 獲取資源實例在指定租戶的擁有者登記
+func (r *MySQLObjectRepository) GetResourceOwner(ctx context.Context, tenantID int64, resource, objectID string) (*domain.ResourceOwner, error) {
+	return getResourceOwner(r.db.WithContext(ctx).Where("tenant_id = ?", tenantID), resource, objectID)
+}
+
+// SetResourceOwner 登記或變更資源實例在租戶內的擁有者
+func (r *MySQLObjectRepository) SetResourceOwner(ctx context.Context, owner *domain.ResourceOwner) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"owner_id", "updated_at"})}).
+		Create(owner).Error
+}
+
+// DeleteResourceOwner 移除資源實例在租戶內的擁有者
+func (r *MySQLObjectRepository) DeleteResourceOwner(ctx context.Context, tenantID int64, resource, objectID string) error {
+	result := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND resource = ? AND object_id = ?", tenantID, resource, objectID).
+		Delete(&domain.ResourceOwner{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrResourceOwnerNotFound
+	}
+	return nil
+}
+
+// ListOwnerRules 列出所有擁有者規則
+func (r *MySQLObjectRepository) ListOwnerRules(ctx context.Context) ([]domain.OwnerRule, error) {
+	return listOwnerRules(r.db.WithContext(ctx))
+}
+
+// CreateOwnerRule 新增擁有者規則
+func (r *MySQLObjectRepository) CreateOwnerRule(ctx context.Context, rule *domain.OwnerRule) error {
+	result := r.db.WithContext(ctx).Create(rule)
+	if result.Error != nil {
+		if isDuplicateKeyError(result.Error) {
+			return domain.ErrOwnerRuleAlreadyExists
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// DeleteOwnerRule 刪除擁有者規則
+func (r *MySQLObjectRepository) DeleteOwnerRule(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&domain.OwnerRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrOwnerRuleNotFound
+	}
+	return nil
+}
+
+// getResourceOwner 查詢資源實例的擁有者，租戶條件與排序由呼叫端在 db 上指定
+func getResourceOwner(db *gorm.DB, resource, objectID string) (*domain.ResourceOwner, error) {
+	var owner domain.ResourceOwner
+	result := db.Where("resource = ? AND object_id = ?", resource, objectID).First(&owner)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrResourceOwnerNotFound
+		}
+		return nil, result.Error
+	}
+	return &owner, nil
+}
+
+// listOwnerRules 查詢所有擁有者規則
+func listOwnerRules(db *gorm.DB) ([]domain.OwnerRule, error) {
+	rules := []domain.OwnerRule{}
+	if err := db.Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+)
+
+// likeEscaper 轉義 LIKE 查詢中的萬用字元
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// MySQLPermissionRepository MySQL 權限倉儲實作
+type MySQLPermissionRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLPermissionRepository 創建 MySQL 權限倉儲
+func NewMySQLPermissionRepository(db *gorm.DB) domain.PermissionRepository {
+	return &MySQLPermissionRepository{db: db}
+}
+
+// GetPermissionByID 根據權限 ID 獲取權限
+func (r *MySQLPermissionRepository) GetPermissionByID(ctx context.Context, id int64) (*domain.Permission, error) {
+	var permission domain.Permission
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&permission)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPermissionNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &permission, nil
+}
+
+// GetPermissionByResourceAction 根據資源與操作獲取權限
+func (r *MySQLPermissionRepository) GetPermissionByResourceAction(ctx context.Context, resource, action string) (*domain.Permission, error) {
+	var permission domain.Permission
+	result := r.db.WithContext(ctx).
+		Where("resource = ? AND action = ?", resource, action).
+		First(&permission)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPermissionNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &permission, nil
+}
+
+// ListPermissions 列出權限，可依資源前綴過濾
+func (r *MySQLPermissionRepository) ListPermissions(ctx context.Context, resourcePrefix string) ([]domain.Permission, error) {
+	permissions := []domain.Permission{}
+	query := r.db.WithContext(ctx)
+	if resourcePrefix != "" {
+		query = query.Where("resource LIKE ?", likeEscaper.Replace(resourcePrefix)+"%")
+	}
+
+	result := query.Order("resource").Order("action").Find(&permissions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return permissions, nil
+}
+
+// CreatePermission 創建權限
+func (r *MySQLPermissionRepository) CreatePermission(ctx context.Context, permission *domain.Permission) (*domain.Permission, error) {
+	result := r.db.WithContext(ctx).Create(permission)
+	if result.Error != nil {
+		if isDuplicateKeyError(result.Error) {
+			return nil, domain.ErrPermissionAlreadyExists
+		}
+		return nil, result.Error
+	}
+
+	return permission, nil
+}
+
+// UpdatePermission 根據權限 ID 更新權限信息，可 partial update
+func (r *MySQLPermissionRepository) UpdatePermission(ctx context.Context, id int64, updateFields map[string]interface{}) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Permission{}).
+		Where("id = ?", id).
+		Updates(updateFields)
+
+	if result.Error != nil {
+		if isDuplicateKeyError(result.Error) {
+			return domain.ErrPermissionAlreadyExists
+		}
+		return result.Error
+	}
+
+	// 內容未變動時 MySQL 回報的 RowsAffected 為 0，存在性由服務層先行檢查
+	return nil
+}
+
+// DeletePermission 刪除權限及其角色與用戶分配
+func (r *MySQLPermissionRepository) DeletePermission(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("permission_id = ?", id).Delete(&domain.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("permission_id = ?", id).Delete(&domain.UserPermission{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", id).Delete(&domain.Permission{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// 檢查是否有實際刪除
+		if result.RowsAffected == 0 {
+			return domain.ErrPermissionNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+)
+
+// MySQLRefreshTokenRepository MySQL 刷新令牌倉儲實作
+type MySQLRefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLRefreshTokenRepository 創建 MySQL 刷新令牌倉儲
+func NewMySQLRefreshTokenRepository(db *gorm.DB) domain.RefreshTokenRepository {
+	return &MySQLRefreshTokenRepository{db: db}
+}
+
+// CreateRefreshToken 保存新的刷新令牌
+func (r *MySQLRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// GetRefreshTokenByHash 根據雜湊值獲取刷新令牌
+func (r *MySQLRefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	result := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, result.Error
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken 以條件更新標記令牌已輪替，並發請求中只有一個能成功
+func (r *MySQLRefreshTokenRepository) RotateRefreshToken(ctx context.Context, id int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.RefreshToken{}).
+		Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", id).
+		Update("rotated_at", time.Now())
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeRefreshTokenFamily 撤銷同一 family 下所有尚未撤銷的令牌
+func (r *MySQLRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserRefreshTokens 撤銷用戶所有尚未撤銷的令牌
+func (r *MySQLRefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
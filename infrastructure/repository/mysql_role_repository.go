@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MySQLRoleRepository MySQL 角色倉儲實作
+type MySQLRoleRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLRoleRepository 創建 MySQL 角色倉儲
+func NewMySQLRoleRepository(db *gorm.DB) domain.RoleRepository {
+	return &MySQLRoleRepository{db: db}
+}
+
+// GetRoleByID 根據角色 ID 獲取角色，包含其權限（含效果）與直接父角色
+func (r *MySQLRoleRepository) GetRoleByID(ctx context.Context, id int64) (*domain.Role, error) {
+	var role domain.Role
+	result := r.db.WithContext(ctx).Preload("Parents").Where("id = ?", id).First(&role)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, result.Error
+	}
+
+	// 關聯預載不會帶出關聯表的欄位，權限改以 JOIN 查詢以取得分配的效果與條件
+	result = r.db.WithContext(ctx).
+		Select("permissions.*", "role_permissions.effect", "role_permissions.condition_expr").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Where("role_permissions.role_id = ?", id).
+		Order("permissions.id").
+		Find(&role.Permissions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &role, nil
+}
+
+// GetRoleByName 根據角色名稱獲取角色
+func (r *MySQLRoleRepository) GetRoleByName(ctx context.Context, name string) (*domain.Role, error) {
+	var role domain.Role
+	result := r.db.WithContext(ctx).Where("name = ?", name).First(&role)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &role, nil
+}
+
+// ListRoles 列出所有角色
+func (r *MySQLRoleRepository) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	roles := []domain.Role{}
+	result := r.db.WithContext(ctx).Order("id").Find(&roles)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return roles, nil
+}
+
+// CreateRole 創建角色
+func (r *MySQLRoleRepository) CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	// 權限分配與繼承關聯走各自的 API，這裡不寫入
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Create(role)
+	if result.Error != nil {
+		if isDuplicateKeyError(result.Error) {
+			return nil, domain.ErrRoleAlreadyExists
+		}
+		return nil, result.Error
+	}
+
+	return role, nil
+}
+
+// UpdateRole 根據角色 ID 更新角色信息，可 partial update
+func (r *MySQLRoleRepository) UpdateRole(ctx context.Context, id int64, updateFields map[string]interface{}) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Role{}).
+		Where("id = ?", id).
+		Updates(updateFields)
+
+	if result.Error != nil {
+		if isDuplicateKeyError(result.Error) {
+			return domain.ErrRoleAlreadyExists
+		}
+		return result.Error
+	}
+
+	// 內容未變動時 MySQL 回報的 RowsAffected 為 0，存在性由服務層先行檢查
+	return nil
+}
+
+// DeleteRole 刪除角色及其關聯
+func (r *MySQLRoleRepository) DeleteRole(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&domain.UserRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&domain.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ? OR parent_id = ?", id, id).Delete(&domain.RoleParent{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", id).Delete(&domain.Role{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// 檢查是否有實際刪除
+		if result.RowsAffected == 0 {
+			return domain.ErrRoleNotFound
+		}
+		return nil
+	})
+}
+
+// CountRoleUsers 統計被分配該角色的用戶數，同一用戶在多個租戶被分配只計一次
+func (r *MySQLRoleRepository) CountRoleUsers(ctx context.Context, id int64) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&domain.UserRole{}).Where("role_id = ?", id).Distinct("user_id").Count(&count)
+	return count, result.Error
+}
+
+// AssignRoleToUser 依 assignment 的租戶與有效期間為用戶分配角色，已過期但尚未清除的相同分配會先被移除
+func (r *MySQLRoleRepository) AssignRoleToUser(ctx context.Context, assignment *domain.UserRole) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return assignUserRole(tx, assignment)
+	})
+}
+
+// RemoveRoleFromUser 移除用戶在租戶內的角色
+func (r *MySQLRoleRepository) RemoveRoleFromUser(ctx context.Context, userID, roleID, tenantID int64) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND role_id = ? AND tenant_id = ?", userID, roleID, tenantID).
+		Delete(&domain.UserRole{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// 檢查是否有實際刪除
+	if result.RowsAffected == 0 {
+		return domain.ErrRoleNotAssigned
+	}
+	return nil
+}
+
+// ListExpiringUserRoles 列出在 (now, before] 期間內過期的角色分配及其用戶與角色名稱
+func (r *MySQLRoleRepository) ListExpiringUserRoles(ctx context.Context, now, before time.Time) ([]domain.RoleAssignment, error) {
+	assignments := []domain.RoleAssignment{}
+	result := r.db.WithContext(ctx).
+		Model(&domain.UserRole{}).
+		Select("user_roles.user_id, users.username, user_roles.role_id, roles.name AS role_name, "+
+			"user_roles.tenant_id, user_roles.valid_from, user_roles.valid_until").
+		Joins("JOIN users ON users.id = user_roles.user_id").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("user_roles.valid_until > ? AND user_roles.valid_until <= ?", now, before).
+		Order("user_roles.valid_until, user_roles.user_id, user_roles.role_id").
+		Scan(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
+}
+
+// DeleteExpiredUserRoles 清除已過期的角色分配，只刪除查詢時仍過期的分配，避免誤刪期間內被重新分配的角色
+func (r *MySQLRoleRepository) DeleteExpiredUserRoles(ctx context.Context, now time.Time) ([]domain.UserRole, error) {
+	var expired []domain.UserRole
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("valid_until <= ?", now).Order("user_id, role_id, tenant_id").Find(&expired).Error; err != nil {
+			return err
+		}
+		for _, assignment := range expired {
+			err := tx.Where("user_id = ? AND role_id = ? AND tenant_id = ? AND valid_until <= ?",
+				assignment.UserID, assignment.RoleID, assignment.TenantID, now).
+				Delete(&domain.UserRole{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// AssignPermissionToRole 以指定的效果與條件為角色分配權限
+func (r *MySQLRoleRepository) AssignPermissionToRole(ctx context.Context, roleID, permissionID int64, effect, condition string) error {
+	result := r.db.WithContext(ctx).Create(&domain.RolePermission{RoleID: roleID, PermissionID: permissionID, Effect: effect, Condition: condition})
+	if result.Error != nil {
+		if isDuplicateKeyError(result.Error) {
+			return domain.ErrPermissionAlreadyAssigned
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// RemovePermissionFromRole 移除角色的權限
+func (r *MySQLRoleRepository) RemovePermissionFromRole(ctx context.Context, roleID, permissionID int64) error {
+	result := r.db.WithContext(ctx).
+		Where("role_id = ? AND permission_id = ?", roleID, permissionID).
+		Delete(&domain.RolePermission{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// 檢查是否有實際刪除
+	if result.RowsAffected == 0 {
+		return domain.ErrPermissionNotAssigned
+	}
+	return nil
+}
+
+// AssignPermissionToUser 依 assignment 的租戶與效果直接為用戶分配權限
+func (r *MySQLRoleRepository) AssignPermissionToUser(ctx context.Context, assignment *domain.UserPermission) error {
+	result := r.db.WithContext(ctx).Create(assignment)
+	if result.Error != nil {
+		if isDuplicateKeyError(result.Error) {
+			return domain.ErrUserPermissionAlreadyAssigned
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// RemovePermissionFromUser 移除用戶在租戶內直接分配的權限
+func (r *MySQLRoleRepository) RemovePermissionFromUser(ctx context.Context, userID, permissionID, tenantID int64) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND permission_id = ? AND tenant_id = ?", userID, permissionID, tenantID).
+		Delete(&domain.UserPermission{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// 檢查是否有實際刪除
+	if result.RowsAffected == 0 {
+		return domain.ErrUserPermissionNotAssigned
+	}
+	return nil
+}
+
+// ListRoleParents 列出所有角色繼承關聯
+func (r *MySQLRoleRepository) ListRoleParents(ctx context.Context) ([]domain.RoleParent, error) {
+	return listRoleParents(r.db.WithContext(ctx))
+}
+
+// SetRoleParents 以 parentIDs 取代角色的直接父角色，寫入前在同一交易中檢查循環
+func (r *MySQLRoleRepository) SetRoleParents(ctx context.Context, roleID int64, parentIDs []int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 鎖定整個繼承關係，避免並行的兩次寫入各自通過檢查後合起來形成循環
+		edges, err := listRoleParents(tx.Clauses(clause.Locking{Strength: "UPDATE"}))
+		if err != nil {
+			return err
+		}
+		if domain.NewRoleHierarchy(edges).WouldCycle(roleID, parentIDs) {
+			return domain.ErrRoleHierarchyCycle
+		}
+
+		if err := tx.Where("role_id = ?", roleID).Delete(&domain.RoleParent{}).Error; err != nil {
+			return err
+		}
+		if len(parentIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]domain.RoleParent, 0, len(parentIDs))
+		for _, parentID := range parentIDs {
+			rows = append(rows, domain.RoleParent{RoleID: roleID, ParentID: parentID})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// listRoleParents 查詢所有角色繼承關聯，由角色倉儲與授權倉儲共用
+func listRoleParents(db *gorm.DB) ([]domain.RoleParent, error) {
+	edges := []domain.RoleParent{}
+	result := db.Order("role_id, parent_id").Find(&edges)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return edges, nil
+}
+
+// assignUserRole 建立角色分配，先移除已過期但尚未清除的相同分配；須在交易中呼叫
+func assignUserRole(tx *gorm.DB, assignment *domain.UserRole) error {
+	err := tx.Where("user_id = ? AND role_id = ? AND tenant_id = ? AND valid_until <= ?",
+		assignment.UserID, assignment.RoleID, assignment.TenantID, time.Now()).
+		Delete(&domain.UserRole{}).Error
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Create(assignment).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			return domain.ErrRoleAlreadyAssigned
+		}
+		return err
+	}
+	return nil
+}
+
+// activeUserRoles 限定在 now 時生效的角色分配：已開始且尚未過期
+func activeUserRoles(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(valid_from IS NULL OR valid_from <= ?) AND (valid_until IS NULL OR valid_until > ?)", now, now)
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+)
+
+// MySQLSessionRepository MySQL 登入會話倉儲實作
+type MySQLSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLSessionRepository 創建 MySQL 登入會話倉儲
+func NewMySQLSessionRepository(db *gorm.DB) domain.SessionRepository {
+	return &MySQLSessionRepository{db: db}
+}
+
+// CreateSession 保存新的會話
+func (r *MySQLSessionRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// GetSessionByID 根據會話 ID 獲取尚未過期的會話
+func (r *MySQLSessionRepository) GetSessionByID(ctx context.Context, id string) (*domain.Session, error) {
+	var session domain.Session
+	result := r.db.WithContext(ctx).Where("id = ? AND expires_at > ?", id, time.Now()).First(&session)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &session, nil
+}
+
+// ListUserSessions 獲取用戶尚未過期的會話，最近活動的在前
+func (r *MySQLSessionRepository) ListUserSessions(ctx context.Context, userID int64) ([]domain.Session, error) {
+	sessions := []domain.Session{}
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return sessions, nil
+}
+
+// TouchSession 更新會話的最後活動時間與過期時間
+func (r *MySQLSessionRepository) TouchSession(ctx context.Context, id string, lastSeenAt time.Time, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Session{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_seen_at": lastSeenAt,
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+// DeleteSession 刪除會話
+func (r *MySQLSessionRepository) DeleteSession(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.Session{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteUserSessions 刪除用戶的所有會話
+func (r *MySQLSessionRepository) DeleteUserSessions(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.Session{}).Error
+}
+
+// DeleteExpiredSessions 清除已過期的會話
+func (r *MySQLSessionRepository) DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&domain.Session{})
+	return result.RowsAffected, result.Error
+}
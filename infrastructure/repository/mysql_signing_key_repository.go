@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MySQLSigningKeyRepository MySQL 簽章金鑰輪替狀態倉儲實作
+type MySQLSigningKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLSigningKeyRepository 創建 MySQL 簽章金鑰輪替狀態倉儲
+func NewMySQLSigningKeyRepository(db *gorm.DB) domain.SigningKeyRepository {
+	return &MySQLSigningKeyRepository{db: db}
+}
+
+// ListSigningKeys 列出所有記錄過狀態的金鑰
+func (r *MySQLSigningKeyRepository) ListSigningKeys(ctx context.Context) ([]domain.SigningKey, error) {
+	keys := []domain.SigningKey{}
+	result := r.db.WithContext(ctx).Order("kid").Find(&keys)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return keys, nil
+}
+
+// PromoteSigningKey 在同一交易中將原本的 current 降為 active，再將指定金鑰設為 current
+func (r *MySQLSigningKeyRepository) PromoteSigningKey(ctx context.Context, kid string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&domain.SigningKey{}).
+			Where("status = ? AND kid <> ?", domain.SigningKeyStatusCurrent, kid).
+			Updates(map[string]interface{}{"status": domain.SigningKeyStatusActive, "updated_at": now}).Error; err != nil {
+			return err
+		}
+
+		return r.upsert(tx, &domain.SigningKey{Kid: kid, Status: domain.SigningKeyStatusCurrent, UpdatedAt: now})
+	})
+}
+
+// RetireSigningKey 將金鑰設為 retired 並記錄退役時間
+func (r *MySQLSigningKeyRepository) RetireSigningKey(ctx context.Context, kid string, retireAt time.Time) error {
+	return r.upsert(r.db.WithContext(ctx), &domain.SigningKey{
+		Kid:       kid,
+		Status:    domain.SigningKeyStatusRetired,
+		RetireAt:  &retireAt,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// upsert 寫入金鑰狀態，已存在時覆蓋狀態與退役時間
+func (r *MySQLSigningKeyRepository) upsert(tx *gorm.DB, key *domain.SigningKey) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "retire_at", "updated_at"}),
+	}).Create(key).Error
+}
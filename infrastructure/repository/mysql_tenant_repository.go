@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+)
+
+// MySQLTenantRepository MySQL 租戶倉儲實作
+type MySQLTenantRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLTenantRepository 創建 MySQL 租戶倉儲
+func NewMySQLTenantRepository(db *gorm.DB) domain.TenantRepository {
+	return &MySQLTenantRepository{db: db}
+}
+
+// ListTenants 列出所有租戶
+func (r *MySQLTenantRepository) ListTenants(ctx context.Context) ([]domain.Tenant, error) {
+	tenants := []domain.Tenant{}
+	if err := r.db.WithContext(ctx).Order("id").Find(&tenants).Error; err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// GetTenantByID 根據 ID 獲取租戶
+func (r *MySQLTenantRepository) GetTenantByID(ctx context.Context, id int64) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&tenant)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTenantNotFound
+		}
+		return nil, result.Error
+	}
+	return &tenant, nil
+}
+
+// CreateTenant 創建租戶
+func (r *MySQLTenantRepository) CreateTenant(ctx context.Context, tenant *domain.Tenant) error {
+	result := r.db.WithContext(ctx).Create(tenant)
+	if result.Error != nil {
+		if isDuplicateKeyError(result.Error) {
+			return domain.ErrTenantAlreadyExists
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// UpdateTenant 更新租戶的名稱與描述，存在性由服務層先行檢查
+func (r *MySQLTenantRepository) UpdateTenant(ctx context.Context, id int64, updateFields map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Tenant{}).
+		Where("id = ?", id).
+		Updates(updateFields).Error
+}
+
+// DeleteTenant 刪除租戶及其成員，以及租戶內的角色分配、直接權限分配、實例授權與擁有者登記
+func (r *MySQLTenantRepository) DeleteTenant(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{
+			&domain.UserRole{},
+			&domain.UserPermission{},
+			&domain.ObjectGrant{},
+			&domain.ResourceOwner{},
+			&domain.TenantMember{},
+		} {
+			if err := tx.Where("tenant_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Where("id = ?", id).Delete(&domain.Tenant{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrTenantNotFound
+		}
+		return nil
+	})
+}
+
+// ListTenantMembers 列出租戶的成員及其全域與租戶內的角色
+func (r *MySQLTenantRepository) ListTenantMembers(ctx context.Context, tenantID int64) ([]domain.User, error) {
+	db := r.db.WithContext(ctx)
+	users := []domain.User{}
+	result := db.
+		Where("id IN (?)", db.Model(&domain.TenantMember{}).Select("user_id").Where("tenant_id = ?", tenantID)).
+		Order("id").
+		Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if err := loadUserRoles(db, users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// AddTenantMember 加入租戶成員
+func (r *MySQLTenantRepository) AddTenantMember(ctx context.Context, tenantID, userID int64) error {
+	result := r.db.WithContext(ctx).Create(&domain.TenantMember{TenantID: tenantID, UserID: userID})
+	if result.Error != nil {
+		if isDuplicateKeyError(result.Error) {
+			return domain.ErrTenantMemberAlreadyExists
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// RemoveTenantMember 移除租戶成員及其在該租戶內的角色分配、直接權限分配與實例授權。
+// 擁有者登記記錄實例由誰建立，予以保留；非成員無法以該租戶請求，登記不會生效
+func (r *MySQLTenantRepository) RemoveTenantMember(ctx context.Context, tenantID, userID int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("tenant_id = ? AND user_id = ?", tenantID, userID).Delete(&domain.TenantMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotTenantMember
+		}
+
+		for _, model := range []interface{}{&domain.UserRole{}, &domain.UserPermission{}, &domain.ObjectGrant{}} {
+			if err := tx.Where("tenant_id = ? AND user_id = ?", tenantID, userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// getTenantByCode 以代碼查詢租戶
+func getTenantByCode(db *gorm.DB, code string) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	result := db.Where("code = ?", code).First(&tenant)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTenantNotFound
+		}
+		return nil, result.Error
+	}
+	return &tenant, nil
+}
+
+// isTenantMember 查詢用戶是否為租戶成員
+func isTenantMember(db *gorm.DB, tenantID, userID int64) (bool, error) {
+	var count int64
+	result := db.Model(&domain.TenantMember{}).
+		Where("tenant_id = ? AND user_id = ?", tenantID, userID).
+		Count(&count)
+	return count > 0, result.Error
+}
+
+// loadUserRoles 載入用戶目前生效的角色分配，全域分配填入 Roles，租戶內的分配依租戶分組填入 TenantRoles
+func loadUserRoles(db *gorm.DB, users []domain.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	userIDs := make([]int64, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	var assignments []domain.UserRole
+	if err := db.Where("user_id IN ?", userIDs).Scopes(activeUserRoles(time.Now())).Order("tenant_id, role_id").Find(&assignments).Error; err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	roleIDs := make([]int64, 0, len(assignments))
+	tenantIDs := []int64{}
+	for _, assignment := range assignments {
+		roleIDs = append(roleIDs, assignment.RoleID)
+		if assignment.TenantID != domain.GlobalTenantID {
+			tenantIDs = append(tenantIDs, assignment.TenantID)
+		}
+	}
+
+	var roles []domain.Role
+	if err := db.Where("id IN ?", roleIDs).Find(&roles).Error; err != nil {
+		return err
+	}
+	roleByID := make(map[int64]domain.Role, len(roles))
+	for _, role := range roles {
+		roleByID[role.ID] = role
+	}
+
+	tenantCodes := map[int64]string{}
+	if len(tenantIDs) > 0 {
+		var tenants []domain.Tenant
+		if err := db.Where("id IN ?", tenantIDs).Find(&tenants).Error; err != nil {
+			return err
+		}
+		for _, tenant := range tenants {
+			tenantCodes[tenant.ID] = tenant.Code
+		}
+	}
+
+	index := make(map[int64]int, len(users))
+	for i, user := range users {
+		index[user.ID] = i
+	}
+	for _, assignment := range assignments {
+		role, ok := roleByID[assignment.RoleID]
+		if !ok {
+			continue
+		}
+		user := &users[index[assignment.UserID]]
+		if assignment.TenantID == domain.GlobalTenantID {
+			user.Roles = append(user.Roles, role)
+			continue
+		}
+		// assignments 依 tenant_id 排序，同一租戶的分配相鄰
+		if n := len(user.TenantRoles); n == 0 || user.TenantRoles[n-1].TenantID != assignment.TenantID {
+			user.TenantRoles = append(user.TenantRoles, domain.TenantRoles{
+				TenantID: assignment.TenantID,
+				Tenant:   tenantCodes[assignment.TenantID],
+			})
+		}
+		tenantRoles := &user.TenantRoles[len(user.TenantRoles)-1]
+		tenantRoles.Roles = append(tenantRoles.Roles, role)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"rbac-service/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MySQLTokenRevocationRepository MySQL 訪問令牌撤銷記錄倉儲實作
+type MySQLTokenRevocationRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLTokenRevocationRepository 創建 MySQL 訪問令牌撤銷記錄倉儲
+func NewMySQLTokenRevocationRepository(db *gorm.DB) domain.TokenRevocationRepository {
+	return &MySQLTokenRevocationRepository{db: db}
+}
+
+// RevokeToken 記錄已撤銷的 jti，已存在時略過
+func (r *MySQLTokenRevocationRepository) RevokeToken(ctx context.Context, token *domain.RevokedToken) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(token).Error
+}
+
+// RevokeUserTokens 寫入用戶層級的撤銷時間點，已存在時以新的時間點覆蓋
+func (r *MySQLTokenRevocationRepository) RevokeUserTokens(ctx context.Context, revocation *domain.UserTokenRevocation) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "username"}},
+			DoUpdates: clause.AssignmentColumns([]string{"revoked_before", "expires_at"}),
+		}).
+		Create(revocation).Error
+}
+
+// IsTokenRevoked 檢查 jti 是否已撤銷，或其簽發時間是否早於用戶的撤銷時間點
+func (r *MySQLTokenRevocationRepository) IsTokenRevoked(ctx context.Context, jti string, username string, issuedAt time.Time) (bool, error) {
+	now := time.Now()
+
+	var count int64
+	if jti != "" {
+		result := r.db.WithContext(ctx).
+			Model(&domain.RevokedToken{}).
+			Where("jti = ? AND expires_at > ?", jti, now).
+			Count(&count)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&domain.UserTokenRevocation{}).
+		Where("username = ? AND revoked_before >= ? AND expires_at > ?", username, issuedAt, now).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return count > 0, nil
+}
+
+// RevokeSessions 記錄已終止的會話，已存在時略過
+func (r *MySQLTokenRevocationRepository) RevokeSessions(ctx context.Context, sessionIDs []string, expiresAt time.Time) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	sessions := make([]domain.RevokedSession, 0, len(sessionIDs))
+	for _, id := range sessionIDs {
+		sessions = append(sessions, domain.RevokedSession{SessionID: id, ExpiresAt: expiresAt})
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&sessions).Error
+}
+
+// ListActiveRevocations 列出尚未過期的令牌、用戶與會話撤銷記錄
+func (r *MySQLTokenRevocationRepository) ListActiveRevocations(ctx context.Context, now time.Time) (*domain.ActiveRevocations, error) {
+	revocations := &domain.ActiveRevocations{}
+	db := r.db.WithContext(ctx)
+	if err := db.Where("expires_at > ?", now).Find(&revocations.Tokens).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("expires_at > ?", now).Find(&revocations.Users).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("expires_at > ?", now).Find(&revocations.Sessions).Error; err != nil {
+		return nil, err
+	}
+
+	return revocations, nil
+}
+
+// DeleteExpiredRevocations 清除已過期的撤銷記錄
+func (r *MySQLTokenRevocationRepository) DeleteExpiredRevocations(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("expires_at <= ?", now).Delete(&domain.RevokedToken{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted += result.RowsAffected
+
+		result = tx.Where("expires_at <= ?", now).Delete(&domain.UserTokenRevocation{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted += result.RowsAffected
+
+		result = tx.Where("expires_at <= ?", now).Delete(&domain.RevokedSession{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted += result.RowsAffected
+		return nil
+	})
+
+	return deleted, err
+}
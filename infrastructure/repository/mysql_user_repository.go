@@ -3,10 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"rbac-service/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // MySQLUserRepository MySQL 用戶倉儲實作
@@ -21,17 +24,21 @@ func NewMySQLUserRepository(db *gorm.DB) domain.UserRepository {
 
 // GetByID 根據用戶 ID 獲取用戶信息
 func (r *MySQLUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	var user domain.User
-	result := r.db.WithContext(ctx).Where("id = ?", id).First(&user)
+	db := r.db.WithContext(ctx)
+	users := []domain.User{}
+	result := db.Where("id = ?", id).Limit(1).Find(&users)
 
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, domain.ErrUserNotFound
-		}
 		return nil, result.Error
 	}
+	if len(users) == 0 {
+		return nil, domain.ErrUserNotFound
+	}
+	if err := loadUserRoles(db, users); err != nil {
+		return nil, err
+	}
 
-	return &user, nil
+	return &users[0], nil
 }
 
 // GetByUsername 根據用戶 username 獲取用戶信息
@@ -68,46 +75,123 @@ func (r *MySQLUserRepository) UpdateUser(ctx context.Context, username string, u
 	return nil
 }
 
-// DeleteUserJwt 刪除使用者的 Jwt token
-func (r *MySQLUserRepository) DeleteUserJwt(ctx context.Context, jwt string) error {
-	// 執行更新
-	result := r.db.WithContext(ctx).
-		Model(&domain.User{}).
-		Where("jwt = ?", jwt).
-		Update("jwt", "")
+// DeleteUser by username，同時移除用戶的角色與權限分配、租戶成員資格、實例授權與擁有的資源登記、刷新令牌與會話
+func (r *MySQLUserRepository) DeleteUser(ctx context.Context, username string, revocation *domain.UserTokenRevocation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		userIDs := tx.Model(&domain.User{}).Select("id").Where("username = ?", username)
+		// 待審核的申請在申請人刪除後無法再核准，直接標記為 expired
+		err := tx.Model(&domain.AccessRequest{}).
+			Where("requester_id IN (?) AND status = ?", userIDs, domain.AccessRequestPending).
+			Updates(map[string]interface{}{
+				"status":     domain.AccessRequestExpired,
+				"decided_at": revocation.RevokedBefore,
+			}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", userIDs).Delete(&domain.UserRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", userIDs).Delete(&domain.UserPermission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", userIDs).Delete(&domain.TenantMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", userIDs).Delete(&domain.ObjectGrant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("owner_id IN (?)", userIDs).Delete(&domain.ResourceOwner{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", userIDs).Delete(&domain.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", userIDs).Delete(&domain.Session{}).Error; err != nil {
+			return err
+		}
 
-	if result.Error != nil {
-		return result.Error
+		result := tx.Where("username = ?", username).Delete(&domain.User{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// 檢查是否有實際刪除
+		if result.RowsAffected == 0 {
+			return domain.ErrUserNotFound
+		}
+
+		// 已簽發的訪問令牌在過期前仍可通過驗證，需一併撤銷
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "username"}},
+			DoUpdates: clause.AssignmentColumns([]string{"revoked_before", "expires_at"}),
+		}).Create(revocation).Error
+	})
+}
+
+// List 依條件查詢用戶，支援 offset 與游標分頁，並返回符合條件的總筆數
+func (r *MySQLUserRepository) List(ctx context.Context, filter domain.UserListFilter) ([]domain.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.User{})
+
+	if filter.Username != "" {
+		pattern := likeEscaper.Replace(filter.Username) + "%"
+		if filter.UsernameMatch == domain.UsernameMatchContains {
+			pattern = "%" + pattern
+		}
+		query = query.Where("username LIKE ?", pattern)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.RoleID > 0 {
+		query = query.Where("id IN (?)", r.db.Model(&domain.UserRole{}).Select("user_id").Where("role_id = ?", filter.RoleID).
+			Scopes(activeUserRoles(time.Now())))
+	}
+	if filter.TenantID > 0 {
+		query = query.Where("id IN (?)", r.db.Model(&domain.TenantMember{}).Select("user_id").Where("tenant_id = ?", filter.TenantID))
 	}
 
-	// 檢查是否有實際更新
-	if result.RowsAffected == 0 {
-		return domain.ErrUserNotFound
+	// 總筆數不受分頁影響
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
-	return nil
-}
 
-// DeleteUser by username
-func (r *MySQLUserRepository) DeleteUser(ctx context.Context, username string) error {
-	result := r.db.WithContext(ctx).
-		Where("username = ?", username).
-		Delete(&domain.User{})
+	// 以排序欄位加上 ID 作為穩定排序，游標取上一頁最後一筆之後的資料
+	sortColumn := domain.UserSortCreatedAt
+	if filter.SortBy == domain.UserSortUpdatedAt {
+		sortColumn = domain.UserSortUpdatedAt
+	}
+	direction, operator := "DESC", "<"
+	if filter.SortOrder == domain.SortOrderAsc {
+		direction, operator = "ASC", ">"
+	}
+	if filter.After != nil {
+		query = query.Where(
+			fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", sortColumn, operator),
+			filter.After.SortValue, filter.After.SortValue, filter.After.ID,
+		)
+	}
 
+	users := []domain.User{}
+	result := query.
+		Order(fmt.Sprintf("%s %s, id %s", sortColumn, direction, direction)).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&users)
 	if result.Error != nil {
-		return result.Error
+		return nil, 0, result.Error
 	}
-
-	// 檢查是否有實際刪除
-	if result.RowsAffected == 0 {
-		return domain.ErrUserNotFound
+	if err := loadUserRoles(r.db.WithContext(ctx), users); err != nil {
+		return nil, 0, err
 	}
 
-	return nil
+	return users, total, nil
 }
 
 // CreateUser 創建用戶
 func (r *MySQLUserRepository) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
-	// 只創建指定的欄位，排除 Roles
-	result := r.db.WithContext(ctx).Omit("Roles").Create(user)
+	// 角色不隨用戶創建，透過 user_roles 關聯 API 分配
+	result := r.db.WithContext(ctx).Create(user)
 	return user, result.Error
 }
@@ -0,0 +1,284 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 支援的簽章演算法
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+)
+
+const (
+	// ephemeralKeyPrefix 未設定金鑰時於啟動時隨機產生的臨時金鑰 kid 前綴
+	ephemeralKeyPrefix = "ephemeral-"
+	// minRSAKeyBits RSA 金鑰長度下限
+	minRSAKeyBits = 2048
+	// minHMACSecretLength HS256 共享密鑰長度下限
+	minHMACSecretLength = 32
+)
+
+// keys 目前載入的金鑰組，未設定時只有啟動時隨機產生的臨時金鑰
+var keys = newEphemeralKeySet()
+
+// newEphemeralKeySet 產生只存在於記憶體的 ES256 金鑰組。重啟後以其簽發的令牌全部失效，
+// 且各實例的金鑰不同，多實例部署必須設定金鑰
+func newEphemeralKeySet() *keySet {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("generate ephemeral signing key: %v", err))
+	}
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		panic(fmt.Sprintf("generate ephemeral signing key id: %v", err))
+	}
+
+	key := &SigningKey{
+		ID:        ephemeralKeyPrefix + hex.EncodeToString(suffix),
+		Algorithm: AlgES256,
+		method:    jwt.SigningMethodES256,
+		signKey:   privateKey,
+		verifyKey: &privateKey.PublicKey,
+	}
+	return newKeySet([]*SigningKey{key}, key.ID)
+}
+
+// SigningKey JWT 簽章金鑰，ID 會寫入令牌標頭的 kid
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// NewSigningKey 建立簽章金鑰，HS256 的 material 為共享密鑰，RS256 與 ES256 為 PEM 格式私鑰
+func NewSigningKey(kid string, algorithm string, material []byte) (*SigningKey, error) {
+	if kid == "" {
+		return nil, errors.New("kid is required")
+	}
+
+	key := &SigningKey{ID: kid, Algorithm: algorithm}
+	switch algorithm {
+	case AlgHS256:
+		if len(material) < minHMACSecretLength {
+			return nil, fmt.Errorf("HS256 secret must be at least %d bytes", minHMACSecretLength)
+		}
+		key.method = jwt.SigningMethodHS256
+		key.signKey = material
+		key.verifyKey = material
+	case AlgRS256:
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(material)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA private key: %w", err)
+		}
+		if privateKey.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		key.method = jwt.SigningMethodRS256
+		key.signKey = privateKey
+		key.verifyKey = &privateKey.PublicKey
+	case AlgES256:
+		privateKey, err := jwt.ParseECPrivateKeyFromPEM(material)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC private key: %w", err)
+		}
+		if privateKey.Curve != elliptic.P256() {
+			return nil, errors.New("ES256 requires a P-256 key")
+		}
+		key.method = jwt.SigningMethodES256
+		key.signKey = privateKey
+		key.verifyKey = &privateKey.PublicKey
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+
+	return key, nil
+}
+
+// keySet 可輪替的金鑰組：只以 current 簽發，未退役或仍在寬限期內的金鑰都可驗證
+type keySet struct {
+	mu        sync.RWMutex
+	keys      map[string]*SigningKey
+	order     []string
+	currentID string
+	// retireAt 金鑰的退役時間，之後以該金鑰簽發的令牌一律拒絕
+	retireAt map[string]time.Time
+}
+
+func newKeySet(signingKeys []*SigningKey, currentID string) *keySet {
+	set := &keySet{
+		keys:      make(map[string]*SigningKey, len(signingKeys)),
+		currentID: currentID,
+		retireAt:  map[string]time.Time{},
+	}
+	for _, key := range signingKeys {
+		set.keys[key.ID] = key
+		set.order = append(set.order, key.ID)
+	}
+	return set
+}
+
+// SetSigningKeys 載入金鑰組並指定簽發用的金鑰，須在 SetTokenOptions 之後於服務啟動時呼叫
+func SetSigningKeys(signingKeys []*SigningKey, currentID string) error {
+	for _, key := range signingKeys {
+		if !algorithmAllowed(key.Algorithm) {
+			return fmt.Errorf("signing key %s uses disallowed algorithm %s", key.ID, key.Algorithm)
+		}
+	}
+
+	set := newKeySet(signingKeys, currentID)
+	if len(set.keys) != len(signingKeys) {
+		return errors.New("duplicate kid")
+	}
+	if _, ok := set.keys[currentID]; !ok {
+		return fmt.Errorf("signing key %s not loaded", currentID)
+	}
+
+	keys.mu.Lock()
+	defer keys.mu.Unlock()
+	keys.keys, keys.order, keys.currentID, keys.retireAt = set.keys, set.order, set.currentID, set.retireAt
+	return nil
+}
+
+// HasSigningKey 檢查金鑰是否已載入
+func HasSigningKey(kid string) bool {
+	keys.mu.RLock()
+	defer keys.mu.RUnlock()
+	_, ok := keys.keys[kid]
+	return ok
+}
+
+// CurrentSigningKeyID 返回目前用於簽發的金鑰 kid
+func CurrentSigningKeyID() string {
+	keys.mu.RLock()
+	defer keys.mu.RUnlock()
+	return keys.currentID
+}
+
+// SigningKeyIDs 依載入順序返回所有金鑰的 kid
+func SigningKeyIDs() []string {
+	keys.mu.RLock()
+	defer keys.mu.RUnlock()
+	return append([]string(nil), keys.order...)
+}
+
+// UpdateSigningKeyStates 套用輪替狀態：以 currentID 簽發新令牌，retireAt 內的金鑰到期後不再接受。
+// 未載入的 kid 會被略過，簽發用的金鑰不會被退役
+func UpdateSigningKeyStates(currentID string, retireAt map[string]time.Time) error {
+	keys.mu.Lock()
+	defer keys.mu.Unlock()
+	if _, ok := keys.keys[currentID]; !ok {
+		return fmt.Errorf("signing key %s not loaded", currentID)
+	}
+
+	states := make(map[string]time.Time, len(retireAt))
+	for kid, at := range retireAt {
+		if _, ok := keys.keys[kid]; ok && kid != currentID {
+			states[kid] = at
+		}
+	}
+	keys.currentID = currentID
+	keys.retireAt = states
+	return nil
+}
+
+// SigningKeyRetireAt 返回金鑰的退役時間，未排定退役時返回 false
+func SigningKeyRetireAt(kid string) (time.Time, bool) {
+	keys.mu.RLock()
+	defer keys.mu.RUnlock()
+	at, ok := keys.retireAt[kid]
+	return at, ok
+}
+
+// currentSigningKey 返回目前用於簽發的金鑰
+func currentSigningKey() *SigningKey {
+	keys.mu.RLock()
+	defer keys.mu.RUnlock()
+	return keys.keys[keys.currentID]
+}
+
+// verifyingKey 返回可用於驗證的金鑰，不存在或已退役時返回 false
+func verifyingKey(kid string, now time.Time) (*SigningKey, bool) {
+	keys.mu.RLock()
+	defer keys.mu.RUnlock()
+	key, ok := keys.keys[kid]
+	if !ok {
+		return nil, false
+	}
+	if retireAt, retiring := keys.retireAt[kid]; retiring && !now.Before(retireAt) {
+		return nil, false
+	}
+	return key, true
+}
+
+// JSONWebKey 公鑰的 JWK 表示（RFC 7517）
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet JWKS 文件
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// PublicJWKS 返回仍可用於驗證的公鑰，已退役的金鑰與 HS256 共享密鑰不會出現在其中
+func PublicJWKS() JSONWebKeySet {
+	keySet := JSONWebKeySet{Keys: []JSONWebKey{}}
+	now := time.Now()
+	for _, kid := range SigningKeyIDs() {
+		key, ok := verifyingKey(kid, now)
+		if !ok {
+			continue
+		}
+		if jwk, ok := key.publicJWK(); ok {
+			keySet.Keys = append(keySet.Keys, jwk)
+		}
+	}
+	return keySet
+}
+
+// publicJWK 將公鑰轉換為 JWK，對稱金鑰返回 false
+func (k *SigningKey) publicJWK() (JSONWebKey, bool) {
+	jwk := JSONWebKey{Kid: k.ID, Use: "sig", Alg: k.Algorithm}
+
+	switch publicKey := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case *ecdsa.PublicKey:
+		// 座標依曲線長度補零，P-256 固定 32 bytes
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = publicKey.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size)))
+	default:
+		return JSONWebKey{}, false
+	}
+
+	return jwk, true
+}
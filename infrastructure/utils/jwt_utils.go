@@ -1,81 +1,221 @@
 package utils
 
 import (
-	"context"
 	"errors"
-	"rbac-service/domain"
+	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
+	"rbac-service/domain"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var userRepo domain.UserRepository
+// AccessTokenTTL 訪問令牌有效期
+const AccessTokenTTL = 2 * time.Hour
 
-func NewUserRepo(repo domain.UserRepository) {
-	userRepo = repo
+// Claims 訪問令牌的 claims，sub 為用戶 ID、sid 為所屬登入會話的 ID，tenant 為登入時綁定的租戶代碼
+type Claims struct {
+	Username  string   `json:"username"`
+	Roles     []string `json:"role"`
+	SessionID string   `json:"sid,omitempty"`
+	Tenant    string   `json:"tenant,omitempty"`
+	jwt.RegisteredClaims
 }
 
-// JWT 密鑰
-var jwtKey = []byte("jwt_for_rcba_login")
+// TokenOptions 訪問令牌的簽發與驗證設定
+type TokenOptions struct {
+	// Issuer 寫入並驗證 iss，空字串表示不驗證
+	Issuer string
+	// Audience 寫入並驗證 aud，空字串表示不驗證
+	Audience string
+	// Leeway 驗證 exp、nbf、iat 時容許的時鐘誤差
+	Leeway time.Duration
+	// Algorithms 允許的簽章演算法，空清單表示允許所有支援的演算法
+	Algorithms []string
+}
 
-// GenerateJWTToken 生成 JWT token
-func GenerateJWTToken(username string, roles []string) (string, error) {
-	claims := jwt.MapClaims{
-		"username": username,
-		"role":     roles,
-		"exp":      jwt.NewNumericDate(time.Now().Add(time.Hour * 2)),
+// DefaultTokenOptions 返回預設的令牌設定
+func DefaultTokenOptions() TokenOptions {
+	return TokenOptions{
+		Issuer:     "rbac-service",
+		Audience:   "rbac-service",
+		Leeway:     30 * time.Second,
+		Algorithms: []string{AlgHS256, AlgRS256, AlgES256},
+	}
+}
+
+var (
+	tokenOptionsMu sync.RWMutex
+	tokenOptions   = DefaultTokenOptions()
+)
+
+// SetTokenOptions 設定令牌的簽發與驗證方式，服務啟動時呼叫
+func SetTokenOptions(options TokenOptions) error {
+	if options.Leeway < 0 {
+		return errors.New("leeway must not be negative")
+	}
+	if len(options.Algorithms) == 0 {
+		options.Algorithms = DefaultTokenOptions().Algorithms
+	}
+	for _, algorithm := range options.Algorithms {
+		if algorithm != AlgHS256 && algorithm != AlgRS256 && algorithm != AlgES256 {
+			return fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+		}
 	}
+	options.Algorithms = append([]string(nil), options.Algorithms...)
 
-	// jwt 加密方式
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)
+	tokenOptionsMu.Lock()
+	defer tokenOptionsMu.Unlock()
+	tokenOptions = options
+	return nil
+}
+
+// currentTokenOptions 返回目前的令牌設定
+func currentTokenOptions() TokenOptions {
+	tokenOptionsMu.RLock()
+	defer tokenOptionsMu.RUnlock()
+	return tokenOptions
+}
+
+// algorithmAllowed 檢查演算法是否在允許清單中
+func algorithmAllowed(algorithm string) bool {
+	for _, allowed := range currentTokenOptions().Algorithms {
+		if allowed == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateJWTToken 生成 JWT token，sub 為用戶 ID，sid 為所屬登入會話的 ID
+func GenerateJWTToken(userID int64, username string, sessionID string, roles []string) (string, error) {
+	return GenerateTenantJWTToken(userID, username, sessionID, "", roles)
+}
+
+// GenerateTenantJWTToken 生成綁定租戶的 JWT token，tenant 為空時不綁定租戶
+func GenerateTenantJWTToken(userID int64, username string, sessionID string, tenant string, roles []string) (string, error) {
+	jti, err := randomString(16)
 	if err != nil {
 		return "", errors.New("token generation failed")
 	}
 
-	return tokenString, nil
-}
+	options := currentTokenOptions()
+	now := time.Now()
+	claims := &Claims{
+		Username:  username,
+		Roles:     roles,
+		SessionID: sessionID,
+		Tenant:    tenant,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Issuer:    options.Issuer,
+			Subject:   strconv.FormatInt(userID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
+		},
+	}
+	if options.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{options.Audience}
+	}
 
-// CompareJWTToken 比對 jwt 是否相同
-func CompareJWTToken(
-	ctx context.Context,
-	username string,
-	tokenNeedToCheck string,
-) (bool, error) {
-	user, err := userRepo.GetByUsername(ctx, username)
+	// 使用目前的簽章金鑰，並在標頭帶上 kid 供驗證端選擇公鑰
+	key := currentSigningKey()
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.ID
+	tokenString, err := token.SignedString(key.signKey)
 	if err != nil {
-		return false, errors.New("user not found")
+		return "", errors.New("token generation failed")
 	}
 
-	return tokenNeedToCheck == user.Jwt, nil
+	return tokenString, nil
 }
 
-// ParseJWTToken 解析 JWT token
-func ParseJWTToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
+// ParseJWTToken 驗證並解析 JWT token，失敗時返回 domain 中對應的令牌錯誤
+func ParseJWTToken(tokenString string) (*Claims, error) {
+	options := currentTokenOptions()
+	parserOptions := []jwt.ParserOption{
+		jwt.WithLeeway(options.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(options.Issuer))
+	}
+	if options.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(options.Audience))
+	}
 
+	claims := &Claims{}
+	token, err := jwt.NewParser(parserOptions...).ParseWithClaims(tokenString, claims, verificationKey)
 	if err != nil {
-		return nil, err
+		return nil, tokenError(err)
+	}
+	if !token.Valid {
+		return nil, domain.ErrInvalidJwt
+	}
+	if claims.ID == "" || claims.Subject == "" || claims.Username == "" {
+		return nil, domain.ErrTokenMissingClaims
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims, nil
+	return claims, nil
+}
+
+// tokenError 將 jwt 套件的驗證錯誤轉換為 domain 中的令牌錯誤
+func tokenError(err error) error {
+	// verificationKey 返回的錯誤已是 domain 錯誤
+	for _, target := range []error{domain.ErrTokenUnknownKey, domain.ErrTokenAlgorithmNotAllowed} {
+		if errors.Is(err, target) {
+			return target
+		}
 	}
 
-	return nil, errors.New("invalid token")
+	switch {
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return domain.ErrTokenMalformed
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return domain.ErrTokenSignatureInvalid
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return domain.ErrTokenMissingClaims
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return domain.ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return domain.ErrTokenNotYetValid
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return domain.ErrTokenInvalidIssuer
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return domain.ErrTokenInvalidAudience
+	case errors.Is(err, jwt.ErrTokenInvalidClaims):
+		return domain.ErrTokenMalformed
+	default:
+		return domain.ErrInvalidJwt
+	}
 }
 
-// IsTokenExpired 檢查 token 是否過期
-func IsTokenExpired(tokenString string) bool {
-	token, _ := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
+// verificationKey 依標頭的 kid 從金鑰組選擇驗證金鑰，未知、已退役或演算法不符時拒絕令牌
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	// 在驗證簽章前檢查演算法，避免以不允許的演算法（包含 none）驗證
+	if !algorithmAllowed(token.Method.Alg()) {
+		return nil, domain.ErrTokenAlgorithmNotAllowed
+	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok {
-		// 直接檢查過期時間
-		return time.Now().After(time.Unix(int64(claims["exp"].(float64)), 0))
+	// 沒有 kid 的令牌無法對應到已載入的金鑰，一律拒絕
+	kid, _ := token.Header["kid"].(string)
+	key, ok := verifyingKey(kid, time.Now())
+	if !ok {
+		return nil, domain.ErrTokenUnknownKey
 	}
-	return true
+	if token.Method.Alg() != key.Algorithm {
+		return nil, domain.ErrTokenAlgorithmNotAllowed
+	}
+
+	return key.verifyKey, nil
+}
+
+// IsTokenExpired 檢查 token 是否過期，無法解析的 token 同樣視為過期
+func IsTokenExpired(tokenString string) bool {
+	_, err := ParseJWTToken(tokenString)
+	return errors.Is(err, domain.ErrTokenExpired) || errors.Is(err, domain.ErrTokenMalformed)
 }
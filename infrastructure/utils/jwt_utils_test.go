@@ -0,0 +1,378 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"rbac-service/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useSigningKeys 在測試期間替換金鑰組並以第一把簽發，結束後還原
+func useSigningKeys(t *testing.T, signingKeys ...*SigningKey) {
+	keys.mu.RLock()
+	previous := &keySet{keys: keys.keys, order: keys.order, currentID: keys.currentID, retireAt: keys.retireAt}
+	keys.mu.RUnlock()
+
+	require.NoError(t, SetSigningKeys(signingKeys, signingKeys[0].ID))
+	t.Cleanup(func() {
+		keys.mu.Lock()
+		defer keys.mu.Unlock()
+		keys.keys, keys.order, keys.currentID, keys.retireAt = previous.keys, previous.order, previous.currentID, previous.retireAt
+	})
+}
+
+// useTokenOptions 在測試期間替換令牌設定，結束後還原
+func useTokenOptions(t *testing.T, options TokenOptions) {
+	previous := currentTokenOptions()
+	require.NoError(t, SetTokenOptions(options))
+	t.Cleanup(func() { require.NoError(t, SetTokenOptions(previous)) })
+}
+
+// signClaims 以金鑰簽署任意 claims，用於產生不合規的令牌
+func signClaims(t *testing.T, key *SigningKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.ID
+	tokenString, err := token.SignedString(key.signKey)
+	require.NoError(t, err)
+	return tokenString
+}
+
+// validClaims 返回符合預設設定的 claims
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"jti":      "jti-1",
+		"sub":      "1",
+		"sid":      "session-1",
+		"username": "testuser",
+		"iss":      "rbac-service",
+		"aud":      "rbac-service",
+		"iat":      now.Unix(),
+		"nbf":      now.Unix(),
+		"exp":      now.Add(time.Hour).Unix(),
+	}
+}
+
+func rsaPEM(t *testing.T, bits int) []byte {
+	privateKey, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+}
+
+func ecPEM(t *testing.T, curve elliptic.Curve) []byte {
+	privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func TestGenerateJWTToken_RS256(t *testing.T) {
+	key, err := NewSigningKey("rsa-1", AlgRS256, rsaPEM(t, 2048))
+	require.NoError(t, err)
+	useSigningKeys(t, key)
+
+	token, err := GenerateJWTToken(1, "testuser", "session-1", []string{"user"})
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "rsa-1", parsed.Header["kid"])
+	assert.Equal(t, AlgRS256, parsed.Method.Alg())
+
+	claims, err := ParseJWTToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "testuser", claims.Username)
+}
+
+func TestGenerateJWTToken_ES256(t *testing.T) {
+	key, err := NewSigningKey("ec-1", AlgES256, ecPEM(t, elliptic.P256()))
+	require.NoError(t, err)
+	useSigningKeys(t, key)
+
+	token, err := GenerateJWTToken(1, "testuser", "session-1", nil)
+	require.NoError(t, err)
+
+	claims, err := ParseJWTToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionID)
+}
+
+func TestDefaultSigningKeys_Ephemeral(t *testing.T) {
+	// 未設定金鑰時以隨機產生的 ES256 金鑰簽發，不再接受舊版寫死的共享密鑰
+	kid := CurrentSigningKeyID()
+	assert.True(t, strings.HasPrefix(kid, ephemeralKeyPrefix))
+	assert.Equal(t, []string{kid}, SigningKeyIDs())
+
+	token, err := GenerateJWTToken(1, "testuser", "session-1", nil)
+	require.NoError(t, err)
+	_, err = ParseJWTToken(token)
+	assert.NoError(t, err)
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"username": "admin"})
+	forgedString, err := forged.SignedString([]byte("jwt_for_rcba_login"))
+	require.NoError(t, err)
+	_, err = ParseJWTToken(forgedString)
+	assert.ErrorIs(t, err, domain.ErrTokenUnknownKey)
+
+	// 每次產生的金鑰皆不同
+	assert.NotEqual(t, kid, newEphemeralKeySet().currentID)
+}
+
+func TestParseJWTToken_RejectsOtherKeys(t *testing.T) {
+	key, err := NewSigningKey("rsa-1", AlgRS256, rsaPEM(t, 2048))
+	require.NoError(t, err)
+	useSigningKeys(t, key)
+
+	// 以舊版寫死的共享密鑰簽發、未帶 kid 的令牌
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"username": "testuser"}).SignedString([]byte("jwt_for_rcba_login"))
+	require.NoError(t, err)
+	_, err = ParseJWTToken(legacy)
+	assert.ErrorIs(t, err, domain.ErrTokenUnknownKey)
+
+	// 帶相同 kid 但改用 HS256，並以公鑰內容作為密鑰的演算法混淆攻擊
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"username": "admin"})
+	forged.Header["kid"] = "rsa-1"
+	forgedString, err := forged.SignedString(x509.MarshalPKCS1PublicKey(key.verifyKey.(*rsa.PublicKey)))
+	require.NoError(t, err)
+	_, err = ParseJWTToken(forgedString)
+	assert.ErrorIs(t, err, domain.ErrTokenAlgorithmNotAllowed)
+}
+
+func TestParseJWTToken_KeyRotation(t *testing.T) {
+	oldKey, err := NewSigningKey("old", AlgES256, ecPEM(t, elliptic.P256()))
+	require.NoError(t, err)
+	newKey, err := NewSigningKey("new", AlgES256, ecPEM(t, elliptic.P256()))
+	require.NoError(t, err)
+	useSigningKeys(t, oldKey, newKey)
+
+	oldToken, err := GenerateJWTToken(1, "testuser", "session-1", nil)
+	require.NoError(t, err)
+
+	// 提升新金鑰後只以新金鑰簽發，舊令牌仍可驗證
+	require.NoError(t, UpdateSigningKeyStates("new", nil))
+	newToken, err := GenerateJWTToken(1, "testuser", "session-1", nil)
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "new", parsed.Header["kid"])
+	_, err = ParseJWTToken(oldToken)
+	assert.NoError(t, err)
+
+	// 寬限期內仍接受舊金鑰
+	require.NoError(t, UpdateSigningKeyStates("new", map[string]time.Time{"old": time.Now().Add(time.Hour)}))
+	_, err = ParseJWTToken(oldToken)
+	assert.NoError(t, err)
+	assert.Len(t, PublicJWKS().Keys, 2)
+
+	// 退役後拒絕舊金鑰簽發的令牌，也不再公開其公鑰
+	require.NoError(t, UpdateSigningKeyStates("new", map[string]time.Time{"old": time.Now().Add(-time.Second)}))
+	_, err = ParseJWTToken(oldToken)
+	assert.Error(t, err)
+	_, err = ParseJWTToken(newToken)
+	assert.NoError(t, err)
+	jwks := PublicJWKS().Keys
+	require.Len(t, jwks, 1)
+	assert.Equal(t, "new", jwks[0].Kid)
+}
+
+func TestUpdateSigningKeyStates(t *testing.T) {
+	key, err := NewSigningKey("hs-1", AlgHS256, make([]byte, 32))
+	require.NoError(t, err)
+	useSigningKeys(t, key)
+
+	// 未載入的金鑰不可用於簽發
+	assert.Error(t, UpdateSigningKeyStates("missing", nil))
+	assert.Equal(t, "hs-1", CurrentSigningKeyID())
+
+	// 簽發用的金鑰不會被退役
+	require.NoError(t, UpdateSigningKeyStates("hs-1", map[string]time.Time{"hs-1": time.Now().Add(-time.Second)}))
+	token, err := GenerateJWTToken(1, "testuser", "session-1", nil)
+	require.NoError(t, err)
+	_, err = ParseJWTToken(token)
+	assert.NoError(t, err)
+}
+
+func TestGenerateJWTToken_StandardClaims(t *testing.T) {
+	key, err := NewSigningKey("hs-1", AlgHS256, make([]byte, 32))
+	require.NoError(t, err)
+	useSigningKeys(t, key)
+	useTokenOptions(t, TokenOptions{Issuer: "issuer-1", Audience: "audience-1"})
+
+	token, err := GenerateJWTToken(42, "testuser", "session-1", []string{"admin"})
+	require.NoError(t, err)
+
+	claims, err := ParseJWTToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "42", claims.Subject)
+	assert.Equal(t, "issuer-1", claims.Issuer)
+	assert.Equal(t, jwt.ClaimStrings{"audience-1"}, claims.Audience)
+	assert.Equal(t, []string{"admin"}, claims.Roles)
+	assert.NotEmpty(t, claims.ID)
+	require.NotNil(t, claims.IssuedAt)
+	require.NotNil(t, claims.NotBefore)
+	assert.Equal(t, AccessTokenTTL, claims.ExpiresAt.Sub(claims.IssuedAt.Time))
+}
+
+func TestParseJWTToken_ValidationErrors(t *testing.T) {
+	key, err := NewSigningKey("hs-1", AlgHS256, make([]byte, 32))
+	require.NoError(t, err)
+	useSigningKeys(t, key)
+	useTokenOptions(t, TokenOptions{Issuer: "rbac-service", Audience: "rbac-service", Leeway: time.Minute})
+
+	now := time.Now()
+	tests := []struct {
+		name   string
+		modify func(claims jwt.MapClaims)
+		want   error
+	}{
+		{name: "expired", modify: func(c jwt.MapClaims) { c["exp"] = now.Add(-2 * time.Minute).Unix() }, want: domain.ErrTokenExpired},
+		{name: "missing exp", modify: func(c jwt.MapClaims) { delete(c, "exp") }, want: domain.ErrTokenMissingClaims},
+		{name: "not yet valid", modify: func(c jwt.MapClaims) { c["nbf"] = now.Add(2 * time.Minute).Unix() }, want: domain.ErrTokenNotYetValid},
+		{name: "issued in future", modify: func(c jwt.MapClaims) { c["iat"] = now.Add(2 * time.Minute).Unix() }, want: domain.ErrTokenNotYetValid},
+		{name: "wrong issuer", modify: func(c jwt.MapClaims) { c["iss"] = "other" }, want: domain.ErrTokenInvalidIssuer},
+		{name: "missing issuer", modify: func(c jwt.MapClaims) { delete(c, "iss") }, want: domain.ErrTokenMissingClaims},
+		{name: "wrong audience", modify: func(c jwt.MapClaims) { c["aud"] = []string{"other"} }, want: domain.ErrTokenInvalidAudience},
+		{name: "missing sub", modify: func(c jwt.MapClaims) { delete(c, "sub") }, want: domain.ErrTokenMissingClaims},
+		{name: "missing jti", modify: func(c jwt.MapClaims) { delete(c, "jti") }, want: domain.ErrTokenMissingClaims},
+		{name: "exp wrong type", modify: func(c jwt.MapClaims) { c["exp"] = "tomorrow" }, want: domain.ErrTokenMalformed},
+		{name: "username wrong type", modify: func(c jwt.MapClaims) { c["username"] = 42 }, want: domain.ErrTokenMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.modify(claims)
+			token := signClaims(t, key, claims)
+
+			assert.NotPanics(t, func() {
+				_, err := ParseJWTToken(token)
+				assert.ErrorIs(t, err, tt.want)
+				IsTokenExpired(token)
+			})
+		})
+	}
+}
+
+func TestParseJWTToken_Leeway(t *testing.T) {
+	key, err := NewSigningKey("hs-1", AlgHS256, make([]byte, 32))
+	require.NoError(t, err)
+	useSigningKeys(t, key)
+	useTokenOptions(t, TokenOptions{Issuer: "rbac-service", Audience: "rbac-service", Leeway: time.Minute})
+
+	// 時鐘誤差內的過期與尚未生效仍接受
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-30 * time.Second).Unix()
+	claims["nbf"] = time.Now().Add(30 * time.Second).Unix()
+	_, err = ParseJWTToken(signClaims(t, key, claims))
+	assert.NoError(t, err)
+}
+
+func TestParseJWTToken_MalformedInput(t *testing.T) {
+	for _, token := range []string{"", "not-a-token", "a.b.c", "eyJhbGciOiJIUzI1NiJ9.eyJleHAiOiJ4In0.sig"} {
+		assert.NotPanics(t, func() {
+			_, err := ParseJWTToken(token)
+			assert.ErrorIs(t, err, domain.ErrTokenMalformed)
+			assert.True(t, IsTokenExpired(token))
+		})
+	}
+}
+
+func TestParseJWTToken_AlgorithmAllowList(t *testing.T) {
+	hmacKey, err := NewSigningKey("hs-1", AlgHS256, make([]byte, 32))
+	require.NoError(t, err)
+	rsaKey, err := NewSigningKey("rsa-1", AlgRS256, rsaPEM(t, 2048))
+	require.NoError(t, err)
+	useSigningKeys(t, rsaKey, hmacKey)
+	token := signClaims(t, hmacKey, validClaims())
+
+	// 不允許的演算法在驗證簽章前即被拒絕
+	useTokenOptions(t, TokenOptions{Issuer: "rbac-service", Audience: "rbac-service", Algorithms: []string{AlgRS256}})
+	_, err = ParseJWTToken(token)
+	assert.ErrorIs(t, err, domain.ErrTokenAlgorithmNotAllowed)
+
+	// alg 為 none 的令牌
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = ParseJWTToken(unsigned)
+	assert.ErrorIs(t, err, domain.ErrTokenAlgorithmNotAllowed)
+
+	// 不可載入使用不允許演算法的金鑰
+	assert.Error(t, SetSigningKeys([]*SigningKey{hmacKey}, hmacKey.ID))
+	assert.Error(t, SetTokenOptions(TokenOptions{Algorithms: []string{"none"}}))
+}
+
+func TestParseJWTToken_SignatureInvalid(t *testing.T) {
+	key, err := NewSigningKey("hs-1", AlgHS256, make([]byte, 32))
+	require.NoError(t, err)
+	useSigningKeys(t, key)
+
+	other, err := NewSigningKey("hs-1", AlgHS256, []byte("another-secret-with-at-least-32-bytes"))
+	require.NoError(t, err)
+	_, err = ParseJWTToken(signClaims(t, other, validClaims()))
+	assert.ErrorIs(t, err, domain.ErrTokenSignatureInvalid)
+}
+
+func TestNewSigningKey_InvalidMaterial(t *testing.T) {
+	tests := []struct {
+		name      string
+		kid       string
+		algorithm string
+		material  []byte
+	}{
+		{name: "missing kid", algorithm: AlgHS256, material: make([]byte, 32)},
+		{name: "short secret", kid: "hs-1", algorithm: AlgHS256, material: []byte("short")},
+		{name: "weak rsa", kid: "rsa-1", algorithm: AlgRS256, material: rsaPEM(t, 1024)},
+		{name: "not pem", kid: "rsa-1", algorithm: AlgRS256, material: []byte("not a key")},
+		{name: "wrong curve", kid: "ec-1", algorithm: AlgES256, material: ecPEM(t, elliptic.P384())},
+		{name: "unsupported", kid: "x", algorithm: "none", material: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSigningKey(tt.kid, tt.algorithm, tt.material)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPublicJWKS(t *testing.T) {
+	// 共享密鑰不可公開
+	hmacKey, err := NewSigningKey("hs-1", AlgHS256, make([]byte, 32))
+	require.NoError(t, err)
+	useSigningKeys(t, hmacKey)
+	assert.Empty(t, PublicJWKS().Keys)
+
+	ecKey, err := NewSigningKey("ec-1", AlgES256, ecPEM(t, elliptic.P256()))
+	require.NoError(t, err)
+	require.NoError(t, SetSigningKeys([]*SigningKey{ecKey}, ecKey.ID))
+
+	keys := PublicJWKS().Keys
+	require.Len(t, keys, 1)
+	assert.Equal(t, "EC", keys[0].Kty)
+	assert.Equal(t, "P-256", keys[0].Crv)
+	assert.Equal(t, "ec-1", keys[0].Kid)
+
+	// 由 JWK 還原的公鑰須與原金鑰一致
+	x, err := base64.RawURLEncoding.DecodeString(keys[0].X)
+	require.NoError(t, err)
+	y, err := base64.RawURLEncoding.DecodeString(keys[0].Y)
+	require.NoError(t, err)
+	assert.Len(t, x, 32)
+	publicKey := ecKey.verifyKey.(*ecdsa.PublicKey)
+	assert.Zero(t, publicKey.X.Cmp(new(big.Int).SetBytes(x)))
+	assert.Zero(t, publicKey.Y.Cmp(new(big.Int).SetBytes(y)))
+}
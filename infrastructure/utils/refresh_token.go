@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// RefreshTokenTTL 刷新令牌有效期
+const RefreshTokenTTL = 7 * 24 * time.Hour
+
+// GenerateRefreshToken 產生隨機的刷新令牌，返回明文與其雜湊值
+func GenerateRefreshToken() (token string, tokenHash string, err error) {
+	token, err = randomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashRefreshToken(token), nil
+}
+
+// GenerateSessionID 產生會話識別碼，同時作為該會話刷新令牌的 family ID
+func GenerateSessionID() (string, error) {
+	return randomString(16)
+}
+
+// HashRefreshToken 計算刷新令牌的 SHA-256 雜湊，資料庫只保存此值
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,314 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"rbac-service/domain"
+	"rbac-service/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAccessRequestRequest 申請角色的請求參數，duration 為 Go duration 格式，tenant_id 省略或為 0 時申請全域角色
+type CreateAccessRequestRequest struct {
+	RoleID   int64  `json:"role_id" binding:"required,gt=0" example:"1"`
+	TenantID int64  `json:"tenant_id" binding:"omitempty,gte=0" example:"0"`
+	Duration string `json:"duration" binding:"required" example:"4h"`
+	Reason   string `json:"reason" binding:"required,max=512" example:"週年慶活動期間處理玩家補償"`
+}
+
+// ReviewAccessRequestRequest 核准或駁回申請的請求參數
+type ReviewAccessRequestRequest struct {
+	Comment string `json:"comment" binding:"max=512" example:"活動期間使用"`
+}
+
+// AccessRequestHandler 處理即時權限申請相關的 HTTP 請求
+type AccessRequestHandler struct {
+	accessRequestService *usecase.AccessRequestService
+	auditService         *usecase.AuditService
+}
+
+// NewAccessRequestHandler 創建新的 AccessRequestHandler
+func NewAccessRequestHandler(accessRequestService *usecase.AccessRequestService, auditService *usecase.AuditService) *AccessRequestHandler {
+	return &AccessRequestHandler{
+		accessRequestService: accessRequestService,
+		auditService:         auditService,
+	}
+}
+
+// respondAccessRequestError 將權限申請服務的錯誤轉換為對應的 HTTP 狀態碼
+func respondAccessRequestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrAccessRequestNotFound),
+		errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrInvalidAccessRequestID),
+		errors.Is(err, domain.ErrInvalidAccessRequestDuration),
+		errors.Is(err, domain.ErrInvalidAccessRequestReason),
+		errors.Is(err, domain.ErrInvalidAccessRequestFilter),
+		errors.Is(err, domain.ErrInvalidRoleID),
+		errors.Is(err, domain.ErrInvalidTenantID),
+		errors.Is(err, domain.ErrNotTenantMember):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrNotAccessRequestApprover),
+		errors.Is(err, domain.ErrSelfReview),
+		errors.Is(err, domain.ErrNotAccessRequestRequester),
+		errors.Is(err, domain.ErrAccessRequestForbidden),
+		errors.Is(err, domain.ErrAccessRequestEscalation):
+		c.JSON(http.StatusForbidden, domain.NewErrorResponse("Permission Denied", err.Error()))
+	case errors.Is(err, domain.ErrAccessRequestNotPending),
+		errors.Is(err, domain.ErrAccessRequestExpired),
+		errors.Is(err, domain.ErrAccessRequestAlreadyPending),
+		errors.Is(err, domain.ErrAccessRequestRoleHeld),
+		errors.Is(err, domain.ErrRoleAlreadyAssigned):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse("Request Failed", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+	}
+}
+
+// Create 處理申請角色的請求
+// @Summary 申請角色
+// @Description 以理由申請在一段時長內擁有角色，申請人為目前登入的用戶。duration 為 Go duration 格式（例如 4h），上限 720h（30 天），自核准時起算；
+// @Description 帶入 tenant_id 時申請該租戶內的角色，申請人須為租戶成員。申請在 72 小時內未審核即轉為 expired，同一角色已有待審核的申請時不可重複申請
+// @Tags AccessRequests
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param request body CreateAccessRequestRequest true "角色、租戶、時長與理由"
+// @Success 201 {object} domain.Response{data=domain.AccessRequest} "申請已建立"
+// @Failure 400 {object} domain.Response "參數驗證失敗或用戶不是租戶成員"
+// @Failure 404 {object} domain.Response "角色未找到"
+// @Failure 409 {object} domain.Response "已有待審核的申請或已擁有該角色"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /access-requests [post]
+func (h *AccessRequestHandler) Create(c *gin.Context) {
+	var req CreateAccessRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	request, err := h.accessRequestService.CreateAccessRequest(c, c.GetString("username"), req.RoleID, req.TenantID, req.Duration, req.Reason)
+	entry := auditEntry{
+		Operation:  domain.AuditAccessRequestCreate,
+		EntityType: domain.AuditEntityAccessRequest,
+		Details: map[string]interface{}{
+			"role_id":   req.RoleID,
+			"tenant_id": req.TenantID,
+			"duration":  req.Duration,
+			"reason":    req.Reason,
+		},
+	}
+	if err == nil {
+		entry.EntityID = strconv.FormatInt(request.ID, 10)
+	}
+	recordAudit(c, h.auditService, entry, err)
+	if err != nil {
+		respondAccessRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewResponse("Access request created", request))
+}
+
+// List 處理列出權限申請的請求
+// @Summary 列出權限申請
+// @Description 依狀態、申請人與租戶列出權限申請，新的在前。擁有全域 access_request:approve 的審核者可列出所有申請，
+// @Description 帶入 tenant_id 時在該租戶內擁有即可；其他用戶只會列出自己的申請，指定其他申請人時返回 403
+// @Tags AccessRequests
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param status query string false "狀態：pending、approved、rejected、expired、cancelled"
+// @Param requester_id query int false "申請人ID"
+// @Param tenant_id query int false "租戶ID"
+// @Param limit query int false "筆數，預設 50，上限 200"
+// @Success 200 {object} domain.Response{data=[]domain.AccessRequest} "成功獲取申請列表"
+// @Failure 400 {object} domain.Response "無效的查詢條件"
+// @Failure 403 {object} domain.Response "不是審核者且指定其他申請人"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /access-requests [get]
+func (h *AccessRequestHandler) List(c *gin.Context) {
+	filter := domain.AccessRequestFilter{Status: c.Query("status")}
+	if requesterID := c.Query("requester_id"); requesterID != "" {
+		parsed, err := strconv.ParseInt(requesterID, 10, 64)
+		if err != nil {
+			respondAccessRequestError(c, domain.ErrInvalidAccessRequestFilter)
+			return
+		}
+		filter.RequesterID = parsed
+	}
+	if tenantID := c.Query("tenant_id"); tenantID != "" {
+		parsed, err := strconv.ParseInt(tenantID, 10, 64)
+		if err != nil {
+			respondAccessRequestError(c, domain.ErrInvalidAccessRequestFilter)
+			return
+		}
+		filter.TenantID = parsed
+	}
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			respondAccessRequestError(c, domain.ErrInvalidAccessRequestFilter)
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	requests, err := h.accessRequestService.ListAccessRequests(c, c.GetString("username"), filter)
+	if err != nil {
+		respondAccessRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", requests))
+}
+
+// Get 處理獲取單個權限申請的請求
+// @Summary 獲取權限申請
+// @Description 根據ID獲取權限申請詳情，只有申請人與在申請的租戶內擁有 access_request:approve 的審核者可以查看
+// @Tags AccessRequests
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "申請ID"
+// @Success 200 {object} domain.Response{data=domain.AccessRequest} "成功獲取申請"
+// @Failure 400 {object} domain.Response "無效的申請ID"
+// @Failure 403 {object} domain.Response "不是申請人或審核者"
+// @Failure 404 {object} domain.Response "申請未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /access-requests/{id} [get]
+func (h *AccessRequestHandler) Get(c *gin.Context) {
+	request, err := h.accessRequestService.GetAccessRequest(c, c.Param("id"), c.GetString("username"))
+	if err != nil {
+		respondAccessRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", request))
+}
+
+// Approve 處理核准權限申請的請求
+// @Summary 核准權限申請
+// @Description 核准待審核的申請，並為申請人建立自核准時起 duration 時長的臨時角色分配。
+// @Description 審核者須在申請的租戶內擁有 access_request:approve 權限，且不可審核自己的申請；角色（含繼承）允許的權限審核者須皆擁有，避免給予超出自身的權限。
+// @Description 逾期未審核的申請轉為 expired 並返回 409
+// @Tags AccessRequests
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "申請ID"
+// @Param request body ReviewAccessRequestRequest false "審核意見"
+// @Success 200 {object} domain.Response{data=domain.AccessRequest} "申請已核准"
+// @Failure 400 {object} domain.Response "參數驗證失敗或申請人已不是租戶成員"
+// @Failure 403 {object} domain.Response "不是審核者、審核自己的申請，或角色包含審核者沒有的權限"
+// @Failure 404 {object} domain.Response "申請或角色未找到"
+// @Failure 409 {object} domain.Response "申請已不在待審核狀態、已逾期，或申請人已擁有該角色"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /access-requests/{id}/approve [post]
+func (h *AccessRequestHandler) Approve(c *gin.Context) {
+	var req ReviewAccessRequestRequest
+	if !bindReview(c, &req) {
+		return
+	}
+
+	request, err := h.accessRequestService.ApproveAccessRequest(c, c.Param("id"), c.GetString("username"), req.Comment)
+	details := map[string]interface{}{"comment": req.Comment}
+	if err == nil {
+		details["requester_id"] = request.RequesterID
+		details["role_id"] = request.RoleID
+		details["tenant_id"] = request.TenantID
+		details["granted_until"] = request.GrantedUntil
+	}
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditAccessRequestApprove,
+		EntityType: domain.AuditEntityAccessRequest,
+		EntityID:   c.Param("id"),
+		Details:    details,
+	}, err)
+	if err != nil {
+		respondAccessRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Access request approved", request))
+}
+
+// Reject 處理駁回權限申請的請求
+// @Summary 駁回權限申請
+// @Description 駁回待審核的申請，審核者的條件與核准相同
+// @Tags AccessRequests
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "申請ID"
+// @Param request body ReviewAccessRequestRequest false "審核意見"
+// @Success 200 {object} domain.Response{data=domain.AccessRequest} "申請已駁回"
+// @Failure 400 {object} domain.Response "參數驗證失敗"
+// @Failure 403 {object} domain.Response "不是審核者或審核自己的申請"
+// @Failure 404 {object} domain.Response "申請未找到"
+// @Failure 409 {object} domain.Response "申請已不在待審核狀態或已逾期"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /access-requests/{id}/reject [post]
+func (h *AccessRequestHandler) Reject(c *gin.Context) {
+	var req ReviewAccessRequestRequest
+	if !bindReview(c, &req) {
+		return
+	}
+
+	request, err := h.accessRequestService.RejectAccessRequest(c, c.Param("id"), c.GetString("username"), req.Comment)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditAccessRequestReject,
+		EntityType: domain.AuditEntityAccessRequest,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"comment": req.Comment},
+	}, err)
+	if err != nil {
+		respondAccessRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Access request rejected", request))
+}
+
+// Cancel 處理撤回權限申請的請求
+// @Summary 撤回權限申請
+// @Description 申請人撤回自己待審核的申請
+// @Tags AccessRequests
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "申請ID"
+// @Success 200 {object} domain.Response{data=domain.AccessRequest} "申請已撤回"
+// @Failure 400 {object} domain.Response "無效的申請ID"
+// @Failure 403 {object} domain.Response "不是申請人"
+// @Failure 404 {object} domain.Response "申請未找到"
+// @Failure 409 {object} domain.Response "申請已不在待審核狀態或已逾期"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /access-requests/{id}/cancel [post]
+func (h *AccessRequestHandler) Cancel(c *gin.Context) {
+	request, err := h.accessRequestService.CancelAccessRequest(c, c.Param("id"), c.GetString("username"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditAccessRequestCancel,
+		EntityType: domain.AuditEntityAccessRequest,
+		EntityID:   c.Param("id"),
+	}, err)
+	if err != nil {
+		respondAccessRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Access request cancelled", request))
+}
+
+// bindReview 解析審核意見，請求本文可省略；解析失敗時回應 400 並返回 false
+func bindReview(c *gin.Context, req *ReviewAccessRequestRequest) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,346 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"rbac-service/domain"
+	"rbac-service/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AssignRoleRequest 為用戶分配角色的請求參數，tenant_id 省略或為 0 時為全域分配；
+// valid_from 與 valid_until 為 RFC 3339 時間，省略時分別表示立即生效與永久有效
+type AssignRoleRequest struct {
+	RoleID     int64      `json:"role_id" binding:"required,gt=0" example:"2"`
+	TenantID   int64      `json:"tenant_id" binding:"omitempty,gte=0" example:"1"`
+	ValidFrom  *time.Time `json:"valid_from" example:"2026-10-20T00:00:00+08:00"`
+	ValidUntil *time.Time `json:"valid_until" example:"2026-10-27T00:00:00+08:00"`
+}
+
+// AssignPermissionRequest 為角色或用戶分配權限的請求參數，effect 省略時為 allow
+type AssignPermissionRequest struct {
+	PermissionID int64  `json:"permission_id" binding:"required,gt=0" example:"5"`
+	Effect       string `json:"effect" binding:"omitempty,oneof=allow deny" example:"deny"`
+}
+
+// AssignUserPermissionRequest 直接為用戶分配權限的請求參數，tenant_id 省略或為 0 時為全域分配
+type AssignUserPermissionRequest struct {
+	AssignPermissionRequest
+	TenantID int64 `json:"tenant_id" binding:"omitempty,gte=0" example:"1"`
+}
+
+// AssignRolePermissionRequest 為角色分配權限的請求參數，condition 為分配生效的條件運算式，省略時無條件
+type AssignRolePermissionRequest struct {
+	AssignPermissionRequest
+	Condition string `json:"condition" binding:"max=1024" example:"subject.region == request.region"`
+}
+
+// AssignmentHandler 處理用戶角色與角色權限關聯的 HTTP 請求
+type AssignmentHandler struct {
+	assignmentService *usecase.AssignmentService
+	auditService      *usecase.AuditService
+}
+
+// NewAssignmentHandler 創建新的 AssignmentHandler
+func NewAssignmentHandler(assignmentService *usecase.AssignmentService, auditService *usecase.AuditService) *AssignmentHandler {
+	return &AssignmentHandler{
+		assignmentService: assignmentService,
+		auditService:      auditService,
+	}
+}
+
+// respondAssignmentError 將關聯服務的錯誤轉換為對應的 HTTP 狀態碼
+func respondAssignmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrRoleNotFound),
+		errors.Is(err, domain.ErrPermissionNotFound),
+		errors.Is(err, domain.ErrRoleNotAssigned),
+		errors.Is(err, domain.ErrPermissionNotAssigned),
+		errors.Is(err, domain.ErrUserPermissionNotAssigned):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrInvalidUserID),
+		errors.Is(err, domain.ErrInvalidRoleID),
+		errors.Is(err, domain.ErrInvalidPermissionID),
+		errors.Is(err, domain.ErrInvalidEffect),
+		errors.Is(err, domain.ErrInvalidCondition),
+		errors.Is(err, domain.ErrInvalidTenantID),
+		errors.Is(err, domain.ErrNotTenantMember),
+		errors.Is(err, domain.ErrInvalidValidityPeriod),
+		errors.Is(err, domain.ErrInvalidExpiringWindow):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrRoleAlreadyAssigned),
+		errors.Is(err, domain.ErrPermissionAlreadyAssigned),
+		errors.Is(err, domain.ErrUserPermissionAlreadyAssigned):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse("Request Failed", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+	}
+}
+
+// AssignUserRole 處理為用戶分配角色的請求
+// @Summary 為用戶分配角色
+// @Description 為指定用戶分配一個角色；帶入 tenant_id 時只在該租戶內生效，用戶須為租戶成員，省略時為全域分配。
+// @Description 帶入 valid_from 或 valid_until 時為臨時分配，只在有效期間內參與權限解析，過期後由背景工作清除並寫入審計日誌；
+// @Description valid_until 須晚於 valid_from 與目前時間
+// @Tags Assignments
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "用戶ID"
+// @Param request body AssignRoleRequest true "角色ID、租戶ID與有效期間"
+// @Success 201 {object} domain.Response "角色分配成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗、有效期間無效或用戶不是租戶成員"
+// @Failure 403 {object} domain.Response "沒有 user:assign 權限"
+// @Failure 404 {object} domain.Response "用戶或角色未找到"
+// @Failure 409 {object} domain.Response "用戶已擁有該角色"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users/{id}/roles [post]
+func (h *AssignmentHandler) AssignUserRole(c *gin.Context) {
+	var req AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	err := h.assignmentService.AssignRoleToUser(c, c.Param("id"), strconv.FormatInt(req.RoleID, 10), strconv.FormatInt(req.TenantID, 10), req.ValidFrom, req.ValidUntil)
+	details := map[string]interface{}{"role_id": req.RoleID, "tenant_id": req.TenantID}
+	if req.ValidFrom != nil {
+		details["valid_from"] = req.ValidFrom
+	}
+	if req.ValidUntil != nil {
+		details["valid_until"] = req.ValidUntil
+	}
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditUserRoleAssign,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   c.Param("id"),
+		Details:    details,
+	}, err)
+	if err != nil {
+		respondAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewResponse("Role assigned", nil))
+}
+
+// RemoveUserRole 處理移除用戶角色的請求
+// @Summary 移除用戶的角色
+// @Description 移除指定用戶的一個角色，帶入 tenant_id 時移除該租戶內的分配，省略時移除全域分配
+// @Tags Assignments
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "用戶ID"
+// @Param roleId path string true "角色ID"
+// @Param tenant_id query int false "租戶ID，省略或為 0 表示全域"
+// @Success 200 {object} domain.Response "角色移除成功"
+// @Failure 400 {object} domain.Response "無效的ID"
+// @Failure 403 {object} domain.Response "沒有 user:assign 權限"
+// @Failure 404 {object} domain.Response "用戶或角色未找到，或用戶未擁有該角色"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users/{id}/roles/{roleId} [delete]
+func (h *AssignmentHandler) RemoveUserRole(c *gin.Context) {
+	err := h.assignmentService.RemoveRoleFromUser(c, c.Param("id"), c.Param("roleId"), c.Query("tenant_id"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditUserRoleRemove,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"role_id": c.Param("roleId"), "tenant_id": c.Query("tenant_id")},
+	}, err)
+	if err != nil {
+		respondAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Role removed", nil))
+}
+
+// ListExpiringRoleAssignments 處理列出即將過期角色分配的請求
+// @Summary 列出即將過期的角色分配
+// @Description 列出在 within 時間內過期的臨時角色分配，依過期時間排序。within 為 Go duration 格式（例如 72h），省略時為 168h（7 天），上限 2160h（90 天）
+// @Tags Assignments
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param within query string false "時間範圍，例如 72h"
+// @Success 200 {object} domain.Response{data=[]domain.RoleAssignment} "成功獲取即將過期的分配"
+// @Failure 400 {object} domain.Response "無效的時間範圍"
+// @Failure 403 {object} domain.Response "沒有 user:assign 權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /role-assignments/expiring [get]
+func (h *AssignmentHandler) ListExpiringRoleAssignments(c *gin.Context) {
+	assignments, err := h.assignmentService.ListExpiringRoleAssignments(c, c.Query("within"))
+	if err != nil {
+		respondAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", assignments))
+}
+
+// ListUserPermissions 處理獲取用戶所有權限的請求
+// @Summary 獲取用戶所有權限
+// @Description 獲取用戶經由所有角色（含繼承）與直接分配取得的權限，effect 為 allow 或 deny，同一權限同時被允許與拒絕時以 deny 為準。
+// @Description 帶入 tenant_id 時計入全域與該租戶內的角色與直接分配，省略時只計入全域分配。查詢其他用戶需要 user:assign 權限
+// @Tags Assignments
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "用戶ID"
+// @Param tenant_id query int false "租戶ID，省略或為 0 表示全域"
+// @Success 200 {object} domain.Response{data=[]domain.Permission} "成功獲取權限列表"
+// @Failure 400 {object} domain.Response "無效的用戶ID"
+// @Failure 403 {object} domain.Response "查詢其他用戶但沒有 user:assign 權限"
+// @Failure 404 {object} domain.Response "用戶未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users/{id}/permissions [get]
+func (h *AssignmentHandler) ListUserPermissions(c *gin.Context) {
+	permissions, err := h.assignmentService.GetUserPermissions(c, c.Param("id"), c.Query("tenant_id"))
+	if err != nil {
+		respondAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", permissions))
+}
+
+// AssignRolePermission 處理為角色分配權限的請求
+// @Summary 為角色分配權限
+// @Description 為指定角色分配一個權限，effect 為 deny 時明確拒絕該權限，優先於其他角色的 allow。
+// @Description condition 為條件運算式，可引用 subject.*（用戶屬性）、request.*（授權請求的屬性）與 env.*（hour、minute、weekday、time、date），儲存前驗證語法
+// @Tags Assignments
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "角色ID"
+// @Param request body AssignRolePermissionRequest true "權限ID、效果與條件"
+// @Success 201 {object} domain.Response "權限分配成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗或條件運算式無效"
+// @Failure 403 {object} domain.Response "沒有 role:manage 權限"
+// @Failure 404 {object} domain.Response "角色或權限未找到"
+// @Failure 409 {object} domain.Response "角色已擁有該權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /roles/{id}/permissions [post]
+func (h *AssignmentHandler) AssignRolePermission(c *gin.Context) {
+	var req AssignRolePermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	err := h.assignmentService.AssignPermissionToRole(c, c.Param("id"), strconv.FormatInt(req.PermissionID, 10), req.Effect, req.Condition)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditRolePermissionAssign,
+		EntityType: domain.AuditEntityRole,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"permission_id": req.PermissionID, "effect": req.Effect, "condition": req.Condition},
+	}, err)
+	if err != nil {
+		respondAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewResponse("Permission assigned", nil))
+}
+
+// RemoveRolePermission 處理移除角色權限的請求
+// @Summary 移除角色的權限
+// @Description 移除指定角色的一個權限
+// @Tags Assignments
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "角色ID"
+// @Param permId path string true "權限ID"
+// @Success 200 {object} domain.Response "權限移除成功"
+// @Failure 400 {object} domain.Response "無效的ID"
+// @Failure 403 {object} domain.Response "沒有 role:manage 權限"
+// @Failure 404 {object} domain.Response "角色或權限未找到，或角色未擁有該權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /roles/{id}/permissions/{permId} [delete]
+func (h *AssignmentHandler) RemoveRolePermission(c *gin.Context) {
+	err := h.assignmentService.RemovePermissionFromRole(c, c.Param("id"), c.Param("permId"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditRolePermissionRemove,
+		EntityType: domain.AuditEntityRole,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"permission_id": c.Param("permId")},
+	}, err)
+	if err != nil {
+		respondAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Permission removed", nil))
+}
+
+// AssignUserPermission 處理直接為用戶分配權限的請求
+// @Summary 為用戶分配權限
+// @Description 不經由角色直接為指定用戶分配一個權限，effect 為 deny 時明確拒絕該權限，優先於用戶任何角色的 allow。
+// @Description 帶入 tenant_id 時只在該租戶內生效，用戶須為租戶成員，省略時為全域分配
+// @Tags Assignments
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "用戶ID"
+// @Param request body AssignUserPermissionRequest true "權限ID、效果與租戶ID"
+// @Success 201 {object} domain.Response "權限分配成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗或用戶不是租戶成員"
+// @Failure 403 {object} domain.Response "沒有 user:assign 權限"
+// @Failure 404 {object} domain.Response "用戶或權限未找到"
+// @Failure 409 {object} domain.Response "用戶已被分配該權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users/{id}/permissions [post]
+func (h *AssignmentHandler) AssignUserPermission(c *gin.Context) {
+	var req AssignUserPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	err := h.assignmentService.AssignPermissionToUser(c, c.Param("id"), strconv.FormatInt(req.PermissionID, 10), strconv.FormatInt(req.TenantID, 10), req.Effect)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditUserPermissionAssign,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"permission_id": req.PermissionID, "effect": req.Effect, "tenant_id": req.TenantID},
+	}, err)
+	if err != nil {
+		respondAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewResponse("Permission assigned", nil))
+}
+
+// RemoveUserPermission 處理移除用戶直接分配權限的請求
+// @Summary 移除用戶的權限
+// @Description 移除直接分配給指定用戶的一個權限，不影響經由角色取得的權限；帶入 tenant_id 時移除該租戶內的分配，省略時移除全域分配
+// @Tags Assignments
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "用戶ID"
+// @Param permId path string true "權限ID"
+// @Param tenant_id query int false "租戶ID，省略或為 0 表示全域"
+// @Success 200 {object} domain.Response "權限移除成功"
+// @Failure 400 {object} domain.Response "無效的ID"
+// @Failure 403 {object} domain.Response "沒有 user:assign 權限"
+// @Failure 404 {object} domain.Response "用戶或權限未找到，或用戶未被直接分配該權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users/{id}/permissions/{permId} [delete]
+func (h *AssignmentHandler) RemoveUserPermission(c *gin.Context) {
+	err := h.assignmentService.RemovePermissionFromUser(c, c.Param("id"), c.Param("permId"), c.Query("tenant_id"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditUserPermissionRemove,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"permission_id": c.Param("permId"), "tenant_id": c.Query("tenant_id")},
+	}, err)
+	if err != nil {
+		respondAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Permission removed", nil))
+}
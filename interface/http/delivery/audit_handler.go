@@ -0,0 +1,139 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"rbac-service/domain"
+	"rbac-service/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditEntry 一次審計記錄的內容
+type auditEntry struct {
+	Operation  string
+	EntityType string
+	EntityID   string
+	// Operator 為空時使用 JWT 中介層寫入的 username
+	Operator string
+	Details  map[string]interface{}
+}
+
+// recordAudit 依操作結果寫入審計日誌，err 不為空時記為失敗並附上錯誤訊息
+func recordAudit(c *gin.Context, auditService *usecase.AuditService, entry auditEntry, err error) {
+	result := domain.AuditResultSuccess
+	if err != nil {
+		result = domain.AuditResultFailure
+		if entry.Details == nil {
+			entry.Details = map[string]interface{}{}
+		}
+		entry.Details["error"] = err.Error()
+	}
+
+	operator := entry.Operator
+	if operator == "" {
+		operator = c.GetString("username")
+	}
+
+	auditService.Record(c, &domain.AuditLog{
+		Operation:       entry.Operation,
+		EntityType:      entry.EntityType,
+		EntityID:        entry.EntityID,
+		Operator:        operator,
+		OperationResult: result,
+	}, entry.Details)
+}
+
+// AuditHandler 處理審計日誌相關的 HTTP 請求
+type AuditHandler struct {
+	auditService *usecase.AuditService
+}
+
+// NewAuditHandler 創建新的 AuditHandler
+func NewAuditHandler(auditService *usecase.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// parseAuditFilter 解析審計日誌查詢參數
+func parseAuditFilter(c *gin.Context) (domain.AuditLogFilter, error) {
+	filter := domain.AuditLogFilter{
+		Operator:   c.Query("operator"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		Operation:  c.Query("operation"),
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, domain.ErrInvalidAuditFilter
+		}
+		filter.From = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, domain.ErrInvalidAuditFilter
+		}
+		filter.To = &t
+	}
+	if cursor := c.Query("cursor"); cursor != "" {
+		parsed, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return filter, domain.ErrInvalidAuditFilter
+		}
+		filter.Cursor = parsed
+	}
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			return filter, domain.ErrInvalidAuditFilter
+		}
+		filter.Limit = parsed
+	}
+
+	return filter, nil
+}
+
+// List 處理查詢審計日誌的請求
+// @Summary 查詢審計日誌
+// @Description 依操作者、實體、操作類型與時間範圍查詢審計日誌，由新到舊以游標分頁
+// @Tags AuditLogs
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param operator query string false "操作者"
+// @Param entity_type query string false "實體類型，例如 user、role、permission"
+// @Param entity_id query string false "實體ID"
+// @Param operation query string false "操作類型，例如 role.create"
+// @Param from query string false "起始時間（含），RFC3339"
+// @Param to query string false "結束時間（不含），RFC3339"
+// @Param cursor query string false "上一頁返回的 next_cursor"
+// @Param limit query int false "每頁筆數，預設 50，上限 200"
+// @Success 200 {object} domain.Response{data=domain.AuditLogPage} "成功獲取審計日誌"
+// @Failure 400 {object} domain.Response "無效的查詢條件"
+// @Failure 403 {object} domain.Response "沒有 audit:view 權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /audit-logs [get]
+func (h *AuditHandler) List(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+		return
+	}
+
+	page, err := h.auditService.ListAuditLogs(c, filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAuditFilter) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", page))
+}
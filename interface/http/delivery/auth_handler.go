@@ -1,9 +1,12 @@
 package delivery
 
 import (
+	"errors"
 	"net/http"
 	"rbac-service/domain"
+	"rbac-service/infrastructure/utils"
 	"rbac-service/usecase"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -11,36 +14,67 @@ import (
 
 // AuthHandler 處理用戶相關的 HTTP 請求
 type AuthHandler struct {
-	authService *usecase.AuthService
+	authService  *usecase.AuthService
+	auditService *usecase.AuditService
 }
 
 // NewAuthHandler 創建新的 AuthHandler
-func NewAuthHandler(authService *usecase.AuthService) *AuthHandler {
+func NewAuthHandler(authService *usecase.AuthService, auditService *usecase.AuditService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:  authService,
+		auditService: auditService,
 	}
 }
 
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	// Tenant 租戶代碼，帶入時令牌綁定該租戶並帶有全域與租戶內的角色，用戶須為其成員
+	Tenant string `json:"tenant" binding:"omitempty,max=64" example:"game-a"`
+}
+
+// RefreshRequest 刷新令牌請求參數
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RevokeRequest 撤銷令牌請求參數，未帶 token 時撤銷目前使用的令牌
+type RevokeRequest struct {
+	Token string `json:"token"`
+}
+
+// BatchRevokeRequest 批量撤銷請求參數，可指定令牌或撤銷用戶的所有令牌
+type BatchRevokeRequest struct {
+	Tokens  []string `json:"tokens"`
+	UserIDs []int64  `json:"user_ids" example:"2"`
 }
 
 // AuthorizeRequest 授權請求參數
 type AuthorizeRequest struct {
 	Resource string `json:"resource" binding:"required"` // 要訪問的資源
 	Action   string `json:"action" binding:"required"`   // 要執行的操作 (例如: read, write, delete)
+	// Attributes 請求屬性，供權限條件以 request.<鍵> 引用
+	Attributes map[string]string `json:"attributes" binding:"omitempty,max=32"`
+	// ResourceID 資源實例ID，帶入時一併依實例授權與擁有者規則判定
+	ResourceID string `json:"resource_id" binding:"omitempty,max=64" example:"42"`
+}
+
+// BatchAuthorizeRequest 批量授權請求參數，單次最多 100 項；include_permissions 為 true 時可不帶 checks
+type BatchAuthorizeRequest struct {
+	Checks             []AuthorizeRequest `json:"checks" binding:"max=100,dive"`
+	IncludePermissions bool               `json:"include_permissions"`
 }
 
 // Login 處理用戶登錄請求
 // @Summary 用戶登錄
-// @Description 處理用戶登錄並返回訪問令牌
+// @Description 處理用戶登錄並建立新的會話，返回訪問令牌與刷新令牌；帶入 tenant 時令牌綁定該租戶，用戶須為其成員
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "登錄請求參數"
 // @Success 200 {object} map[string]interface{} "登錄成功"
 // @Failure 400 {object} map[string]interface{} "無效的輸入或登錄失敗"
+// @Failure 403 {object} map[string]interface{} "租戶不存在或用戶不是租戶成員"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
@@ -49,23 +83,38 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authService.Login(req.Username, req.Password)
+	client := domain.SessionClient{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+	loginResponse, err := h.authService.Login(c, req.Username, req.Password, req.Tenant, client)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditAuthLogin,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   req.Username,
+		Operator:   req.Username,
+	}, err)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("login failed", err.Error()))
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrTenantNotFound) || errors.Is(err, domain.ErrNotTenantMember) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, domain.NewErrorResponse("login failed", err.Error()))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "login successful",
-		"token":   token,
+		"message":       "login successful",
+		"token":         loginResponse.Token,
+		"refresh_token": loginResponse.RefreshToken,
+		"expires_in":    loginResponse.ExpiresIn,
 	})
 }
 
 // Logout 處理用戶登出請求
 // @Summary 用戶登出
-// @Description 處理用戶登出
+// @Description 處理用戶登出，終止目前的會話並撤銷其刷新令牌
 // @Tags Auth
-// @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer Token"
 // @Success 200 {object} map[string]interface{} "登出成功"
@@ -76,6 +125,11 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	token = strings.TrimPrefix(token, "Bearer ")
 
 	err := h.authService.Logout(c, token)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditAuthLogout,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   c.GetString("username"),
+	}, err)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "logout failed"})
 		return
@@ -84,19 +138,35 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "logout successful"})
 }
 
+// JWKS 返回驗證令牌用的公鑰（RFC 7517），使用 HS256 共享密鑰時為空集合
+// @Summary 取得驗證令牌用的公鑰
+// @Description 以 JWK Set（RFC 7517）公開仍可用於驗證的公鑰，供其他服務離線驗證令牌；已退役的金鑰與 HS256 共享密鑰不會出現，只有共享密鑰時 keys 為空陣列。
+// @Description 此端點不需認證，位於根路徑而非 /v1 之下，回應可快取 300 秒
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} utils.JSONWebKeySet "公鑰集合"
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, utils.PublicJWKS())
+}
+
 // Authorize 處理權限驗證的請求
 // @Summary 驗證權限
-// @Description 驗證用戶是否有權限訪問特定資源
+// @Description 驗證用戶是否有權限訪問特定資源，採 deny-overrides：任一 deny 分配符合即拒絕；回應的 rule 標示決定結果的權限規則。
+// @Description 帶條件的分配以 attributes（request.*）、用戶屬性（subject.*）與當下時間（env.*）求值，因條件不成立而拒絕時 reason 說明原因
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer Token"
+// @Param X-Tenant header string false "租戶代碼，令牌未綁定租戶時指定角色解析的租戶"
 // @Param request body AuthorizeRequest true "授權請求參數"
 // @Security BearerAuth
 // @Success 200 {object} map[string]interface{} "驗證成功"
 // @Failure 400 {object} map[string]interface{} "無效的請求參數"
 // @Failure 401 {object} map[string]interface{} "未授權訪問"
 // @Failure 403 {object} map[string]interface{} "權限不足"
+// @Failure 500 {object} map[string]interface{} "服務器內部錯誤"
 // @Router /auth/authorize [post]
 func (h *AuthHandler) Authorize(c *gin.Context) {
 	var req AuthorizeRequest
@@ -105,7 +175,7 @@ func (h *AuthHandler) Authorize(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
+	username, exists := c.Get("username")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", "Please login first"))
 		return
@@ -118,66 +188,281 @@ func (h *AuthHandler) Authorize(c *gin.Context) {
 		return
 	}
 
-	hasPermission, err := h.authService.CheckPermission(c, userID.(string), token.(string), req.Resource, req.Action)
+	check := domain.PermissionCheck{Resource: req.Resource, Action: req.Action, ResourceID: req.ResourceID, Attributes: req.Attributes}
+	decision, err := h.authService.Authorize(c, username.(string), token.(string), c.GetString("tenant"), check)
+	h.recordDecision(c, username.(string), req, decision, err)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Permission Check Failed", err.Error()))
+		if errors.Is(err, domain.ErrInvalidAuthorizeRequest) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Permission Check Failed", domain.ErrInternalServerError.Error()))
 		return
 	}
 
-	if !hasPermission {
-		c.JSON(http.StatusForbidden, domain.NewErrorResponse("Permission Denied", "No access to this resource"))
+	if !decision.Allowed {
+		// 被 deny 規則拒絕時標示該規則，沒有任何規則符合時 rule 省略，因條件不成立時附上原因
+		response := domain.NewErrorResponse("Permission Denied", "No access to this resource")
+		response.Data = domain.AuthorizeResponse{Authorized: false, Rule: decision.Rule, Reason: decision.Reason}
+		c.JSON(http.StatusForbidden, response)
 		return
 	}
+	expiresIn, needsRefresh := h.authService.TokenStatus(token.(string))
 	authResponse := domain.AuthorizeResponse{
-		Authorized: true,
-		// TODO: Get expiration time from token
-		ExpiresIn: 3600, // Example: 1 hour
+		Authorized:   true,
+		Rule:         decision.Rule,
+		NeedsRefresh: needsRefresh,
+		ExpiresIn:    expiresIn,
 	}
 
 	c.JSON(http.StatusOK, domain.NewResponse("Authorization successful", authResponse))
 }
 
-// Refresh 處理刷新令牌的請求
-// @Summary 刷新訪問令牌
-// @Description 使用刷新令牌獲取新的訪問令牌
+// BatchAuthorize 處理批量權限驗證的請求
+// @Summary 批量驗證權限
+// @Description 以單次權限解析判定多個資源與操作，各項目的結果依請求順序列於 decisions；include_permissions 為 true 時一併返回用戶完整的權限列表供客戶端快取
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer Token"
+// @Param X-Tenant header string false "租戶代碼，令牌未綁定租戶時指定角色解析的租戶"
+// @Param request body BatchAuthorizeRequest true "批量授權請求參數"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "令牌刷新成功"
-// @Failure 401 {object} map[string]interface{} "無效的刷新令牌"
+// @Success 200 {object} domain.Response{data=domain.BatchAuthorizeResult} "判定完成"
+// @Failure 400 {object} domain.Response "無效的請求參數"
+// @Failure 401 {object} domain.Response "未授權訪問"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /auth/authorize/batch [post]
+func (h *AuthHandler) BatchAuthorize(c *gin.Context) {
+	var req BatchAuthorizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	username := c.GetString("username")
+	token := c.GetString("token")
+	if username == "" || token == "" {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", "Please login first"))
+		return
+	}
+
+	checks := make([]domain.PermissionCheck, 0, len(req.Checks))
+	for _, check := range req.Checks {
+		checks = append(checks, domain.PermissionCheck{Resource: check.Resource, Action: check.Action, ResourceID: check.ResourceID, Attributes: check.Attributes})
+	}
+
+	result, err := h.authService.BatchCheckPermissions(c, username, token, c.GetString("tenant"), checks, req.IncludePermissions)
+	entry := auditEntry{
+		Operation:  domain.AuditAuthBatchAuthorize,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   username,
+	}
+	if result != nil {
+		allowed, denied := []string{}, []string{}
+		for _, decision := range result.Decisions {
+			key := decision.Resource + ":" + decision.Action
+			if decision.Allowed {
+				allowed = append(allowed, key)
+			} else {
+				denied = append(denied, key)
+			}
+		}
+		entry.Details = map[string]interface{}{"allowed": allowed, "denied": denied}
+	}
+	recordAudit(c, h.auditService, entry, err)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAuthorizeRequest) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Permission Check Failed", domain.ErrInternalServerError.Error()))
+		return
+	}
+
+	result.ExpiresIn, result.NeedsRefresh = h.authService.TokenStatus(token)
+	c.JSON(http.StatusOK, domain.NewResponse("Authorization completed", result))
+}
+
+// recordDecision 將授權判定結果寫入審計日誌
+func (h *AuthHandler) recordDecision(c *gin.Context, username string, req AuthorizeRequest, decision *domain.AuthorizeDecision, err error) {
+	details := map[string]interface{}{
+		"resource": req.Resource,
+		"action":   req.Action,
+	}
+	result := domain.AuditResultDeny
+	switch {
+	case err != nil:
+		result = domain.AuditResultFailure
+		details["error"] = err.Error()
+	case decision.Allowed:
+		result = domain.AuditResultAllow
+	}
+	if req.ResourceID != "" {
+		details["resource_id"] = req.ResourceID
+	}
+	if tenant := c.GetString("tenant"); tenant != "" {
+		details["tenant"] = tenant
+	}
+	if len(req.Attributes) > 0 {
+		details["attributes"] = req.Attributes
+	}
+	if decision != nil && decision.Rule != nil {
+		details["rule"] = decision.Rule
+	}
+	if decision != nil && decision.Reason != "" {
+		details["reason"] = decision.Reason
+	}
+
+	h.auditService.Record(c, &domain.AuditLog{
+		Operation:       domain.AuditAuthAuthorize,
+		EntityType:      domain.AuditEntityUser,
+		EntityID:        username,
+		Operator:        username,
+		OperationResult: result,
+	}, details)
+}
+
+// Refresh 處理刷新令牌的請求
+// @Summary 刷新訪問令牌
+// @Description 使用刷新令牌獲取新的訪問令牌，刷新令牌每次使用後輪替；重複使用已輪替的刷新令牌會撤銷該次登入的所有刷新令牌
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "刷新令牌"
+// @Success 200 {object} domain.Response{data=domain.LoginResponse} "令牌刷新成功"
+// @Failure 400 {object} domain.Response "無效的請求參數"
+// @Failure 401 {object} domain.Response "無效、過期或重複使用的刷新令牌"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
 // @Router /auth/refresh [post]
 func (h *AuthHandler) Refresh(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	loginResponse, err := h.authService.Refresh(c, req.RefreshToken)
+	entry := auditEntry{
+		Operation:  domain.AuditAuthRefresh,
+		EntityType: domain.AuditEntityUser,
+	}
+	if loginResponse != nil {
+		entry.EntityID = loginResponse.User
+		entry.Operator = loginResponse.User
+	}
+	recordAudit(c, h.auditService, entry, err)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidRefreshToken),
+			errors.Is(err, domain.ErrRefreshTokenExpired),
+			errors.Is(err, domain.ErrRefreshTokenReused),
+			errors.Is(err, domain.ErrUserDisabled):
+			c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Token refreshed", loginResponse))
 }
 
 // Revoke 處理取消授權jwt的請求
 // @Summary 撤銷訪問令牌
-// @Description 撤銷指定的訪問令牌
+// @Description 撤銷指定的訪問令牌，未指定時撤銷目前使用的令牌；撤銷後至令牌過期前都會被拒絕。撤銷其他用戶的令牌需要 token:revoke 權限
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer Token"
+// @Param request body RevokeRequest false "要撤銷的令牌"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "令牌撤銷成功"
-// @Failure 401 {object} map[string]interface{} "無效的令牌"
+// @Success 200 {object} domain.Response "令牌撤銷成功"
+// @Failure 400 {object} domain.Response "無效的令牌"
+// @Failure 403 {object} domain.Response "沒有撤銷其他用戶令牌的權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
 // @Router /auth/revoke [post]
 func (h *AuthHandler) Revoke(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	var req RevokeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+			return
+		}
+	}
+	token := c.GetString("token")
+	if req.Token == "" {
+		req.Token = token
+	}
+
+	revoked, err := h.authService.RevokeToken(c, c.GetString("username"), token, req.Token)
+	entry := auditEntry{
+		Operation:  domain.AuditAuthRevoke,
+		EntityType: domain.AuditEntityUser,
+	}
+	if revoked != nil {
+		entry.EntityID = revoked.Username
+		entry.Details = map[string]interface{}{"jti": revoked.JTI}
+	}
+	recordAudit(c, h.auditService, entry, err)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidJwt) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrRevokeForbidden) {
+			c.JSON(http.StatusForbidden, domain.NewErrorResponse("Permission Denied", err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Token revoked", nil))
 }
 
 // BatchRevoke 處理批量取消授權jwt的請求
 // @Summary 批量撤銷訪問令牌
-// @Description 批量撤銷多個訪問令牌
+// @Description 批量撤銷多個訪問令牌，或撤銷指定用戶目前所有的訪問令牌與刷新令牌；單次最多 100 項。沒有 token:revoke 權限時只能撤銷自己的令牌，其他項目列於 failed
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer Token"
+// @Param request body BatchRevokeRequest true "要撤銷的令牌與用戶"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "批量撤銷成功"
-// @Failure 401 {object} map[string]interface{} "無效的令牌"
-// @Router /auth/batchRevoke [post]
+// @Success 200 {object} domain.Response{data=domain.BatchRevokeResult} "批量撤銷完成，失敗項目列於 failed"
+// @Failure 400 {object} domain.Response "無效的請求參數"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /auth/batch-revoke [post]
 func (h *AuthHandler) BatchRevoke(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	var req BatchRevokeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	userIDs := make([]string, 0, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		userIDs = append(userIDs, strconv.FormatInt(id, 10))
+	}
+
+	result, err := h.authService.BatchRevoke(c, c.GetString("username"), c.GetString("token"), req.Tokens, userIDs)
+	entry := auditEntry{
+		Operation:  domain.AuditAuthBatchRevoke,
+		EntityType: domain.AuditEntityUser,
+	}
+	if result != nil {
+		entry.Details = map[string]interface{}{"revoked": result.Revoked, "failed": result.Failed}
+	}
+	recordAudit(c, h.auditService, entry, err)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRevokeRequest) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Batch revoke completed", result))
 }
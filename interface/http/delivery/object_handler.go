@@ -0,0 +1,388 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"rbac-service/domain"
+	"rbac-service/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ObjectGrantRequest 新增實例授權的請求參數，action 為 "*" 時允許該實例的所有操作；
+// tenant_id 省略或為 0 時授權在所有租戶中生效
+type ObjectGrantRequest struct {
+	TenantID int64  `json:"tenant_id" binding:"omitempty,gte=0" example:"1"`
+	Resource string `json:"resource" binding:"required,max=64" example:"notice"`
+	ObjectID string `json:"object_id" binding:"required,max=64" example:"42"`
+	Action   string `json:"action" binding:"required,max=64" example:"edit"`
+}
+
+// ResourceOwnerRequest 登記資源實例擁有者的請求參數，tenant_id 省略或為 0 時為全域登記
+type ResourceOwnerRequest struct {
+	TenantID int64  `json:"tenant_id" binding:"omitempty,gte=0" example:"1"`
+	Resource string `json:"resource" binding:"required,max=64" example:"notice"`
+	ObjectID string `json:"object_id" binding:"required,max=64" example:"42"`
+	OwnerID  int64  `json:"owner_id" binding:"required,gt=0" example:"3"`
+}
+
+// OwnerRuleRequest 新增擁有者規則的請求參數
+type OwnerRuleRequest struct {
+	Resource string `json:"resource" binding:"required,max=64" example:"notice"`
+	Action   string `json:"action" binding:"required,max=64" example:"edit"`
+}
+
+// ObjectHandler 處理資源實例授權、擁有者與擁有者規則的 HTTP 請求
+type ObjectHandler struct {
+	objectService *usecase.ObjectService
+	auditService  *usecase.AuditService
+}
+
+// NewObjectHandler 創建新的 ObjectHandler
+func NewObjectHandler(objectService *usecase.ObjectService, auditService *usecase.AuditService) *ObjectHandler {
+	return &ObjectHandler{
+		objectService: objectService,
+		auditService:  auditService,
+	}
+}
+
+// respondObjectError 將資源實例授權服務的錯誤轉換為對應的 HTTP 狀態碼
+func respondObjectError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrObjectGrantNotFound),
+		errors.Is(err, domain.ErrResourceOwnerNotFound),
+		errors.Is(err, domain.ErrOwnerRuleNotFound):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrInvalidUserID),
+		errors.Is(err, domain.ErrInvalidObjectID),
+		errors.Is(err, domain.ErrInvalidObjectResource),
+		errors.Is(err, domain.ErrInvalidTenantID),
+		errors.Is(err, domain.ErrNotTenantMember):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrObjectGrantAlreadyExists),
+		errors.Is(err, domain.ErrOwnerRuleAlreadyExists):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse("Request Failed", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+	}
+}
+
+// objectEntityID 審計日誌中資源實例的識別，格式為 "resource:object_id"
+func objectEntityID(resource, objectID string) string {
+	return resource + ":" + objectID
+}
+
+// ListAccessibleObjects 處理列出用戶可操作的資源實例的請求
+// @Summary 列出用戶可操作的資源實例
+// @Description 列出用戶可對指定資源類型執行操作的實例 ID。權限分配允許該類資源時 all 為 true，表示所有實例；明確拒絕時為空列表；否則為實例授權與擁有者規則允許的實例。
+// @Description 權限分配、實例授權與擁有者登記皆計入全域與 tenant_id 指定租戶內的部分
+// @Tags Objects
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "用戶ID"
+// @Param resource query string true "資源類型"
+// @Param action query string true "操作"
+// @Param tenant_id query int false "租戶ID，計入該租戶內的分配；省略或為 0 時只計入全域分配"
+// @Success 200 {object} domain.Response{data=domain.AccessibleObjects} "成功獲取實例列表"
+// @Failure 400 {object} domain.Response "無效的用戶ID、租戶ID、資源或操作"
+// @Failure 403 {object} domain.Response "沒有 object:manage 權限"
+// @Failure 404 {object} domain.Response "用戶未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users/{id}/objects [get]
+func (h *ObjectHandler) ListAccessibleObjects(c *gin.Context) {
+	objects, err := h.objectService.ListAccessibleObjects(c, c.Param("id"), c.Query("tenant_id"), c.Query("resource"), c.Query("action"))
+	if err != nil {
+		respondObjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", objects))
+}
+
+// ListGrants 處理列出用戶實例授權的請求
+// @Summary 列出用戶的實例授權
+// @Description 列出用戶在所有租戶對單一資源實例的授權，tenant_id 為 0 的授權在所有租戶中生效
+// @Tags Objects
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "用戶ID"
+// @Success 200 {object} domain.Response{data=[]domain.ObjectGrant} "成功獲取實例授權"
+// @Failure 400 {object} domain.Response "無效的用戶ID"
+// @Failure 403 {object} domain.Response "沒有 object:manage 權限"
+// @Failure 404 {object} domain.Response "用戶未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users/{id}/object-grants [get]
+func (h *ObjectHandler) ListGrants(c *gin.Context) {
+	grants, err := h.objectService.ListUserObjectGrants(c, c.Param("id"))
+	if err != nil {
+		respondObjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", grants))
+}
+
+// CreateGrant 處理新增實例授權的請求
+// @Summary 新增實例授權
+// @Description 允許用戶對單一資源實例執行操作，例如編輯 notice 42；資源類型不可含萬用字元，action 可為 "*"。權限分配明確拒絕該類資源時，實例授權不會生效。
+// @Description 帶入 tenant_id 時只在該租戶內生效，用戶須為租戶成員，省略時在所有租戶中生效
+// @Tags Objects
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "用戶ID"
+// @Param request body ObjectGrantRequest true "租戶、資源、實例與操作"
+// @Success 201 {object} domain.Response{data=domain.ObjectGrant} "實例授權新增成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗或用戶不是租戶成員"
+// @Failure 403 {object} domain.Response "沒有 object:manage 權限"
+// @Failure 404 {object} domain.Response "用戶未找到"
+// @Failure 409 {object} domain.Response "實例授權已存在"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users/{id}/object-grants [post]
+func (h *ObjectHandler) CreateGrant(c *gin.Context) {
+	var req ObjectGrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	grant, err := h.objectService.CreateObjectGrant(c, c.Param("id"), &domain.ObjectGrant{
+		TenantID: req.TenantID,
+		Resource: req.Resource,
+		ObjectID: req.ObjectID,
+		Action:   req.Action,
+	})
+	entry := auditEntry{
+		Operation:  domain.AuditObjectGrantCreate,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"tenant_id": req.TenantID, "resource": req.Resource, "object_id": req.ObjectID, "action": req.Action},
+	}
+	if grant != nil {
+		entry.Details["grant_id"] = grant.ID
+	}
+	recordAudit(c, h.auditService, entry, err)
+	if err != nil {
+		respondObjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewResponse("Object grant created", grant))
+}
+
+// DeleteGrant 處理刪除實例授權的請求
+// @Summary 刪除實例授權
+// @Description 刪除用戶的一筆實例授權
+// @Tags Objects
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "用戶ID"
+// @Param grantId path string true "實例授權ID"
+// @Success 200 {object} domain.Response "實例授權刪除成功"
+// @Failure 400 {object} domain.Response "無效的用戶ID"
+// @Failure 403 {object} domain.Response "沒有 object:manage 權限"
+// @Failure 404 {object} domain.Response "用戶或實例授權未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users/{id}/object-grants/{grantId} [delete]
+func (h *ObjectHandler) DeleteGrant(c *gin.Context) {
+	err := h.objectService.DeleteObjectGrant(c, c.Param("id"), c.Param("grantId"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditObjectGrantDelete,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"grant_id": c.Param("grantId")},
+	}, err)
+	if err != nil {
+		respondObjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Object grant deleted", nil))
+}
+
+// GetOwner 處理獲取資源實例擁有者的請求
+// @Summary 獲取資源實例的擁有者
+// @Description 根據租戶、資源類型與實例 ID 獲取登記的擁有者，只返回該租戶的登記，不回退至全域登記
+// @Tags Objects
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param resource query string true "資源類型"
+// @Param object_id query string true "實例ID"
+// @Param tenant_id query int false "租戶ID，省略或為 0 表示全域"
+// @Success 200 {object} domain.Response{data=domain.ResourceOwner} "成功獲取擁有者"
+// @Failure 400 {object} domain.Response "無效的租戶ID、資源或實例ID"
+// @Failure 403 {object} domain.Response "沒有 object:manage 權限"
+// @Failure 404 {object} domain.Response "實例未登記擁有者"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /resource-owners [get]
+func (h *ObjectHandler) GetOwner(c *gin.Context) {
+	owner, err := h.objectService.GetResourceOwner(c, c.Query("tenant_id"), c.Query("resource"), c.Query("object_id"))
+	if err != nil {
+		respondObjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", owner))
+}
+
+// SetOwner 處理登記資源實例擁有者的請求
+// @Summary 登記資源實例的擁有者
+// @Description 登記或變更資源實例的擁有者，通常由擁有該資源的服務在建立實例時呼叫；擁有者依擁有者規則取得對該實例的操作。
+// @Description 帶入 tenant_id 時登記該租戶內的實例，擁有者須為租戶成員，且在該租戶內優先於全域登記；省略時為全域登記
+// @Tags Objects
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param request body ResourceOwnerRequest true "租戶、資源、實例與擁有者"
+// @Success 200 {object} domain.Response{data=domain.ResourceOwner} "登記成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗或擁有者不是租戶成員"
+// @Failure 403 {object} domain.Response "沒有 object:manage 權限"
+// @Failure 404 {object} domain.Response "用戶未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /resource-owners [put]
+func (h *ObjectHandler) SetOwner(c *gin.Context) {
+	var req ResourceOwnerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	owner, err := h.objectService.SetResourceOwner(c, &domain.ResourceOwner{
+		TenantID: req.TenantID,
+		Resource: req.Resource,
+		ObjectID: req.ObjectID,
+		OwnerID:  req.OwnerID,
+	})
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditResourceOwnerSet,
+		EntityType: domain.AuditEntityObject,
+		EntityID:   objectEntityID(req.Resource, req.ObjectID),
+		Details:    map[string]interface{}{"owner_id": req.OwnerID, "tenant_id": req.TenantID},
+	}, err)
+	if err != nil {
+		respondObjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Resource owner set", owner))
+}
+
+// DeleteOwner 處理移除資源實例擁有者的請求
+// @Summary 移除資源實例的擁有者
+// @Description 移除資源實例在租戶內的擁有者登記，通常於實例刪除時呼叫
+// @Tags Objects
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param resource query string true "資源類型"
+// @Param object_id query string true "實例ID"
+// @Param tenant_id query int false "租戶ID，省略或為 0 表示全域"
+// @Success 200 {object} domain.Response "移除成功"
+// @Failure 400 {object} domain.Response "無效的租戶ID、資源或實例ID"
+// @Failure 403 {object} domain.Response "沒有 object:manage 權限"
+// @Failure 404 {object} domain.Response "實例未登記擁有者"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /resource-owners [delete]
+func (h *ObjectHandler) DeleteOwner(c *gin.Context) {
+	resource, objectID := c.Query("resource"), c.Query("object_id")
+	err := h.objectService.DeleteResourceOwner(c, c.Query("tenant_id"), resource, objectID)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditResourceOwnerDelete,
+		EntityType: domain.AuditEntityObject,
+		EntityID:   objectEntityID(resource, objectID),
+		Details:    map[string]interface{}{"tenant_id": c.Query("tenant_id")},
+	}, err)
+	if err != nil {
+		respondObjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Resource owner deleted", nil))
+}
+
+// ListOwnerRules 處理列出擁有者規則的請求
+// @Summary 列出擁有者規則
+// @Description 列出擁有者對自己的資源實例自動取得的操作
+// @Tags Objects
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Success 200 {object} domain.Response{data=[]domain.OwnerRule} "成功獲取擁有者規則"
+// @Failure 403 {object} domain.Response "沒有 object:manage 權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /owner-rules [get]
+func (h *ObjectHandler) ListOwnerRules(c *gin.Context) {
+	rules, err := h.objectService.ListOwnerRules(c)
+	if err != nil {
+		respondObjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", rules))
+}
+
+// CreateOwnerRule 處理新增擁有者規則的請求
+// @Summary 新增擁有者規則
+// @Description 讓擁有者對自己的資源實例自動取得操作，例如 {"resource": "notice", "action": "edit"} 表示建立者可編輯自己的公告；資源與操作支援萬用字元
+// @Tags Objects
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param request body OwnerRuleRequest true "資源與操作"
+// @Success 201 {object} domain.Response{data=domain.OwnerRule} "擁有者規則新增成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗"
+// @Failure 403 {object} domain.Response "沒有 object:manage 權限"
+// @Failure 409 {object} domain.Response "擁有者規則已存在"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /owner-rules [post]
+func (h *ObjectHandler) CreateOwnerRule(c *gin.Context) {
+	var req OwnerRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	rule, err := h.objectService.CreateOwnerRule(c, &domain.OwnerRule{Resource: req.Resource, Action: req.Action})
+	entry := auditEntry{
+		Operation:  domain.AuditOwnerRuleCreate,
+		EntityType: domain.AuditEntityOwnerRule,
+		Details:    map[string]interface{}{"resource": req.Resource, "action": req.Action},
+	}
+	if rule != nil {
+		entry.EntityID = strconv.FormatInt(rule.ID, 10)
+	}
+	recordAudit(c, h.auditService, entry, err)
+	if err != nil {
+		respondObjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewResponse("Owner rule created", rule))
+}
+
+// DeleteOwnerRule 處理刪除擁有者規則的請求
+// @Summary 刪除擁有者規則
+// @Description 刪除擁有者規則，擁有者隨即失去該規則給予的操作
+// @Tags Objects
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "擁有者規則ID"
+// @Success 200 {object} domain.Response "擁有者規則刪除成功"
+// @Failure 403 {object} domain.Response "沒有 object:manage 權限"
+// @Failure 404 {object} domain.Response "擁有者規則未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /owner-rules/{id} [delete]
+func (h *ObjectHandler) DeleteOwnerRule(c *gin.Context) {
+	err := h.objectService.DeleteOwnerRule(c, c.Param("id"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditOwnerRuleDelete,
+		EntityType: domain.AuditEntityOwnerRule,
+		EntityID:   c.Param("id"),
+	}, err)
+	if err != nil {
+		respondObjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Owner rule deleted", nil))
+}
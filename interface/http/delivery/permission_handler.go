@@ -0,0 +1,205 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"rbac-service/domain"
+	"rbac-service/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionRequest 創建與更新權限的請求參數
+type PermissionRequest struct {
+	Resource    string `json:"resource" binding:"required,max=64" example:"notice"`
+	Action      string `json:"action" binding:"required,max=64" example:"publish"`
+	Description string `json:"description" binding:"max=255" example:"發布公告"`
+}
+
+// PermissionHandler 處理權限相關的 HTTP 請求
+type PermissionHandler struct {
+	permissionService *usecase.PermissionService
+	auditService      *usecase.AuditService
+}
+
+// NewPermissionHandler 創建新的 PermissionHandler
+func NewPermissionHandler(permissionService *usecase.PermissionService, auditService *usecase.AuditService) *PermissionHandler {
+	return &PermissionHandler{
+		permissionService: permissionService,
+		auditService:      auditService,
+	}
+}
+
+// respondPermissionError 將權限服務的錯誤轉換為對應的 HTTP 狀態碼
+func respondPermissionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrPermissionNotFound):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrInvalidPermissionID),
+		errors.Is(err, domain.ErrInvalidPermission),
+		errors.Is(err, domain.ErrInvalidDescription):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrPermissionAlreadyExists):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse("Request Failed", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+	}
+}
+
+// Create 處理創建權限的請求
+// @Summary 創建權限
+// @Description 創建新的權限，(resource, action) 不可重複
+// @Tags Permissions
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param permission body PermissionRequest true "權限信息"
+// @Success 201 {object} domain.Response{data=domain.Permission} "權限創建成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗"
+// @Failure 403 {object} domain.Response "沒有 permission:manage 權限"
+// @Failure 409 {object} domain.Response "權限已存在"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /permissions [post]
+func (h *PermissionHandler) Create(c *gin.Context) {
+	var req PermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	permission, err := h.permissionService.CreatePermission(c, &domain.Permission{
+		Resource:    req.Resource,
+		Action:      req.Action,
+		Description: req.Description,
+	})
+	entry := auditEntry{
+		Operation:  domain.AuditPermissionCreate,
+		EntityType: domain.AuditEntityPermission,
+		Details:    map[string]interface{}{"resource": req.Resource, "action": req.Action},
+	}
+	if permission != nil {
+		entry.EntityID = strconv.FormatInt(permission.ID, 10)
+	}
+	recordAudit(c, h.auditService, entry, err)
+	if err != nil {
+		respondPermissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewResponse("Permission created", permission))
+}
+
+// List 處理列出權限的請求
+// @Summary 列出權限
+// @Description 獲取權限列表，可依資源前綴過濾，例如 resource=notice 返回所有 notice:* 權限
+// @Tags Permissions
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param resource query string false "資源前綴"
+// @Success 200 {object} domain.Response{data=[]domain.Permission} "成功獲取權限列表"
+// @Failure 403 {object} domain.Response "沒有 permission:manage 權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /permissions [get]
+func (h *PermissionHandler) List(c *gin.Context) {
+	permissions, err := h.permissionService.ListPermissions(c, c.Query("resource"))
+	if err != nil {
+		respondPermissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", permissions))
+}
+
+// Get 處理獲取單個權限的請求
+// @Summary 獲取權限
+// @Description 根據ID獲取權限詳情
+// @Tags Permissions
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "權限ID"
+// @Success 200 {object} domain.Response{data=domain.Permission} "成功獲取權限信息"
+// @Failure 400 {object} domain.Response "無效的權限ID"
+// @Failure 403 {object} domain.Response "沒有 permission:manage 權限"
+// @Failure 404 {object} domain.Response "權限未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /permissions/{id} [get]
+func (h *PermissionHandler) Get(c *gin.Context) {
+	permission, err := h.permissionService.GetPermission(c, c.Param("id"))
+	if err != nil {
+		respondPermissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", permission))
+}
+
+// Update 處理更新權限的請求
+// @Summary 更新權限
+// @Description 更新權限的資源、操作與描述
+// @Tags Permissions
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "權限ID"
+// @Param permission body PermissionRequest true "權限信息"
+// @Success 200 {object} domain.Response{data=domain.Permission} "權限更新成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗或無效的權限ID"
+// @Failure 403 {object} domain.Response "沒有 permission:manage 權限"
+// @Failure 404 {object} domain.Response "權限未找到"
+// @Failure 409 {object} domain.Response "權限已存在"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /permissions/{id} [put]
+func (h *PermissionHandler) Update(c *gin.Context) {
+	var req PermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	permission, err := h.permissionService.UpdatePermission(c, c.Param("id"), &domain.Permission{
+		Resource:    req.Resource,
+		Action:      req.Action,
+		Description: req.Description,
+	})
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditPermissionUpdate,
+		EntityType: domain.AuditEntityPermission,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"resource": req.Resource, "action": req.Action},
+	}, err)
+	if err != nil {
+		respondPermissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Permission updated", permission))
+}
+
+// Delete 處理刪除權限的請求
+// @Summary 刪除權限
+// @Description 根據ID刪除權限，並移除其在所有角色上的分配
+// @Tags Permissions
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "權限ID"
+// @Success 200 {object} domain.Response "權限刪除成功"
+// @Failure 400 {object} domain.Response "無效的權限ID"
+// @Failure 403 {object} domain.Response "沒有 permission:manage 權限"
+// @Failure 404 {object} domain.Response "權限未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /permissions/{id} [delete]
+func (h *PermissionHandler) Delete(c *gin.Context) {
+	err := h.permissionService.DeletePermission(c, c.Param("id"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditPermissionDelete,
+		EntityType: domain.AuditEntityPermission,
+		EntityID:   c.Param("id"),
+	}, err)
+	if err != nil {
+		respondPermissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Permission deleted", nil))
+}
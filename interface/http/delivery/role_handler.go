@@ -0,0 +1,279 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"rbac-service/domain"
+	"rbac-service/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleRequest 創建與更新角色的請求參數
+type RoleRequest struct {
+	Name        string `json:"name" binding:"required,max=64" example:"operator"`
+	Description string `json:"description" binding:"max=255" example:"運營"`
+}
+
+// RoleParentsRequest 設定父角色的請求參數，空列表表示移除所有繼承
+type RoleParentsRequest struct {
+	ParentIDs []int64 `json:"parent_ids" binding:"required,max=20" example:"2,3"`
+}
+
+// RoleHandler 處理角色相關的 HTTP 請求
+type RoleHandler struct {
+	roleService  *usecase.RoleService
+	auditService *usecase.AuditService
+}
+
+// NewRoleHandler 創建新的 RoleHandler
+func NewRoleHandler(roleService *usecase.RoleService, auditService *usecase.AuditService) *RoleHandler {
+	return &RoleHandler{
+		roleService:  roleService,
+		auditService: auditService,
+	}
+}
+
+// respondRoleError 將角色服務的錯誤轉換為對應的 HTTP 狀態碼
+func respondRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrInvalidRoleID),
+		errors.Is(err, domain.ErrInvalidRoleParents),
+		errors.Is(err, domain.ErrInvalidRoleName),
+		errors.Is(err, domain.ErrInvalidDescription):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrRoleAlreadyExists),
+		errors.Is(err, domain.ErrRoleInUse),
+		errors.Is(err, domain.ErrRoleHierarchyCycle):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse("Request Failed", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+	}
+}
+
+// Create 處理創建角色的請求
+// @Summary 創建角色
+// @Description 創建新的角色，名稱不可重複
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param role body RoleRequest true "角色信息"
+// @Success 201 {object} domain.Response{data=domain.Role} "角色創建成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗"
+// @Failure 403 {object} domain.Response "沒有 role:manage 權限"
+// @Failure 409 {object} domain.Response "角色名稱已存在"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /roles [post]
+func (h *RoleHandler) Create(c *gin.Context) {
+	var req RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	role, err := h.roleService.CreateRole(c, &domain.Role{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	entry := auditEntry{
+		Operation:  domain.AuditRoleCreate,
+		EntityType: domain.AuditEntityRole,
+		Details:    map[string]interface{}{"name": req.Name, "description": req.Description},
+	}
+	if role != nil {
+		entry.EntityID = strconv.FormatInt(role.ID, 10)
+	}
+	recordAudit(c, h.auditService, entry, err)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewResponse("Role created", role))
+}
+
+// List 處理列出角色的請求
+// @Summary 列出角色
+// @Description 獲取所有角色
+// @Tags Roles
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Success 200 {object} domain.Response{data=[]domain.Role} "成功獲取角色列表"
+// @Failure 403 {object} domain.Response "沒有 role:manage 權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /roles [get]
+func (h *RoleHandler) List(c *gin.Context) {
+	roles, err := h.roleService.ListRoles(c)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", roles))
+}
+
+// Get 處理獲取單個角色的請求
+// @Summary 獲取角色
+// @Description 根據ID獲取角色詳情，包含其權限
+// @Tags Roles
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "角色ID"
+// @Success 200 {object} domain.Response{data=domain.Role} "成功獲取角色信息"
+// @Failure 400 {object} domain.Response "無效的角色ID"
+// @Failure 403 {object} domain.Response "沒有 role:manage 權限"
+// @Failure 404 {object} domain.Response "角色未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /roles/{id} [get]
+func (h *RoleHandler) Get(c *gin.Context) {
+	role, err := h.roleService.GetRole(c, c.Param("id"))
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", role))
+}
+
+// Update 處理更新角色的請求
+// @Summary 更新角色
+// @Description 更新角色名稱與描述
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "角色ID"
+// @Param role body RoleRequest true "角色信息"
+// @Success 200 {object} domain.Response{data=domain.Role} "角色更新成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗或無效的角色ID"
+// @Failure 403 {object} domain.Response "沒有 role:manage 權限"
+// @Failure 404 {object} domain.Response "角色未找到"
+// @Failure 409 {object} domain.Response "角色名稱已存在"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /roles/{id} [put]
+func (h *RoleHandler) Update(c *gin.Context) {
+	var req RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(c, c.Param("id"), &domain.Role{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditRoleUpdate,
+		EntityType: domain.AuditEntityRole,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"name": req.Name, "description": req.Description},
+	}, err)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Role updated", role))
+}
+
+// Delete 處理刪除角色的請求
+// @Summary 刪除角色
+// @Description 根據ID刪除角色；角色仍被分配給用戶時需帶 force=true 才會連同分配一併刪除
+// @Tags Roles
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "角色ID"
+// @Param force query bool false "強制刪除仍被分配的角色"
+// @Success 200 {object} domain.Response "角色刪除成功"
+// @Failure 400 {object} domain.Response "無效的角色ID"
+// @Failure 403 {object} domain.Response "沒有 role:manage 權限"
+// @Failure 404 {object} domain.Response "角色未找到"
+// @Failure 409 {object} domain.Response "角色仍被分配給用戶"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /roles/{id} [delete]
+func (h *RoleHandler) Delete(c *gin.Context) {
+	force, err := strconv.ParseBool(c.DefaultQuery("force", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid force parameter"))
+		return
+	}
+
+	err = h.roleService.DeleteRole(c, c.Param("id"), force)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditRoleDelete,
+		EntityType: domain.AuditEntityRole,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"force": force},
+	}, err)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Role deleted", nil))
+}
+
+// SetParents 處理設定角色父角色的請求
+// @Summary 設定父角色
+// @Description 以請求中的列表取代角色直接繼承的父角色，角色會繼承所有祖先角色的權限；設定後形成循環時拒絕寫入
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "角色ID"
+// @Param parents body RoleParentsRequest true "父角色ID列表"
+// @Success 200 {object} domain.Response{data=domain.Role} "父角色設定成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗或無效的角色ID"
+// @Failure 403 {object} domain.Response "沒有 role:manage 權限"
+// @Failure 404 {object} domain.Response "角色或父角色未找到"
+// @Failure 409 {object} domain.Response "繼承關係形成循環"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /roles/{id}/parents [put]
+func (h *RoleHandler) SetParents(c *gin.Context) {
+	var req RoleParentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	role, err := h.roleService.SetRoleParents(c, c.Param("id"), req.ParentIDs)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditRoleSetParents,
+		EntityType: domain.AuditEntityRole,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"parent_ids": req.ParentIDs},
+	}, err)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Role parents updated", role))
+}
+
+// EffectivePermissions 處理獲取角色有效權限的請求
+// @Summary 獲取角色有效權限
+// @Description 列出角色經由自身與所有祖先角色取得的權限，每項權限標示直接擁有它的角色，以及從查詢角色到該角色的最短繼承路徑
+// @Tags Roles
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "角色ID"
+// @Success 200 {object} domain.Response{data=[]domain.EffectivePermission} "成功獲取有效權限"
+// @Failure 400 {object} domain.Response "無效的角色ID"
+// @Failure 403 {object} domain.Response "沒有 role:manage 權限"
+// @Failure 404 {object} domain.Response "角色未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /roles/{id}/effective-permissions [get]
+func (h *RoleHandler) EffectivePermissions(c *gin.Context) {
+	permissions, err := h.roleService.GetEffectivePermissions(c, c.Param("id"))
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", permissions))
+}
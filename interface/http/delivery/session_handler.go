@@ -0,0 +1,107 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"rbac-service/domain"
+	"rbac-service/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler 處理登入會話相關的 HTTP 請求
+type SessionHandler struct {
+	authService  *usecase.AuthService
+	auditService *usecase.AuditService
+}
+
+// NewSessionHandler 創建新的 SessionHandler
+func NewSessionHandler(authService *usecase.AuthService, auditService *usecase.AuditService) *SessionHandler {
+	return &SessionHandler{
+		authService:  authService,
+		auditService: auditService,
+	}
+}
+
+// respondSessionError 將會話服務的錯誤轉換為對應的 HTTP 狀態碼
+func respondSessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse("Request Failed", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+	}
+}
+
+// List 處理列出目前用戶會話的請求
+// @Summary 列出登入會話
+// @Description 列出目前用戶所有有效的登入會話，current 標記目前使用的會話
+// @Tags Sessions
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Success 200 {object} domain.Response{data=[]domain.Session} "成功獲取會話列表"
+// @Failure 401 {object} domain.Response "未授權訪問"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /auth/sessions [get]
+func (h *SessionHandler) List(c *gin.Context) {
+	sessions, err := h.authService.ListSessions(c, c.GetString("session_id"))
+	if err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", sessions))
+}
+
+// Terminate 處理終止指定會話的請求
+// @Summary 終止登入會話
+// @Description 終止目前用戶的指定會話，該會話的令牌將立即失效
+// @Tags Sessions
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "會話ID"
+// @Success 200 {object} domain.Response "會話已終止"
+// @Failure 401 {object} domain.Response "未授權訪問"
+// @Failure 404 {object} domain.Response "會話未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /auth/sessions/{id} [delete]
+func (h *SessionHandler) Terminate(c *gin.Context) {
+	err := h.authService.TerminateSession(c, c.GetString("session_id"), c.Param("id"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditSessionTerminate,
+		EntityType: domain.AuditEntitySession,
+		EntityID:   c.Param("id"),
+	}, err)
+	if err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Session terminated", nil))
+}
+
+// TerminateAll 處理終止目前用戶所有會話的請求
+// @Summary 終止所有登入會話
+// @Description 終止目前用戶的所有會話，包含目前使用的會話
+// @Tags Sessions
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Success 200 {object} domain.Response "所有會話已終止"
+// @Failure 401 {object} domain.Response "未授權訪問"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /auth/sessions [delete]
+func (h *SessionHandler) TerminateAll(c *gin.Context) {
+	err := h.authService.TerminateAllSessions(c, c.GetString("session_id"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditSessionTerminate,
+		EntityType: domain.AuditEntitySession,
+		EntityID:   "*",
+		Details:    map[string]interface{}{"all": true},
+	}, err)
+	if err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("All sessions terminated", nil))
+}
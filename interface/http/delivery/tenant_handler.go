@@ -0,0 +1,309 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"rbac-service/domain"
+	"rbac-service/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTenantRequest 創建租戶的請求參數，code 建立後不可變更
+type CreateTenantRequest struct {
+	Code        string `json:"code" binding:"required,max=64" example:"game-a"`
+	Name        string `json:"name" binding:"required,max=64" example:"Game A"`
+	Description string `json:"description" binding:"max=255" example:"A 遊戲"`
+}
+
+// UpdateTenantRequest 更新租戶的請求參數
+type UpdateTenantRequest struct {
+	Name        string `json:"name" binding:"required,max=64" example:"Game A"`
+	Description string `json:"description" binding:"max=255" example:"A 遊戲"`
+}
+
+// TenantMemberRequest 加入租戶成員的請求參數
+type TenantMemberRequest struct {
+	UserID int64 `json:"user_id" binding:"required,gt=0" example:"3"`
+}
+
+// TenantHandler 處理租戶與成員相關的 HTTP 請求
+type TenantHandler struct {
+	tenantService *usecase.TenantService
+	auditService  *usecase.AuditService
+}
+
+// NewTenantHandler 創建新的 TenantHandler
+func NewTenantHandler(tenantService *usecase.TenantService, auditService *usecase.AuditService) *TenantHandler {
+	return &TenantHandler{
+		tenantService: tenantService,
+		auditService:  auditService,
+	}
+}
+
+// respondTenantError 將租戶服務的錯誤轉換為對應的 HTTP 狀態碼
+func respondTenantError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTenantNotFound),
+		errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrNotTenantMember):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrInvalidTenantID),
+		errors.Is(err, domain.ErrInvalidTenantCode),
+		errors.Is(err, domain.ErrInvalidTenantName),
+		errors.Is(err, domain.ErrInvalidDescription),
+		errors.Is(err, domain.ErrInvalidUserID):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+	case errors.Is(err, domain.ErrTenantAlreadyExists),
+		errors.Is(err, domain.ErrTenantMemberAlreadyExists):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse("Request Failed", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+	}
+}
+
+// Create 處理創建租戶的請求
+// @Summary 創建租戶
+// @Description 創建新的租戶，代碼為小寫英數字、"-" 與 "_"，不可重複且建立後不可變更；令牌與 X-Tenant 標頭以代碼識別租戶
+// @Tags Tenants
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param tenant body CreateTenantRequest true "租戶信息"
+// @Success 201 {object} domain.Response{data=domain.Tenant} "租戶創建成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗"
+// @Failure 403 {object} domain.Response "沒有 tenant:manage 權限"
+// @Failure 409 {object} domain.Response "租戶代碼已存在"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /tenants [post]
+func (h *TenantHandler) Create(c *gin.Context) {
+	var req CreateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	tenant, err := h.tenantService.CreateTenant(c, &domain.Tenant{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	entry := auditEntry{
+		Operation:  domain.AuditTenantCreate,
+		EntityType: domain.AuditEntityTenant,
+		Details:    map[string]interface{}{"code": req.Code, "name": req.Name, "description": req.Description},
+	}
+	if err == nil {
+		entry.EntityID = strconv.FormatInt(tenant.ID, 10)
+	}
+	recordAudit(c, h.auditService, entry, err)
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewResponse("Tenant created", tenant))
+}
+
+// List 處理列出租戶的請求
+// @Summary 列出租戶
+// @Description 獲取所有租戶
+// @Tags Tenants
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Success 200 {object} domain.Response{data=[]domain.Tenant} "成功獲取租戶列表"
+// @Failure 403 {object} domain.Response "沒有 tenant:manage 權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /tenants [get]
+func (h *TenantHandler) List(c *gin.Context) {
+	tenants, err := h.tenantService.ListTenants(c)
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", tenants))
+}
+
+// Get 處理獲取單個租戶的請求
+// @Summary 獲取租戶
+// @Description 根據ID獲取租戶詳情
+// @Tags Tenants
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "租戶ID"
+// @Success 200 {object} domain.Response{data=domain.Tenant} "成功獲取租戶信息"
+// @Failure 400 {object} domain.Response "無效的租戶ID"
+// @Failure 403 {object} domain.Response "沒有 tenant:manage 權限"
+// @Failure 404 {object} domain.Response "租戶未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /tenants/{id} [get]
+func (h *TenantHandler) Get(c *gin.Context) {
+	tenant, err := h.tenantService.GetTenant(c, c.Param("id"))
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", tenant))
+}
+
+// Update 處理更新租戶的請求
+// @Summary 更新租戶
+// @Description 更新租戶名稱與描述，代碼不可變更
+// @Tags Tenants
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "租戶ID"
+// @Param tenant body UpdateTenantRequest true "租戶信息"
+// @Success 200 {object} domain.Response{data=domain.Tenant} "租戶更新成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗或無效的租戶ID"
+// @Failure 403 {object} domain.Response "沒有 tenant:manage 權限"
+// @Failure 404 {object} domain.Response "租戶未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /tenants/{id} [put]
+func (h *TenantHandler) Update(c *gin.Context) {
+	var req UpdateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	tenant, err := h.tenantService.UpdateTenant(c, c.Param("id"), &domain.Tenant{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditTenantUpdate,
+		EntityType: domain.AuditEntityTenant,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"name": req.Name, "description": req.Description},
+	}, err)
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Tenant updated", tenant))
+}
+
+// Delete 處理刪除租戶的請求
+// @Summary 刪除租戶
+// @Description 根據ID刪除租戶，其成員資格與租戶內的角色分配一併移除；綁定該租戶的令牌在下次請求時被拒絕
+// @Tags Tenants
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "租戶ID"
+// @Success 200 {object} domain.Response "租戶刪除成功"
+// @Failure 400 {object} domain.Response "無效的租戶ID"
+// @Failure 403 {object} domain.Response "沒有 tenant:manage 權限"
+// @Failure 404 {object} domain.Response "租戶未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /tenants/{id} [delete]
+func (h *TenantHandler) Delete(c *gin.Context) {
+	err := h.tenantService.DeleteTenant(c, c.Param("id"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditTenantDelete,
+		EntityType: domain.AuditEntityTenant,
+		EntityID:   c.Param("id"),
+	}, err)
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Tenant deleted", nil))
+}
+
+// ListMembers 處理列出租戶成員的請求
+// @Summary 列出租戶成員
+// @Description 列出租戶的成員，roles 為全域角色，tenant_roles 為各租戶內的角色
+// @Tags Tenants
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "租戶ID"
+// @Success 200 {object} domain.Response{data=[]domain.User} "成功獲取成員列表"
+// @Failure 400 {object} domain.Response "無效的租戶ID"
+// @Failure 403 {object} domain.Response "沒有 tenant:manage 權限"
+// @Failure 404 {object} domain.Response "租戶未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /tenants/{id}/members [get]
+func (h *TenantHandler) ListMembers(c *gin.Context) {
+	members, err := h.tenantService.ListTenantMembers(c, c.Param("id"))
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", members))
+}
+
+// AddMember 處理加入租戶成員的請求
+// @Summary 加入租戶成員
+// @Description 將用戶加入租戶，成員才能在該租戶被分配角色或以其身分登入與請求
+// @Tags Tenants
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "租戶ID"
+// @Param request body TenantMemberRequest true "用戶ID"
+// @Success 201 {object} domain.Response "成員加入成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗"
+// @Failure 403 {object} domain.Response "沒有 tenant:manage 權限"
+// @Failure 404 {object} domain.Response "租戶或用戶未找到"
+// @Failure 409 {object} domain.Response "用戶已是租戶成員"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /tenants/{id}/members [post]
+func (h *TenantHandler) AddMember(c *gin.Context) {
+	var req TenantMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	err := h.tenantService.AddTenantMember(c, c.Param("id"), req.UserID)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditTenantMemberAdd,
+		EntityType: domain.AuditEntityTenant,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"user_id": req.UserID},
+	}, err)
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewResponse("Member added", nil))
+}
+
+// RemoveMember 處理移除租戶成員的請求
+// @Summary 移除租戶成員
+// @Description 將用戶移出租戶，其在該租戶內的角色分配一併移除，綁定該租戶的令牌在下次請求時被拒絕
+// @Tags Tenants
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "租戶ID"
+// @Param userId path string true "用戶ID"
+// @Success 200 {object} domain.Response "成員移除成功"
+// @Failure 400 {object} domain.Response "無效的ID"
+// @Failure 403 {object} domain.Response "沒有 tenant:manage 權限"
+// @Failure 404 {object} domain.Response "租戶未找到或用戶不是租戶成員"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /tenants/{id}/members/{userId} [delete]
+func (h *TenantHandler) RemoveMember(c *gin.Context) {
+	err := h.tenantService.RemoveTenantMember(c, c.Param("id"), c.Param("userId"))
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditTenantMemberRemove,
+		EntityType: domain.AuditEntityTenant,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"user_id": c.Param("userId")},
+	}, err)
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("Member removed", nil))
+}
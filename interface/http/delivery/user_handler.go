@@ -2,9 +2,11 @@ package delivery
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"rbac-service/domain"
 	"rbac-service/usecase"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -16,15 +18,22 @@ type UpdateUserRequest struct {
 	Password string `json:"password,omitempty"`
 }
 
+// UpdateUserMetadataRequest 更新用戶屬性的請求參數，以 metadata 取代所有現有屬性
+type UpdateUserMetadataRequest struct {
+	Metadata domain.UserMetadata `json:"metadata" binding:"required"`
+}
+
 // UserHandler 處理用戶相關的 HTTP 請求
 type UserHandler struct {
-	userService *usecase.UserService
+	userService  *usecase.UserService
+	auditService *usecase.AuditService
 }
 
 // NewUserHandler 創建新的 UserHandler
-func NewUserHandler(userService *usecase.UserService) *UserHandler {
+func NewUserHandler(userService *usecase.UserService, auditService *usecase.AuditService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:  userService,
+		auditService: auditService,
 	}
 }
 
@@ -83,6 +92,13 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 	// 調用用戶服務創建用戶
 	createdUser, err := h.userService.CreateUser(context.Background(), newUser)
+	// 註冊不經過 JWT 中介層，操作者即為註冊的用戶本身
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditUserCreate,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   req.Username,
+		Operator:   req.Username,
+	}, err)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "創建用戶失敗",
@@ -99,20 +115,99 @@ func (h *UserHandler) Create(c *gin.Context) {
 	})
 }
 
+// parseUserListFilter 解析用戶列表查詢參數
+func parseUserListFilter(c *gin.Context) (domain.UserListFilter, error) {
+	filter := domain.UserListFilter{
+		Username:      c.Query("username"),
+		UsernameMatch: c.Query("match"),
+		Status:        c.Query("status"),
+		SortBy:        c.Query("sort_by"),
+		SortOrder:     c.Query("order"),
+	}
+
+	if roleID := c.Query("role_id"); roleID != "" {
+		parsed, err := strconv.ParseInt(roleID, 10, 64)
+		if err != nil {
+			return filter, domain.ErrInvalidUserFilter
+		}
+		filter.RoleID = parsed
+	}
+	if tenantID := c.Query("tenant_id"); tenantID != "" {
+		parsed, err := strconv.ParseInt(tenantID, 10, 64)
+		if err != nil {
+			return filter, domain.ErrInvalidUserFilter
+		}
+		filter.TenantID = parsed
+	}
+	if offset := c.Query("offset"); offset != "" {
+		parsed, err := strconv.Atoi(offset)
+		if err != nil {
+			return filter, domain.ErrInvalidUserFilter
+		}
+		filter.Offset = parsed
+	}
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			return filter, domain.ErrInvalidUserFilter
+		}
+		filter.Limit = parsed
+	}
+
+	return filter, nil
+}
+
 // List 處理列出用戶的請求
+// @Summary 列出用戶
+// @Description 依用戶名、角色、租戶與狀態查詢用戶列表，支援 offset 與游標分頁，返回符合條件的總筆數；需要 user:view 權限
+// @Tags Users
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param username query string false "用戶名搜尋字串"
+// @Param match query string false "用戶名匹配方式：prefix（預設）或 contains"
+// @Param role_id query int false "角色ID"
+// @Param tenant_id query int false "租戶ID，只返回該租戶的成員"
+// @Param status query string false "用戶狀態：active 或 disabled"
+// @Param sort_by query string false "排序欄位：created_at（預設）或 updated_at"
+// @Param order query string false "排序方向：desc（預設）或 asc"
+// @Param offset query int false "略過的筆數，不可與 cursor 同時使用"
+// @Param cursor query string false "上一頁返回的 next_cursor"
+// @Param limit query int false "每頁筆數，預設 20，上限 100"
+// @Success 200 {object} domain.Response{data=domain.UserPage} "成功獲取用戶列表"
+// @Failure 400 {object} domain.Response "無效的查詢條件"
+// @Failure 403 {object} domain.Response "沒有 user:view 權限"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users [get]
 func (h *UserHandler) List(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	filter, err := parseUserListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+		return
+	}
+
+	page, err := h.userService.ListUsers(c, filter, c.Query("cursor"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidUserFilter) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("ok", page))
 }
 
 // Get 處理獲取單個用戶的請求
 // @Summary 獲取用戶詳情
-// @Description 根據用戶ID獲取用戶詳細信息
+// @Description 根據用戶ID獲取用戶詳細信息，查詢其他用戶需要 user:view 權限
 // @Tags Users
 // @Produce json
 // @Param Authorization header string true "Bearer Token"
 // @Param id path string true "用戶ID"
 // @Success 200 {object} domain.User "成功獲取用戶信息"
 // @Failure 400 {object} map[string]string "無效的用戶ID"
+// @Failure 403 {object} domain.Response "查詢其他用戶但沒有 user:view 權限"
 // @Failure 404 {object} map[string]string "用戶未找到"
 // @Failure 500 {object} map[string]string "服務器內部錯誤"
 // @Router /users/{id} [get]
@@ -190,6 +285,12 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 	// 調用用戶服務更新用戶
 	updatedUser, err := h.userService.UpdateUser(c, updateUser)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditUserUpdate,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   req.Username,
+		Details:    map[string]interface{}{"password_changed": req.Password != ""},
+	}, err)
 	if err != nil {
 		switch err {
 		case domain.ErrUserNotFound:
@@ -219,6 +320,50 @@ func (h *UserHandler) Update(c *gin.Context) {
 	})
 }
 
+// UpdateMetadata 處理更新用戶屬性的請求
+// @Summary 更新用戶屬性
+// @Description 以請求中的 metadata 取代用戶的所有屬性，供權限條件以 subject.<鍵> 引用；鍵須為英數字與底線且不可為 id 或 username，最多 32 個，值最長 256 位元組
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "用戶ID"
+// @Param request body UpdateUserMetadataRequest true "用戶屬性"
+// @Success 200 {object} domain.Response{data=domain.User} "更新成功"
+// @Failure 400 {object} domain.Response "參數驗證失敗或屬性無效"
+// @Failure 403 {object} domain.Response "沒有 user:manage 權限"
+// @Failure 404 {object} domain.Response "用戶未找到"
+// @Failure 500 {object} domain.Response "服務器內部錯誤"
+// @Router /users/{id}/metadata [put]
+func (h *UserHandler) UpdateMetadata(c *gin.Context) {
+	var req UpdateUserMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", "Invalid request parameters"))
+		return
+	}
+
+	user, err := h.userService.UpdateUserMetadata(c, c.Param("id"), req.Metadata)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditUserMetadataUpdate,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   c.Param("id"),
+		Details:    map[string]interface{}{"metadata": req.Metadata},
+	}, err)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse("Request Failed", err.Error()))
+		case errors.Is(err, domain.ErrInvalidUserID), errors.Is(err, domain.ErrInvalidUserMetadata):
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse("Request Failed", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewResponse("User metadata updated", user))
+}
+
 // Delete 處理刪除用戶的請求
 // @Summary 刪除用戶
 // @Description 根據用戶名直接刪除用戶
@@ -233,9 +378,13 @@ func (h *UserHandler) Delete(c *gin.Context) {
 	// 準備更新的用戶資訊
 	updateUser := &domain.User{
 		Username: c.GetString("username"),
-		Jwt:      c.GetString("token"),
 	}
 	err := h.userService.DeleteUser(c, updateUser)
+	recordAudit(c, h.auditService, auditEntry{
+		Operation:  domain.AuditUserDelete,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   updateUser.Username,
+	}, err)
 	if err != nil {
 		switch err {
 		case domain.ErrUserNotFound:
@@ -243,11 +392,6 @@ func (h *UserHandler) Delete(c *gin.Context) {
 				"error": domain.ErrUserNotFound.Error(),
 			})
 			return
-		case domain.ErrInvalidJwt:
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": domain.ErrInvalidJwt.Error(),
-			})
-			return
 
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -1,18 +1,36 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"rbac-service/domain"
 	"rbac-service/infrastructure/utils"
-	"rbac-service/usecase"
 
 	"github.com/gin-gonic/gin"
 )
 
+// TokenValidator JWTMiddleware 確認令牌有效與決定租戶所需的服務
+type TokenValidator interface {
+	ValidateClaims(ctx context.Context, claims *utils.Claims) error
+	ResolveTenant(ctx context.Context, claims *utils.Claims, requested string) (*domain.Tenant, error)
+}
+
+// PermissionChecker PermissionMiddleware 檢查權限所需的服務
+type PermissionChecker interface {
+	CheckPermission(ctx context.Context, userID string, token string, resource string, action string) (bool, error)
+}
+
+// Authenticator 路由所需的認證與授權服務，由 usecase.AuthService 實作
+type Authenticator interface {
+	TokenValidator
+	PermissionChecker
+}
+
 // JWTMiddleware 創建 JWT 驗證中間件
-func JWTMiddleware() gin.HandlerFunc {
+func JWTMiddleware(authService TokenValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 1. 從 Header 提取 token
 		token := c.GetHeader("Authorization")
@@ -25,51 +43,67 @@ func JWTMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 3. 檢查 token 是否過期
-		if utils.IsTokenExpired(token) {
-			c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", "Token expired"))
-			c.Abort()
-			return
-		}
-
-		// 4. 解析 token
+		// 3. 驗證並解析 token，過期與其他驗證失敗分別回應
 		claims, err := utils.ParseJWTToken(token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", "Invalid token"))
+			message := "Invalid token: " + err.Error()
+			if errors.Is(err, domain.ErrTokenExpired) {
+				message = "Token expired"
+			}
+			c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", message))
 			c.Abort()
 			return
 		}
 
-		// 5. 提取用戶名
-		username, ok := claims["username"].(string)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", "Invalid token claims"))
+		// 4. 檢查 token 是否已被撤銷、所屬的會話是否仍有效
+		if err := authService.ValidateClaims(c, claims); err != nil {
+			switch {
+			case errors.Is(err, domain.ErrTokenRevoked):
+				c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", "Token revoked"))
+			case errors.Is(err, domain.ErrSessionNotFound):
+				c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", "Token invalidated"))
+			default:
+				c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+			}
 			c.Abort()
 			return
 		}
 
-		// 6. 檢查 token 是否與數據庫一致
-		isValid, err := utils.CompareJWTToken(c, username, token)
-		if err != nil || !isValid {
-			c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", "Token invalidated"))
+		// 5. 決定請求所屬的租戶：令牌綁定的租戶優先，標頭只能省略或與其相同
+		tenant, err := authService.ResolveTenant(c, claims, c.GetHeader(domain.TenantHeader))
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrTenantMismatch), errors.Is(err, domain.ErrNotTenantMember), errors.Is(err, domain.ErrTenantNotFound):
+				c.JSON(http.StatusForbidden, domain.NewErrorResponse("Permission Denied", err.Error()))
+			case errors.Is(err, domain.ErrInvalidJwt):
+				c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", "Token invalidated"))
+			default:
+				c.JSON(http.StatusInternalServerError, domain.NewErrorResponse("Request Failed", domain.ErrInternalServerError.Error()))
+			}
 			c.Abort()
 			return
 		}
 
-		// 7. 將用戶信息存入 context
-		c.Set("username", username)
+		// 6. 將用戶信息存入 context，未指定租戶時 tenant 為空字串
+		c.Set("username", claims.Username)
+		c.Set("user_id", claims.Subject)
 		c.Set("token", token)
+		c.Set("session_id", claims.SessionID)
+		if tenant != nil {
+			c.Set("tenant", tenant.Code)
+			c.Set("tenant_id", tenant.ID)
+		}
 
-		// 8. 繼續處理請求
+		// 7. 繼續處理請求
 		c.Next()
 	}
 }
 
-// PermissionMiddleware 權限中間件
-func PermissionMiddleware(authService *usecase.AuthService, resource, action string) gin.HandlerFunc {
+// PermissionMiddleware 權限中間件，須在 JWTMiddleware 之後使用，用戶的全域權限沒有 resource:action 時返回 403
+func PermissionMiddleware(authService PermissionChecker, resource, action string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 從 context 獲取用戶信息
-		userID, exists := c.Get("userID")
+		username, exists := c.Get("username")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, domain.NewErrorResponse("Unauthorized", "Please login first"))
 			c.Abort()
@@ -84,7 +118,7 @@ func PermissionMiddleware(authService *usecase.AuthService, resource, action str
 		}
 
 		// 檢查權限
-		hasPermission, err := authService.CheckPermission(c, userID.(string), token.(string), resource, action)
+		hasPermission, err := authService.CheckPermission(c, username.(string), token.(string), resource, action)
 		if err != nil {
 			c.JSON(http.StatusForbidden, domain.NewErrorResponse("Permission Denied", err.Error()))
 			c.Abort()
@@ -100,3 +134,15 @@ func PermissionMiddleware(authService *usecase.AuthService, resource, action str
 		c.Next()
 	}
 }
+
+// SelfOrPermissionMiddleware 路徑參數 param 為目前用戶自己的 ID 時直接放行，否則與 PermissionMiddleware 相同要求全域的 resource:action
+func SelfOrPermissionMiddleware(authService PermissionChecker, param, resource, action string) gin.HandlerFunc {
+	requirePermission := PermissionMiddleware(authService, resource, action)
+	return func(c *gin.Context) {
+		if userID := c.GetString("user_id"); userID != "" && c.Param(param) == userID {
+			c.Next()
+			return
+		}
+		requirePermission(c)
+	}
+}
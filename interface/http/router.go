@@ -14,136 +14,188 @@ import (
 // SetupRouter 設置路由
 func SetupRouter(
 	r *gin.Engine,
+	authService middleware.Authenticator,
 	userHandler *delivery.UserHandler,
 	authHandler *delivery.AuthHandler,
+	roleHandler *delivery.RoleHandler,
+	permissionHandler *delivery.PermissionHandler,
+	assignmentHandler *delivery.AssignmentHandler,
+	auditHandler *delivery.AuditHandler,
+	sessionHandler *delivery.SessionHandler,
+	objectHandler *delivery.ObjectHandler,
+	tenantHandler *delivery.TenantHandler,
+	accessRequestHandler *delivery.AccessRequestHandler,
 ) {
 	// Swagger 路由
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	// 不走中介層的 api
 	r.POST("/v1/users/registry", userHandler.Create)
 	r.POST("/v1/auth/login", authHandler.Login)
+	// 訪問令牌過期後仍需能刷新，故不經過 JWT 中介層
+	r.POST("/v1/auth/refresh", authHandler.Refresh)
+	// 公開驗證令牌用的公鑰，供其他服務離線驗證
+	r.GET("/.well-known/jwks.json", authHandler.JWKS)
 	// 設定基本路由群組
 	v1 := r.Group("/v1")
-	v1.Use(middleware.JWTMiddleware())
+	v1.Use(middleware.JWTMiddleware(authService))
+	// requirePermission 要求用戶在請求租戶內擁有 resource:action，管理類 api 皆須經過
+	requirePermission := func(resource, action string) gin.HandlerFunc {
+		return middleware.PermissionMiddleware(authService, resource, action)
+	}
+	// requireSelfOrPermission 查詢自己（路徑的 :id）時不需權限，查詢其他用戶時要求 resource:action
+	requireSelfOrPermission := func(resource, action string) gin.HandlerFunc {
+		return middleware.SelfOrPermissionMiddleware(authService, "id", resource, action)
+	}
 	{
 		// 用戶管理路由
 		userGroup := v1.Group("/users")
 		{
-			// @Summary 列出用戶
-			// @Description 獲取用戶列表
-			// @Tags Users
-			// @Produce json
-			// @Success 200 {object} map[string]string
-			// @Router /users [get]
-			userGroup.GET("", userHandler.List)
-
-			userGroup.GET("/:id", userHandler.Get)
+			// 列出用戶
+			userGroup.GET("", requirePermission("user", "view"), userHandler.List)
+			// 獲取用戶，查詢自己不需權限
+			userGroup.GET("/:id", requireSelfOrPermission("user", "view"), userHandler.Get)
+			// 更新用戶屬性，供權限條件引用；屬性會影響授權結果，不可由用戶自行修改
+			userGroup.PUT("/:id/metadata", requirePermission("user", "manage"), userHandler.UpdateMetadata)
 
 			// @Summary 更新用戶
 			userGroup.PUT("/", userHandler.Update)
 
 			// @Summary 刪除用戶
 			userGroup.DELETE("/", userHandler.Delete)
+
+			// 為用戶分配角色
+			userGroup.POST("/:id/roles", requirePermission("user", "assign"), assignmentHandler.AssignUserRole)
+			// 移除用戶的角色
+			userGroup.DELETE("/:id/roles/:roleId", requirePermission("user", "assign"), assignmentHandler.RemoveUserRole)
+			// 獲取用戶所有權限
+			userGroup.GET("/:id/permissions", requireSelfOrPermission("user", "assign"), assignmentHandler.ListUserPermissions)
+			// 直接為用戶分配權限
+			userGroup.POST("/:id/permissions", requirePermission("user", "assign"), assignmentHandler.AssignUserPermission)
+			// 移除用戶直接分配的權限
+			userGroup.DELETE("/:id/permissions/:permId", requirePermission("user", "assign"), assignmentHandler.RemoveUserPermission)
+			// 列出用戶的實例授權
+			userGroup.GET("/:id/object-grants", requirePermission("object", "manage"), objectHandler.ListGrants)
+			// 為用戶新增實例授權
+			userGroup.POST("/:id/object-grants", requirePermission("object", "manage"), objectHandler.CreateGrant)
+			// 刪除用戶的實例授權
+			userGroup.DELETE("/:id/object-grants/:grantId", requirePermission("object", "manage"), objectHandler.DeleteGrant)
+			// 列出用戶可操作的資源實例
+			userGroup.GET("/:id/objects", requirePermission("object", "manage"), objectHandler.ListAccessibleObjects)
+		}
+
+		// 角色分配查詢路由
+		roleAssignmentGroup := v1.Group("/role-assignments")
+		{
+			// 列出即將過期的角色分配
+			roleAssignmentGroup.GET("/expiring", requirePermission("user", "assign"), assignmentHandler.ListExpiringRoleAssignments)
 		}
 
 		// 角色管理路由
 		roleGroup := v1.Group("/roles")
+		roleGroup.Use(requirePermission("role", "manage"))
 		{
-			// @Summary 創建角色
-			// @Description 創建新的角色
-			// @Tags Roles
-			// @Accept json
-			// @Produce json
-			// @Success 200 {object} map[string]string
-			// @Router /roles [post]
-			roleGroup.POST("", createRole)
-
-			// @Summary 列出角色
-			// @Description 獲取角色列表
-			// @Tags Roles
-			// @Produce json
-			// @Success 200 {object} map[string]string
-			// @Router /roles [get]
-			roleGroup.GET("", listRoles)
-
-			// @Summary 獲取角色
-			// @Description 根據ID獲取角色詳情
-			// @Tags Roles
-			// @Produce json
-			// @Param id path string true "角色ID"
-			// @Success 200 {object} map[string]string
-			// @Router /roles/{id} [get]
-			roleGroup.GET("/:id", getRole)
-
-			// @Summary 更新角色
-			// @Description 更新角色信息
-			// @Tags Roles
-			// @Accept json
-			// @Produce json
-			// @Param id path string true "角色ID"
-			// @Success 200 {object} map[string]string
-			// @Router /roles/{id} [put]
-			roleGroup.PUT("/:id", updateRole)
-
-			// @Summary 刪除角色
-			// @Description 根據ID刪除角色
-			// @Tags Roles
-			// @Produce json
-			// @Param id path string true "角色ID"
-			// @Success 200 {object} map[string]string
-			// @Router /roles/{id} [delete]
-			roleGroup.DELETE("/:id", deleteRole)
+			// 創建角色
+			roleGroup.POST("", roleHandler.Create)
+			// 列出角色
+			roleGroup.GET("", roleHandler.List)
+			// 獲取角色
+			roleGroup.GET("/:id", roleHandler.Get)
+			// 更新角色
+			roleGroup.PUT("/:id", roleHandler.Update)
+			// 刪除角色
+			roleGroup.DELETE("/:id", roleHandler.Delete)
+			// 為角色分配權限
+			roleGroup.POST("/:id/permissions", assignmentHandler.AssignRolePermission)
+			// 移除角色的權限
+			roleGroup.DELETE("/:id/permissions/:permId", assignmentHandler.RemoveRolePermission)
+			// 設定角色的父角色
+			roleGroup.PUT("/:id/parents", roleHandler.SetParents)
+			// 獲取角色的有效權限及其來源
+			roleGroup.GET("/:id/effective-permissions", roleHandler.EffectivePermissions)
 		}
 
 		// 權限管理路由
 		permissionGroup := v1.Group("/permissions")
+		permissionGroup.Use(requirePermission("permission", "manage"))
+		{
+			// 創建權限
+			permissionGroup.POST("", permissionHandler.Create)
+			// 列出權限
+			permissionGroup.GET("", permissionHandler.List)
+			// 獲取權限
+			permissionGroup.GET("/:id", permissionHandler.Get)
+			// 更新權限
+			permissionGroup.PUT("/:id", permissionHandler.Update)
+			// 刪除權限
+			permissionGroup.DELETE("/:id", permissionHandler.Delete)
+		}
+
+		// 資源實例擁有者路由
+		ownerGroup := v1.Group("/resource-owners")
+		ownerGroup.Use(requirePermission("object", "manage"))
+		{
+			// 獲取實例擁有者
+			ownerGroup.GET("", objectHandler.GetOwner)
+			// 登記或變更實例擁有者
+			ownerGroup.PUT("", objectHandler.SetOwner)
+			// 移除實例擁有者
+			ownerGroup.DELETE("", objectHandler.DeleteOwner)
+		}
+
+		// 擁有者規則路由
+		ownerRuleGroup := v1.Group("/owner-rules")
+		ownerRuleGroup.Use(requirePermission("object", "manage"))
 		{
-			// @Summary 創建權限
-			// @Description 創建新的權限
-			// @Tags Permissions
-			// @Accept json
-			// @Produce json
-			// @Success 200 {object} map[string]string
-			// @Router /permissions [post]
-			permissionGroup.POST("", createPermission)
-
-			// @Summary 列出權限
-			// @Description 獲取權限列表
-			// @Tags Permissions
-			// @Produce json
-			// @Success 200 {object} map[string]string
-			// @Router /permissions [get]
-			permissionGroup.GET("", listPermissions)
-
-			// @Summary 獲取權限
-			// @Description 根據ID獲取權限詳情
-			// @Tags Permissions
-			// @Produce json
-			// @Param id path string true "權限ID"
-			// @Success 200 {object} map[string]string
-			// @Router /permissions/{id} [get]
-			permissionGroup.GET("/:id", getPermission)
-
-			// @Summary 更新權限
-			// @Description 更新權限信息
-			// @Tags Permissions
-			// @Accept json
-			// @Produce json
-			// @Param id path string true "權限ID"
-			// @Success 200 {object} map[string]string
-			// @Router /permissions/{id} [put]
-			permissionGroup.PUT("/:id", updatePermission)
-
-			// @Summary 刪除權限
-			// @Description 根據ID刪除權限
-			// @Tags Permissions
-			// @Produce json
-			// @Param id path string true "權限ID"
-			// @Success 200 {object} map[string]string
-			// @Router /permissions/{id} [delete]
-			permissionGroup.DELETE("/:id", deletePermission)
+			// 列出擁有者規則
+			ownerRuleGroup.GET("", objectHandler.ListOwnerRules)
+			// 新增擁有者規則
+			ownerRuleGroup.POST("", objectHandler.CreateOwnerRule)
+			// 刪除擁有者規則
+			ownerRuleGroup.DELETE("/:id", objectHandler.DeleteOwnerRule)
 		}
 
+		// 租戶管理路由
+		tenantGroup := v1.Group("/tenants")
+		tenantGroup.Use(requirePermission("tenant", "manage"))
+		{
+			// 創建租戶
+			tenantGroup.POST("", tenantHandler.Create)
+			// 列出租戶
+			tenantGroup.GET("", tenantHandler.List)
+			// 獲取租戶
+			tenantGroup.GET("/:id", tenantHandler.Get)
+			// 更新租戶
+			tenantGroup.PUT("/:id", tenantHandler.Update)
+			// 刪除租戶
+			tenantGroup.DELETE("/:id", tenantHandler.Delete)
+			// 列出租戶成員
+			tenantGroup.GET("/:id/members", tenantHandler.ListMembers)
+			// 加入租戶成員
+			tenantGroup.POST("/:id/members", tenantHandler.AddMember)
+			// 移除租戶成員
+			tenantGroup.DELETE("/:id/members/:userId", tenantHandler.RemoveMember)
+		}
+
+		// 權限申請路由
+		accessRequestGroup := v1.Group("/access-requests")
+		{
+			// 申請角色
+			accessRequestGroup.POST("", accessRequestHandler.Create)
+			// 列出權限申請
+			accessRequestGroup.GET("", accessRequestHandler.List)
+			// 獲取權限申請
+			accessRequestGroup.GET("/:id", accessRequestHandler.Get)
+			// 核准權限申請
+			accessRequestGroup.POST("/:id/approve", accessRequestHandler.Approve)
+			// 駁回權限申請
+			accessRequestGroup.POST("/:id/reject", accessRequestHandler.Reject)
+			// 撤回權限申請
+			accessRequestGroup.POST("/:id/cancel", accessRequestHandler.Cancel)
+		}
+
+		// 審計日誌路由
+		v1.GET("/audit-logs", requirePermission("audit", "view"), auditHandler.List)
+
 		// 授權管理路由
 		authGroup := v1.Group("/auth")
 		{
@@ -151,12 +203,18 @@ func SetupRouter(
 			authGroup.POST("logout", authHandler.Logout)
 			// 權限驗證
 			authGroup.POST("authorize", authHandler.Authorize)
-			// 刷新令牌
-			authGroup.POST("refresh", authHandler.Refresh)
+			// 批量權限驗證
+			authGroup.POST("authorize/batch", authHandler.BatchAuthorize)
 			// 取消授權jwt
 			authGroup.POST("revoke", authHandler.Revoke)
 			// 批量取消授權jwt
 			authGroup.POST("batch-revoke", authHandler.BatchRevoke)
+			// 列出目前用戶的登入會話
+			authGroup.GET("sessions", sessionHandler.List)
+			// 終止指定會話
+			authGroup.DELETE("sessions/:id", sessionHandler.Terminate)
+			// 終止所有會話
+			authGroup.DELETE("sessions", sessionHandler.TerminateAll)
 		}
 	}
 
@@ -191,46 +249,6 @@ func deleteUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "ok"})
 }
 
-func createRole(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
-}
-
-func listRoles(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
-}
-
-func getRole(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
-}
-
-func updateRole(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
-}
-
-func deleteRole(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
-}
-
-func createPermission(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
-}
-
-func listPermissions(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
-}
-
-func getPermission(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
-}
-
-func updatePermission(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
-}
-
-func deletePermission(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "ok"})
-}
-
 func healthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 }
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"rbac-service/domain"
+	"rbac-service/infrastructure/utils"
+	"rbac-service/interface/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// denyingAuthenticator 接受所有令牌但拒絕所有權限檢查，並記錄被檢查的權限
+type denyingAuthenticator struct {
+	checked []string
+}
+
+func (a *denyingAuthenticator) ValidateClaims(ctx context.Context, claims *utils.Claims) error {
+	return nil
+}
+
+func (a *denyingAuthenticator) ResolveTenant(ctx context.Context, claims *utils.Claims, requested string) (*domain.Tenant, error) {
+	return nil, nil
+}
+
+func (a *denyingAuthenticator) CheckPermission(ctx context.Context, userID string, token string, resource string, action string) (bool, error) {
+	a.checked = append(a.checked, resource+":"+action)
+	return false, nil
+}
+
+func TestSetupRouter_ManagementRoutesRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	token, err := utils.GenerateJWTToken(2, "mallory", "session-1", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		method     string
+		path       string
+		permission string
+	}{
+		{http.MethodPost, "/v1/users/2/roles", "user:assign"},
+		{http.MethodDelete, "/v1/users/2/roles/1", "user:assign"},
+		{http.MethodPost, "/v1/users/2/permissions", "user:assign"},
+		{http.MethodDelete, "/v1/users/2/permissions/1", "user:assign"},
+		{http.MethodGet, "/v1/role-assignments/expiring", "user:assign"},
+		{http.MethodGet, "/v1/users/3/permissions", "user:assign"},
+		{http.MethodGet, "/v1/users", "user:view"},
+		{http.MethodGet, "/v1/users/3", "user:view"},
+		{http.MethodPost, "/v1/roles", "role:manage"},
+		{http.MethodGet, "/v1/roles", "role:manage"},
+		{http.MethodGet, "/v1/roles/1", "role:manage"},
+		{http.MethodPut, "/v1/roles/1", "role:manage"},
+		{http.MethodDelete, "/v1/roles/1", "role:manage"},
+		{http.MethodPost, "/v1/roles/1/permissions", "role:manage"},
+		{http.MethodDelete, "/v1/roles/1/permissions/2", "role:manage"},
+		{http.MethodPut, "/v1/roles/1/parents", "role:manage"},
+		{http.MethodGet, "/v1/roles/1/effective-permissions", "role:manage"},
+		{http.MethodPost, "/v1/permissions", "permission:manage"},
+		{http.MethodGet, "/v1/permissions", "permission:manage"},
+		{http.MethodGet, "/v1/permissions/1", "permission:manage"},
+		{http.MethodPut, "/v1/permissions/1", "permission:manage"},
+		{http.MethodDelete, "/v1/permissions/1", "permission:manage"},
+		{http.MethodGet, "/v1/audit-logs", "audit:view"},
+		{http.MethodPut, "/v1/users/2/metadata", "user:manage"},
+		{http.MethodGet, "/v1/users/2/object-grants", "object:manage"},
+		{http.MethodPost, "/v1/users/2/object-grants", "object:manage"},
+		{http.MethodDelete, "/v1/users/2/object-grants/1", "object:manage"},
+		{http.MethodGet, "/v1/users/2/objects", "object:manage"},
+		{http.MethodGet, "/v1/resource-owners", "object:manage"},
+		{http.MethodPut, "/v1/resource-owners", "object:manage"},
+		{http.MethodDelete, "/v1/resource-owners", "object:manage"},
+		{http.MethodGet, "/v1/owner-rules", "object:manage"},
+		{http.MethodPost, "/v1/owner-rules", "object:manage"},
+		{http.MethodDelete, "/v1/owner-rules/1", "object:manage"},
+		{http.MethodPost, "/v1/tenants", "tenant:manage"},
+		{http.MethodGet, "/v1/tenants", "tenant:manage"},
+		{http.MethodGet, "/v1/tenants/1", "tenant:manage"},
+		{http.MethodPut, "/v1/tenants/1", "tenant:manage"},
+		{http.MethodDelete, "/v1/tenants/1", "tenant:manage"},
+		{http.MethodGet, "/v1/tenants/1/members", "tenant:manage"},
+		{http.MethodPost, "/v1/tenants/1/members", "tenant:manage"},
+		{http.MethodDelete, "/v1/tenants/1/members/2", "tenant:manage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			// 權限檢查未通過時不會呼叫 handler，因此不需要建立 handler
+			authenticator := &denyingAuthenticator{}
+			r := gin.New()
+			SetupRouter(r, authenticator, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Equal(t, []string{tt.permission}, authenticator.checked)
+		})
+	}
+}
+
+func TestSelfOrPermissionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	token, err := utils.GenerateJWTToken(2, "mallory", "session-1", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		checked      []string
+	}{
+		{name: "self", path: "/v1/users/2/permissions", expectedCode: http.StatusOK},
+		{name: "other user", path: "/v1/users/3/permissions", expectedCode: http.StatusForbidden, checked: []string{"user:assign"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := &denyingAuthenticator{}
+			r := gin.New()
+			r.GET("/v1/users/:id/permissions",
+				middleware.JWTMiddleware(authenticator),
+				middleware.SelfOrPermissionMiddleware(authenticator, "id", "user", "assign"),
+				func(c *gin.Context) { c.Status(http.StatusOK) },
+			)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.checked, authenticator.checked)
+		})
+	}
+}
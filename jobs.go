@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"rbac-service/domain"
+	"rbac-service/usecase"
+)
+
+const (
+	// authPurgeInterval 清除過期撤銷記錄與會話的間隔
+	authPurgeInterval = time.Hour
+	// roleExpiryInterval 清除過期角色分配與逾期權限申請的間隔，過期的分配在清除前已不參與權限解析
+	roleExpiryInterval = time.Minute
+	// systemOperator 背景工作寫入審計日誌時的操作者
+	systemOperator = "system"
+	// signingKeySyncInterval 同步金鑰輪替狀態的間隔，提升或退役金鑰後最多經過此時間各實例才會套用
+	signingKeySyncInterval = time.Minute
+)
+
+// purgeExpiredAuthData 定期清除對應令牌皆已過期的撤銷記錄，以及已過期的會話
+func purgeExpiredAuthData(authService *usecase.AuthService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deleted, err := authService.PurgeExpiredRevocations(context.Background())
+		if err != nil {
+			log.Printf("Failed to purge expired token revocations: %v", err)
+		} else if deleted > 0 {
+			log.Printf("已清除 %d 筆過期的令牌撤銷記錄", deleted)
+		}
+
+		deleted, err = authService.PurgeExpiredSessions(context.Background())
+		if err != nil {
+			log.Printf("Failed to purge expired sessions: %v", err)
+		} else if deleted > 0 {
+			log.Printf("已清除 %d 筆過期的會話", deleted)
+		}
+	}
+}
+
+// syncSigningKeys 定期套用資料庫中的金鑰輪替狀態
+func syncSigningKeys(keyService *usecase.KeyService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := keyService.Sync(context.Background()); err != nil {
+			log.Printf("Failed to sync signing keys: %v", err)
+		}
+	}
+}
+
+// expireRoleAssignments 定期清除已過期的臨時角色分配，每筆清除的分配寫入一筆審計日誌
+func expireRoleAssignments(assignmentService *usecase.AssignmentService, auditService *usecase.AuditService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := context.Background()
+		expired, err := assignmentService.ExpireRoleAssignments(ctx)
+		if err != nil {
+			log.Printf("Failed to expire role assignments: %v", err)
+			continue
+		}
+
+		for _, assignment := range expired {
+			auditService.Record(ctx, &domain.AuditLog{
+				Operation:       domain.AuditUserRoleExpire,
+				EntityType:      domain.AuditEntityUser,
+				EntityID:        strconv.FormatInt(assignment.UserID, 10),
+				Operator:        systemOperator,
+				OperationResult: domain.AuditResultSuccess,
+			}, map[string]interface{}{
+				"role_id":     assignment.RoleID,
+				"tenant_id":   assignment.TenantID,
+				"valid_from":  assignment.ValidFrom,
+				"valid_until": assignment.ValidUntil,
+			})
+		}
+		if len(expired) > 0 {
+			log.Printf("已清除 %d 筆過期的角色分配", len(expired))
+		}
+	}
+}
+
+// expireAccessRequests 定期將逾期未審核的權限申請標記為 expired，每筆寫入一筆審計日誌
+func expireAccessRequests(accessRequestService *usecase.AccessRequestService, auditService *usecase.AuditService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := context.Background()
+		expired, err := accessRequestService.ExpireAccessRequests(ctx)
+		if err != nil {
+			log.Printf("Failed to expire access requests: %v", err)
+			continue
+		}
+
+		for _, request := range expired {
+			auditService.Record(ctx, &domain.AuditLog{
+				Operation:       domain.AuditAccessRequestExpire,
+				EntityType:      domain.AuditEntityAccessRequest,
+				EntityID:        strconv.FormatInt(request.ID, 10),
+				Operator:        systemOperator,
+				OperationResult: domain.AuditResultSuccess,
+			}, map[string]interface{}{
+				"requester_id": request.RequesterID,
+				"role_id":      request.RoleID,
+				"tenant_id":    request.TenantID,
+				"expires_at":   request.ExpiresAt,
+			})
+		}
+		if len(expired) > 0 {
+			log.Printf("已將 %d 筆逾期未審核的權限申請標記為 expired", len(expired))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"rbac-service/infrastructure/utils"
+	"rbac-service/usecase"
+)
+
+// defaultRetireGrace 退役的預設寬限期：涵蓋輪替前簽發的訪問令牌效期，以及其他實例套用輪替前的同步延遲
+const defaultRetireGrace = utils.AccessTokenTTL + signingKeySyncInterval
+
+// runKeysCommand 執行 keys 子命令：rbac-service keys list | promote <kid> | retire [-grace 2h] <kid>
+func runKeysCommand(keyService *usecase.KeyService, args []string) {
+	if len(args) == 0 {
+		log.Fatalf("Usage: rbac-service keys list | promote <kid> | retire [-grace 2h] <kid>")
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "list":
+		keys, err := keyService.ListSigningKeys(ctx)
+		if err != nil {
+			log.Fatalf("Failed to list signing keys: %v", err)
+		}
+		for _, key := range keys {
+			if key.RetireAt != nil {
+				log.Printf("%s\t%s\t%s", key.Kid, key.Status, key.RetireAt.Format(time.RFC3339))
+			} else {
+				log.Printf("%s\t%s", key.Kid, key.Status)
+			}
+		}
+	case "promote":
+		if len(args) != 2 {
+			log.Fatalf("Usage: rbac-service keys promote <kid>")
+		}
+		if err := keyService.Promote(ctx, args[1]); err != nil {
+			log.Fatalf("Failed to promote signing key %s: %v", args[1], err)
+		}
+		log.Printf("已將 %s 設為簽發金鑰，各實例將在 %s 內套用", args[1], signingKeySyncInterval)
+	case "retire":
+		flags := flag.NewFlagSet("keys retire", flag.ExitOnError)
+		grace := flags.Duration("grace", defaultRetireGrace, "退役前的寬限期，期間仍接受以該金鑰簽發的令牌")
+		if err := flags.Parse(args[1:]); err != nil {
+			log.Fatalf("Failed to parse keys retire arguments: %v", err)
+		}
+		if flags.NArg() != 1 {
+			log.Fatalf("Usage: rbac-service keys retire [-grace 2h] <kid>")
+		}
+
+		kid := flags.Arg(0)
+		retireAt, err := keyService.Retire(ctx, kid, *grace)
+		if err != nil {
+			log.Fatalf("Failed to retire signing key %s: %v", kid, err)
+		}
+		log.Printf("金鑰 %s 將於 %s 退役", kid, retireAt.Format(time.RFC3339))
+	default:
+		log.Fatalf("Unknown keys command: %s", args[0])
+	}
+}
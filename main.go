@@ -1,46 +1,134 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
 	_ "rbac-service/docs"
 	"rbac-service/interface/http"
 	"rbac-service/interface/http/delivery"
+	"time"
 
+	"rbac-service/domain"
+	"rbac-service/infrastructure/cache"
+	"rbac-service/infrastructure/config"
 	"rbac-service/infrastructure/database"
+	"rbac-service/infrastructure/pubsub"
 	"rbac-service/infrastructure/repository"
 	"rbac-service/infrastructure/utils"
 	"rbac-service/usecase"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
 type ServiceConfig struct {
 	////// 後續要改成map的形式以便支援多個db
 	Database *gorm.DB
+	Auth     config.AuthConfig
+	// Cache 用戶與有效權限查詢的快取，nil 表示不快取
+	Cache    domain.Cache
+	CacheTTL time.Duration
+	// Redis 快取與撤銷傳播共用的連線，未使用 Redis 時為 nil
+	Redis       redis.UniversalClient
+	RedisPrefix string
 }
 
 type ServiceContainer struct {
-	userService *usecase.UserService
-	authService *usecase.AuthService
-	userHandler *delivery.UserHandler
-	authHandler *delivery.AuthHandler
+	userService          *usecase.UserService
+	authService          *usecase.AuthService
+	roleService          *usecase.RoleService
+	permissionService    *usecase.PermissionService
+	assignmentService    *usecase.AssignmentService
+	seedService          *usecase.SeedService
+	auditService         *usecase.AuditService
+	keyService           *usecase.KeyService
+	objectService        *usecase.ObjectService
+	tenantService        *usecase.TenantService
+	accessRequestService *usecase.AccessRequestService
+	// revocationBuses stateless 模式接收其他實例撤銷記錄的來源
+	revocationBuses      []domain.RevocationBus
+	userHandler          *delivery.UserHandler
+	authHandler          *delivery.AuthHandler
+	roleHandler          *delivery.RoleHandler
+	permissionHandler    *delivery.PermissionHandler
+	assignmentHandler    *delivery.AssignmentHandler
+	auditHandler         *delivery.AuditHandler
+	sessionHandler       *delivery.SessionHandler
+	objectHandler        *delivery.ObjectHandler
+	tenantHandler        *delivery.TenantHandler
+	accessRequestHandler *delivery.AccessRequestHandler
 }
 
 func NewServiceContainer(config ServiceConfig) *ServiceContainer {
 	rbacRepo := repository.NewMySQLUserRepository(config.Database)
-	// utils
-	utils.NewUserRepo(rbacRepo)
+	authRepo := repository.NewMySQLAuthRepository(config.Database)
+	roleRepo := repository.NewMySQLRoleRepository(config.Database)
+	permissionRepo := repository.NewMySQLPermissionRepository(config.Database)
+	auditLogRepo := repository.NewMySQLAuditLogRepository(config.Database)
+	refreshTokenRepo := repository.NewMySQLRefreshTokenRepository(config.Database)
+	revocationRepo := repository.NewMySQLTokenRevocationRepository(config.Database)
+	sessionRepo := repository.NewMySQLSessionRepository(config.Database)
+	signingKeyRepo := repository.NewMySQLSigningKeyRepository(config.Database)
+	objectRepo := repository.NewMySQLObjectRepository(config.Database)
+	tenantRepo := repository.NewMySQLTenantRepository(config.Database)
+	accessRequestRepo := repository.NewMySQLAccessRequestRepository(config.Database)
+	if config.Cache != nil {
+		rbacRepo = repository.NewCachedUserRepository(rbacRepo, config.Cache, config.CacheTTL)
+		authRepo = repository.NewCachedAuthRepository(authRepo, config.Cache, config.CacheTTL)
+		roleRepo = repository.NewCachedRoleRepository(roleRepo, config.Cache)
+		permissionRepo = repository.NewCachedPermissionRepository(permissionRepo, config.Cache)
+		tenantRepo = repository.NewCachedTenantRepository(tenantRepo, config.Cache)
+		accessRequestRepo = repository.NewCachedAccessRequestRepository(accessRequestRepo, config.Cache)
+	}
+	// 撤銷傳播：定期查詢資料庫作為補償，redis 模式另以 pub/sub 即時通知
+	revocationBuses := []domain.RevocationBus{
+		pubsub.NewPollingRevocationBus(revocationRepo, time.Duration(config.Auth.RevocationSyncSeconds)*time.Second),
+	}
+	var revocationPublisher domain.RevocationBus
+	if config.Auth.RevocationPropagation == domain.RevocationPropagationRedis {
+		revocationPublisher = pubsub.NewRedisRevocationBus(config.Redis, config.RedisPrefix+"revocations")
+		revocationBuses = append(revocationBuses, revocationPublisher)
+	}
 	// Service
-	userService := usecase.NewUserService(rbacRepo)
-	authService := usecase.NewAuthService(rbacRepo)
+	authService := usecase.NewAuthService(authRepo, refreshTokenRepo, revocationRepo, sessionRepo, config.Auth.MaxSessionsPerUser, config.Auth.ValidationMode, revocationPublisher)
+	userService := usecase.NewUserService(rbacRepo, authService)
+	roleService := usecase.NewRoleService(roleRepo)
+	permissionService := usecase.NewPermissionService(permissionRepo)
+	assignmentService := usecase.NewAssignmentService(authRepo, roleRepo, permissionRepo)
+	seedService := usecase.NewSeedService(roleRepo, permissionRepo, authRepo)
+	auditService := usecase.NewAuditService(auditLogRepo)
+	keyService := usecase.NewKeyService(signingKeyRepo)
+	objectService := usecase.NewObjectService(authRepo, objectRepo)
+	tenantService := usecase.NewTenantService(tenantRepo, authRepo)
+	accessRequestService := usecase.NewAccessRequestService(accessRequestRepo, authRepo, roleRepo)
 
 	return &ServiceContainer{
-		userService: userService,
-		authService: authService,
-		userHandler: delivery.NewUserHandler(userService),
-		authHandler: delivery.NewAuthHandler(authService),
+		userService:          userService,
+		authService:          authService,
+		roleService:          roleService,
+		permissionService:    permissionService,
+		assignmentService:    assignmentService,
+		seedService:          seedService,
+		auditService:         auditService,
+		keyService:           keyService,
+		objectService:        objectService,
+		tenantService:        tenantService,
+		accessRequestService: accessRequestService,
+		revocationBuses:      revocationBuses,
+		userHandler:          delivery.NewUserHandler(userService, auditService),
+		authHandler:          delivery.NewAuthHandler(authService, auditService),
+		roleHandler:          delivery.NewRoleHandler(roleService, auditService),
+		permissionHandler:    delivery.NewPermissionHandler(permissionService, auditService),
+		assignmentHandler:    delivery.NewAssignmentHandler(assignmentService, auditService),
+		auditHandler:         delivery.NewAuditHandler(auditService),
+		sessionHandler:       delivery.NewSessionHandler(authService, auditService),
+		objectHandler:        delivery.NewObjectHandler(objectService, auditService),
+		tenantHandler:        delivery.NewTenantHandler(tenantService, auditService),
+		accessRequestHandler: delivery.NewAccessRequestHandler(accessRequestService, auditService),
 	}
 }
 
@@ -65,16 +153,106 @@ func main() {
 		log.Fatalf("Failed to get database connection: %v", err)
 	}
 
+	// 載入認證配置
+	authConfig, err := config.LoadAuthConfig(config.DefaultAuthConfigPath)
+	if err != nil {
+		log.Fatalf("Failed to load auth configuration: %v", err)
+	}
+
+	// 設定令牌的簽發與驗證方式，須在載入簽章金鑰前設定
+	err = utils.SetTokenOptions(utils.TokenOptions{
+		Issuer:     authConfig.Token.Issuer,
+		Audience:   authConfig.Token.Audience,
+		Leeway:     time.Duration(authConfig.Token.LeewaySeconds) * time.Second,
+		Algorithms: authConfig.Token.Algorithms,
+	})
+	if err != nil {
+		log.Fatalf("Failed to configure token validation: %v", err)
+	}
+
+	// 設定 JWT 簽章金鑰組
+	if err := configureSigningKeys(authConfig.JWT); err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
+	// 載入快取配置
+	cacheConfig, err := config.LoadCacheConfig(config.DefaultCacheConfigPath)
+	if err != nil {
+		log.Fatalf("Failed to load cache configuration: %v", err)
+	}
+
+	// 載入 Redis 配置，只在快取或撤銷傳播使用 Redis 時建立連線
+	redisConfig, err := config.LoadRedisConfig(config.DefaultRedisConfigPath)
+	if err != nil {
+		log.Fatalf("Failed to load redis configuration: %v", err)
+	}
+	var redisClient redis.UniversalClient
+	if cacheConfig.Driver == config.CacheDriverRedis || authConfig.RevocationPropagation == domain.RevocationPropagationRedis {
+		redisClient = newRedisClient(redisConfig)
+	}
+
 	serviceContainer := NewServiceContainer(ServiceConfig{
-		Database: rbacDB,
+		Database:    rbacDB,
+		Auth:        authConfig,
+		Cache:       newCache(cacheConfig, redisClient, redisConfig.Prefix),
+		CacheTTL:    time.Duration(cacheConfig.TTLSeconds) * time.Second,
+		Redis:       redisClient,
+		RedisPrefix: redisConfig.Prefix,
 	})
 
+	// 子命令：rbac-service seed [-file path] [-prune] [-admin user1,user2]
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		runSeedCommand(serviceContainer.seedService, os.Args[2:])
+		return
+	}
+
+	// 子命令：rbac-service keys list | promote <kid> | retire [-grace 2h] <kid>
+	if len(os.Args) > 1 && os.Args[1] == "keys" {
+		runKeysCommand(serviceContainer.keyService, os.Args[2:])
+		return
+	}
+
+	// 套用資料庫中的金鑰輪替狀態
+	if err := serviceContainer.keyService.Sync(context.Background()); err != nil {
+		log.Printf("Failed to sync signing keys: %v", err)
+	}
+
+	// 啟動時同步角色與權限，不移除執行期間新增的分配
+	if err := seedPermissions(serviceContainer.seedService, config.DefaultPermissionsPath, false); err != nil {
+		log.Printf("Failed to seed permissions: %v", err)
+	}
+
+	// stateless 模式以記憶體撤銷清單取代每個請求的資料庫查詢，啟動時先載入一次，之後接收其他實例的撤銷
+	if serviceContainer.authService.StatelessValidation() {
+		if err := serviceContainer.authService.ListenRevocations(context.Background(), serviceContainer.revocationBuses...); err != nil {
+			log.Fatalf("Failed to subscribe token revocations: %v", err)
+		}
+	}
+
 	// 設置路由
 	r := gin.Default()
 	r.Use(cors.Default())
+	// 定期清除已過期的令牌撤銷記錄與會話
+	go purgeExpiredAuthData(serviceContainer.authService, authPurgeInterval)
+	// 定期清除已過期的臨時角色分配並寫入審計日誌
+	go expireRoleAssignments(serviceContainer.assignmentService, serviceContainer.auditService, roleExpiryInterval)
+	// 定期將逾期未審核的權限申請標記為 expired 並寫入審計日誌
+	go expireAccessRequests(serviceContainer.accessRequestService, serviceContainer.auditService, roleExpiryInterval)
+	// 定期同步其他實例或 keys 子命令所做的金鑰輪替
+	go syncSigningKeys(serviceContainer.keyService, signingKeySyncInterval)
+
 	http.SetupRouter(r,
+		serviceContainer.authService,
 		serviceContainer.userHandler,
 		serviceContainer.authHandler,
+		serviceContainer.roleHandler,
+		serviceContainer.permissionHandler,
+		serviceContainer.assignmentHandler,
+		serviceContainer.auditHandler,
+		serviceContainer.sessionHandler,
+		serviceContainer.objectHandler,
+		serviceContainer.tenantHandler,
+		serviceContainer.accessRequestHandler,
 	)
 
 	// 啟動伺服器
@@ -83,3 +261,55 @@ func main() {
 		log.Fatalf("Server startup failed: %v", err)
 	}
 }
+
+// configureSigningKeys 載入設定的 JWT 簽章金鑰組，未設定時沿用啟動時隨機產生的臨時金鑰
+func configureSigningKeys(jwtConfig *config.JWTConfig) error {
+	if jwtConfig == nil {
+		log.Printf("未設定 JWT 簽章金鑰，使用啟動時隨機產生的臨時金鑰 %s：重啟後已簽發的令牌全部失效，且無法多實例部署，正式環境請於 %s 設定 jwt",
+			utils.CurrentSigningKeyID(), config.DefaultAuthConfigPath)
+		return nil
+	}
+
+	signingKeys := make([]*utils.SigningKey, 0, len(jwtConfig.Keys))
+	for _, keyConfig := range jwtConfig.Keys {
+		material, err := keyConfig.KeyMaterial()
+		if err != nil {
+			return err
+		}
+		key, err := utils.NewSigningKey(keyConfig.Kid, keyConfig.Algorithm, material)
+		if err != nil {
+			return fmt.Errorf("金鑰 %s: %w", keyConfig.Kid, err)
+		}
+		signingKeys = append(signingKeys, key)
+		log.Printf("JWT 簽章金鑰: kid=%s alg=%s", key.ID, key.Algorithm)
+	}
+
+	return utils.SetSigningKeys(signingKeys, jwtConfig.CurrentKid)
+}
+
+// newRedisClient 建立 Redis 連線，連線失敗不中止啟動
+func newRedisClient(redisConfig config.RedisConfig) redis.UniversalClient {
+	client := redis.NewClient(&redis.Options{
+		Addr:     redisConfig.Addr,
+		Password: redisConfig.Password,
+		DB:       redisConfig.DB,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		log.Printf("Failed to connect to redis %s: %v", redisConfig.Addr, err)
+	}
+	return client
+}
+
+// newCache 依設定建立快取，driver 為 none 時返回 nil
+func newCache(cacheConfig config.CacheConfig, redisClient redis.UniversalClient, prefix string) domain.Cache {
+	switch cacheConfig.Driver {
+	case config.CacheDriverMemory:
+		log.Printf("使用記憶體快取，多實例部署時其他實例的寫入最多延遲 %d 秒才會反映，請改用 redis", cacheConfig.TTLSeconds)
+		return cache.NewLRUCache(cacheConfig.Capacity)
+	case config.CacheDriverRedis:
+		// 快取操作失敗時會改查資料庫
+		return cache.NewRedisCache(redisClient, prefix+"cache:")
+	default:
+		return nil
+	}
+}
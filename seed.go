@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"strings"
+
+	"rbac-service/infrastructure/config"
+	"rbac-service/usecase"
+)
+
+// bootstrapAdminRole seed -admin 全域分配的角色，即 configs/permissions.json 中擁有 *:* 的管理員
+const bootstrapAdminRole = "admin"
+
+// seedPermissions 讀取種子設定並同步至資料庫，輸出新增、變更、未變動與移除的項目
+func seedPermissions(seedService *usecase.SeedService, path string, prune bool) error {
+	seeds, err := config.LoadRoleSeeds(path)
+	if err != nil {
+		return err
+	}
+
+	report, err := seedService.Seed(context.Background(), seeds, prune)
+	if report != nil {
+		log.Printf("權限種子同步 %s: 新增 %d、變更 %d、未變動 %d、移除 %d",
+			path, len(report.Added), len(report.Changed), len(report.Unchanged), len(report.Removed))
+		for _, item := range report.Added {
+			log.Printf("  + %s", item)
+		}
+		for _, item := range report.Changed {
+			log.Printf("  ~ %s", item)
+		}
+		for _, item := range report.Removed {
+			log.Printf("  - %s", item)
+		}
+	}
+	return err
+}
+
+// assignAdmins 將管理員角色全域分配給指定的用戶，輸出新增與未變動的分配
+func assignAdmins(seedService *usecase.SeedService, usernames []string) error {
+	report, err := seedService.AssignGlobalRole(context.Background(), bootstrapAdminRole, usernames)
+	if report != nil {
+		log.Printf("管理員分配: 新增 %d、未變動 %d", len(report.Added), len(report.Unchanged))
+		for _, item := range report.Added {
+			log.Printf("  + %s", item)
+		}
+	}
+	return err
+}
+
+// runSeedCommand 執行 seed 子命令：rbac-service seed [-file path] [-prune] [-admin user1,user2]
+func runSeedCommand(seedService *usecase.SeedService, args []string) {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	path := flags.String("file", config.DefaultPermissionsPath, "角色與權限種子設定檔")
+	prune := flags.Bool("prune", false, "移除設定檔中未列出的角色權限分配")
+	admins := flags.String("admin", "", "以逗號分隔的用戶名稱，全域分配 admin 角色")
+	if err := flags.Parse(args); err != nil {
+		log.Fatalf("Failed to parse seed arguments: %v", err)
+	}
+
+	if err := seedPermissions(seedService, *path, *prune); err != nil {
+		log.Fatalf("Failed to seed permissions: %v", err)
+	}
+	if *admins != "" {
+		if err := assignAdmins(seedService, strings.Split(*admins, ",")); err != nil {
+			log.Fatalf("Failed to assign admins: %v", err)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"rbac-service/domain"
+)
+
+const (
+	// defaultAccessRequestPageSize 權限申請預設每次列出的筆數
+	defaultAccessRequestPageSize = 50
+	// maxAccessRequestPageSize 權限申請每次列出的筆數上限
+	maxAccessRequestPageSize = 200
+)
+
+// AccessRequestService 即時權限申請與審核。核准的申請自動建立有效期間限定的角色分配
+type AccessRequestService struct {
+	requestRepo domain.AccessRequestRepository
+	authRepo    domain.AuthRepository
+	roleRepo    domain.RoleRepository
+}
+
+// NewAccessRequestService 創建權限申請服務
+func NewAccessRequestService(
+	requestRepo domain.AccessRequestRepository,
+	authRepo domain.AuthRepository,
+	roleRepo domain.RoleRepository,
+) *AccessRequestService {
+	return &AccessRequestService{
+		requestRepo: requestRepo,
+		authRepo:    authRepo,
+		roleRepo:    roleRepo,
+	}
+}
+
+// parseAccessRequestDuration 解析申請時長，須為正值、至少一秒且不超過 MaxAccessRequestDuration
+func parseAccessRequestDuration(duration string) (time.Duration, error) {
+	parsed, err := time.ParseDuration(strings.TrimSpace(duration))
+	if err != nil || parsed < time.Second || parsed > domain.MaxAccessRequestDuration {
+		return 0, domain.ErrInvalidAccessRequestDuration
+	}
+	return parsed.Truncate(time.Second), nil
+}
+
+// CreateAccessRequest 由 username 申請在租戶內擁有角色 duration 時長（Go duration 格式，例如 4h），
+// tenantID 為 0 時申請全域角色；租戶內的申請要求用戶為租戶成員，已擁有該角色或同一角色已有待審核的申請時不可申請
+func (s *AccessRequestService) CreateAccessRequest(ctx context.Context, username string, roleID, tenantID int64, duration, reason string) (*domain.AccessRequest, error) {
+	if roleID <= 0 {
+		return nil, domain.ErrInvalidRoleID
+	}
+	if tenantID < 0 {
+		return nil, domain.ErrInvalidTenantID
+	}
+	parsed, err := parseAccessRequestDuration(duration)
+	if err != nil {
+		return nil, err
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > domain.MaxAccessRequestReasonLength {
+		return nil, domain.ErrInvalidAccessRequestReason
+	}
+
+	requester, err := s.authRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.roleRepo.GetRoleByID(ctx, roleID); err != nil {
+		return nil, err
+	}
+	if err := requireTenantMember(ctx, s.authRepo, tenantID, requester.ID); err != nil {
+		return nil, err
+	}
+	if err := s.requireRoleNotHeld(ctx, requester.ID, roleID, tenantID); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.requestRepo.HasPendingAccessRequest(ctx, requester.ID, roleID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, domain.ErrAccessRequestAlreadyPending
+	}
+
+	request := &domain.AccessRequest{
+		RequesterID:     requester.ID,
+		RoleID:          roleID,
+		TenantID:        tenantID,
+		DurationSeconds: int64(parsed / time.Second),
+		Reason:          reason,
+		Status:          domain.AccessRequestPending,
+		ExpiresAt:       time.Now().Add(domain.AccessRequestPendingTTL),
+	}
+	if err := s.requestRepo.CreateAccessRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetAccessRequest 由 username 查看權限申請，只有申請人與在申請的租戶內擁有 access_request:approve 的審核者可以查看
+func (s *AccessRequestService) GetAccessRequest(ctx context.Context, id, username string) (*domain.AccessRequest, error) {
+	request, err := s.getAccessRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	viewer, err := s.authRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if viewer.ID == request.RequesterID {
+		return request, nil
+	}
+
+	approver, err := s.isApprover(ctx, viewer, request.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !approver {
+		return nil, domain.ErrAccessRequestForbidden
+	}
+	return request, nil
+}
+
+// ListAccessRequests 由 username 依狀態、申請人與租戶列出權限申請，新的在前。
+// 擁有全域 access_request:approve 的審核者可列出所有申請，指定租戶時在該租戶內擁有即可；
+// 其他用戶只能列出自己的申請，指定其他申請人時返回 ErrAccessRequestForbidden
+func (s *AccessRequestService) ListAccessRequests(ctx context.Context, username string, filter domain.AccessRequestFilter) ([]domain.AccessRequest, error) {
+	if filter.Status != "" && !domain.ValidAccessRequestStatus(filter.Status) {
+		return nil, domain.ErrInvalidAccessRequestFilter
+	}
+	if filter.RequesterID < 0 || filter.TenantID < 0 || filter.Limit < 0 {
+		return nil, domain.ErrInvalidAccessRequestFilter
+	}
+
+	viewer, err := s.authRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	approver, err := s.isApprover(ctx, viewer, filter.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !approver {
+		if filter.RequesterID != 0 && filter.RequesterID != viewer.ID {
+			return nil, domain.ErrAccessRequestForbidden
+		}
+		filter.RequesterID = viewer.ID
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultAccessRequestPageSize
+	}
+	if filter.Limit > maxAccessRequestPageSize {
+		filter.Limit = maxAccessRequestPageSize
+	}
+	return s.requestRepo.ListAccessRequests(ctx, filter)
+}
+
+// ApproveAccessRequest 由審核者核准申請，並為申請人建立自核准時起 duration 時長的角色分配。
+// 角色（含繼承）允許的權限須皆為審核者在申請的租戶內所擁有，避免審核者給予超出自身的權限
+func (s *AccessRequestService) ApproveAccessRequest(ctx context.Context, id, reviewerName, comment string) (*domain.AccessRequest, error) {
+	request, reviewer, err := s.review(ctx, id, reviewerName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireGrantable(ctx, reviewer, request); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	assignment, err := request.Approve(reviewer.ID, strings.TrimSpace(comment), now)
+	if errors.Is(err, domain.ErrAccessRequestExpired) {
+		return nil, s.expire(ctx, request, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 申請後申請人或角色可能已被刪除，或申請人已被移出租戶
+	if _, err := s.authRepo.GetByID(ctx, strconv.FormatInt(request.RequesterID, 10)); err != nil {
+		return nil, err
+	}
+	if _, err := s.roleRepo.GetRoleByID(ctx, request.RoleID); err != nil {
+		return nil, err
+	}
+	if err := requireTenantMember(ctx, s.authRepo, request.TenantID, request.RequesterID); err != nil {
+		return nil, err
+	}
+	// 申請後申請人可能已另外獲得該角色；尚未生效的相同分配同樣無法重複建立
+	if err := s.requireRoleNotHeld(ctx, request.RequesterID, request.RoleID, request.TenantID); err != nil {
+		return nil, err
+	}
+
+	err = s.requestRepo.ApproveAccessRequest(ctx, request, assignment)
+	if errors.Is(err, domain.ErrRoleAlreadyAssigned) {
+		return nil, domain.ErrAccessRequestRoleHeld
+	}
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// RejectAccessRequest 由審核者駁回申請
+func (s *AccessRequestService) RejectAccessRequest(ctx context.Context, id, reviewerName, comment string) (*domain.AccessRequest, error) {
+	request, reviewer, err := s.review(ctx, id, reviewerName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = request.Reject(reviewer.ID, strings.TrimSpace(comment), now)
+	if errors.Is(err, domain.ErrAccessRequestExpired) {
+		return nil, s.expire(ctx, request, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requestRepo.UpdateAccessRequestStatus(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// CancelAccessRequest 由申請人撤回待審核的申請
+func (s *AccessRequestService) CancelAccessRequest(ctx context.Context, id, username string) (*domain.AccessRequest, error) {
+	request, err := s.getAccessRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	requester, err := s.authRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if requester.ID != request.RequesterID {
+		return nil, domain.ErrNotAccessRequestRequester
+	}
+
+	now := time.Now()
+	err = request.Cancel(now)
+	if errors.Is(err, domain.ErrAccessRequestExpired) {
+		return nil, s.expire(ctx, request, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requestRepo.UpdateAccessRequestStatus(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// ExpireAccessRequests 將逾期未審核的申請標記為 expired，返回被標記的申請供呼叫端寫入審計日誌
+func (s *AccessRequestService) ExpireAccessRequests(ctx context.Context) ([]domain.AccessRequest, error) {
+	return s.requestRepo.ExpirePendingAccessRequests(ctx, time.Now())
+}
+
+// getAccessRequest 依 ID 載入權限申請，不檢查查看權限
+func (s *AccessRequestService) getAccessRequest(ctx context.Context, id string) (*domain.AccessRequest, error) {
+	requestID, err := parseID(id, domain.ErrInvalidAccessRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.requestRepo.GetAccessRequestByID(ctx, requestID)
+}
+
+// isApprover 判斷用戶在租戶內（全域加上該租戶的分配）是否擁有 access_request:approve
+func (s *AccessRequestService) isApprover(ctx context.Context, user *domain.User, tenantID int64) (bool, error) {
+	permissions, err := resolveUserPermissions(ctx, s.authRepo, user.ID, tenantID)
+	if err != nil {
+		return false, err
+	}
+	check := domain.PermissionCheck{Resource: domain.AccessRequestResource, Action: domain.AccessRequestApproveAction}
+	return decide(permissions, check, conditionAttributes(user, nil, time.Now())).Allowed, nil
+}
+
+// requireRoleNotHeld 申請人在租戶內（含全域分配）已擁有生效中的角色時返回 ErrAccessRequestRoleHeld
+func (s *AccessRequestService) requireRoleNotHeld(ctx context.Context, requesterID, roleID, tenantID int64) error {
+	roles, err := s.authRepo.GetRolesByUserID(ctx, requesterID, tenantID)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if role.ID == roleID {
+			return domain.ErrAccessRequestRoleHeld
+		}
+	}
+	return nil
+}
+
+// requireGrantable 確認審核者擁有申請的角色及其祖先角色允許的所有權限，否則返回 ErrAccessRequestEscalation。
+// 角色權限的資源與操作以字面比對審核者的權限，萬用字元只被審核者涵蓋相同或更廣範圍的萬用字元滿足
+func (s *AccessRequestService) requireGrantable(ctx context.Context, reviewer *domain.User, request *domain.AccessRequest) error {
+	held, err := resolveUserPermissions(ctx, s.authRepo, reviewer.ID, request.TenantID)
+	if err != nil {
+		return err
+	}
+	edges, err := s.authRepo.ListRoleParents(ctx)
+	if err != nil {
+		return err
+	}
+	granted, err := s.authRepo.GetPermissionsByRoleIDs(ctx, domain.NewRoleHierarchy(edges).Expand([]int64{request.RoleID}))
+	if err != nil {
+		return err
+	}
+
+	attributes := conditionAttributes(reviewer, nil, time.Now())
+	for _, permission := range granted {
+		if permission.Denies() {
+			continue
+		}
+		check := domain.PermissionCheck{Resource: permission.Resource, Action: permission.Action}
+		if !decide(held, check, attributes).Allowed {
+			return domain.ErrAccessRequestEscalation
+		}
+	}
+	return nil
+}
+
+// review 載入待審核的申請並確認審核者有權審核：不可審核自己的申請，且須在申請的租戶內擁有 access_request:approve
+func (s *AccessRequestService) review(ctx context.Context, id, reviewerName string) (*domain.AccessRequest, *domain.User, error) {
+	request, err := s.getAccessRequest(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	reviewer, err := s.authRepo.GetByUsername(ctx, reviewerName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if reviewer.ID == request.RequesterID {
+		return nil, nil, domain.ErrSelfReview
+	}
+
+	approver, err := s.isApprover(ctx, reviewer, request.TenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !approver {
+		return nil, nil, domain.ErrNotAccessRequestApprover
+	}
+	return request, reviewer, nil
+}
+
+// expire 將逾期未審核的申請標記為 expired，成功時返回 ErrAccessRequestExpired 告知呼叫端
+func (s *AccessRequestService) expire(ctx context.Context, request *domain.AccessRequest, now time.Time) error {
+	if err := request.Expire(now); err != nil {
+		return err
+	}
+	if err := s.requestRepo.UpdateAccessRequestStatus(ctx, request); err != nil {
+		return err
+	}
+	return domain.ErrAccessRequestExpired
+}
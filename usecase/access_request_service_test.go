@@ -0,0 +1,531 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"rbac-service/domain"
+)
+
+// MockAccessRequestRepository 模擬 AccessRequestRepository
+type MockAccessRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccessRequestRepository) CreateAccessRequest(ctx context.Context, request *domain.AccessRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockAccessRequestRepository) GetAccessRequestByID(ctx context.Context, id int64) (*domain.AccessRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessRequest), args.Error(1)
+}
+
+func (m *MockAccessRequestRepository) ListAccessRequests(ctx context.Context, filter domain.AccessRequestFilter) ([]domain.AccessRequest, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AccessRequest), args.Error(1)
+}
+
+func (m *MockAccessRequestRepository) HasPendingAccessRequest(ctx context.Context, requesterID, roleID, tenantID int64) (bool, error) {
+	args := m.Called(ctx, requesterID, roleID, tenantID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAccessRequestRepository) UpdateAccessRequestStatus(ctx context.Context, request *domain.AccessRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockAccessRequestRepository) ApproveAccessRequest(ctx context.Context, request *domain.AccessRequest, assignment *domain.UserRole) error {
+	args := m.Called(ctx, request, assignment)
+	return args.Error(0)
+}
+
+func (m *MockAccessRequestRepository) ExpirePendingAccessRequests(ctx context.Context, now time.Time) ([]domain.AccessRequest, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AccessRequest), args.Error(1)
+}
+
+func newTestAccessRequestService() (*AccessRequestService, *MockAccessRequestRepository, *MockAuthRepository, *MockRoleRepository) {
+	requestRepo := new(MockAccessRequestRepository)
+	authRepo := new(MockAuthRepository)
+	roleRepo := new(MockRoleRepository)
+	return NewAccessRequestService(requestRepo, authRepo, roleRepo), requestRepo, authRepo, roleRepo
+}
+
+// pendingAccessRequest 由 jared(1) 申請角色 2 四小時的待審核申請
+func pendingAccessRequest() *domain.AccessRequest {
+	return &domain.AccessRequest{
+		ID:              10,
+		RequesterID:     1,
+		RoleID:          2,
+		DurationSeconds: int64((4 * time.Hour).Seconds()),
+		Reason:          "週年慶活動",
+		Status:          domain.AccessRequestPending,
+		ExpiresAt:       time.Now().Add(domain.AccessRequestPendingTTL),
+	}
+}
+
+// mockReviewer 設定審核者 admin(9) 在全域範圍的權限，approver 為 false 時沒有 access_request:approve；
+// 申請的角色 2 只允許審核者也擁有的 user:view
+func mockReviewer(authRepo *MockAuthRepository, approver bool) {
+	authRepo.On("GetByUsername", mock.Anything, "admin").Return(&domain.User{ID: 9, Username: "admin"}, nil)
+	authRepo.On("GetRolesByUserID", mock.Anything, int64(9), domain.GlobalTenantID).Return([]domain.Role{{ID: 1, Name: "admin"}}, nil)
+	authRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+	permissions := []domain.Permission{{ID: 1, Resource: "user", Action: "view", Effect: domain.EffectAllow}}
+	if approver {
+		permissions = append(permissions, domain.Permission{ID: 2, Resource: "access_request", Action: "approve", Effect: domain.EffectAllow})
+	}
+	authRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{1}).Return(permissions, nil)
+	authRepo.On("GetPermissionsByUserID", mock.Anything, int64(9), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+	authRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{2}).Return([]domain.Permission{
+		{ID: 1, Resource: "user", Action: "view", Effect: domain.EffectAllow},
+	}, nil)
+}
+
+func TestAccessRequestService_CreateAccessRequest_Pending(t *testing.T) {
+	service, requestRepo, authRepo, roleRepo := newTestAccessRequestService()
+
+	authRepo.On("GetByUsername", mock.Anything, "jared").Return(&domain.User{ID: 1, Username: "jared"}, nil)
+	roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2, Name: "admin"}, nil)
+	authRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{}, nil)
+	requestRepo.On("HasPendingAccessRequest", mock.Anything, int64(1), int64(2), domain.GlobalTenantID).Return(false, nil)
+	requestRepo.On("CreateAccessRequest", mock.Anything, mock.MatchedBy(func(request *domain.AccessRequest) bool {
+		return request.RequesterID == 1 && request.RoleID == 2 && request.DurationSeconds == 4*3600 &&
+			request.Reason == "週年慶活動" && request.Status == domain.AccessRequestPending
+	})).Return(nil)
+
+	request, err := service.CreateAccessRequest(context.Background(), "jared", 2, 0, "4h", " 週年慶活動 ")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccessRequestPending, request.Status)
+	assert.WithinDuration(t, time.Now().Add(domain.AccessRequestPendingTTL), request.ExpiresAt, time.Minute)
+	requestRepo.AssertExpectations(t)
+}
+
+func TestAccessRequestService_CreateAccessRequest_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		tenantID int64
+		duration string
+		reason   string
+		err      error
+	}{
+		{"時長格式錯誤", 0, "four hours", "活動", domain.ErrInvalidAccessRequestDuration},
+		{"時長不可為負", 0, "-1h", "活動", domain.ErrInvalidAccessRequestDuration},
+		{"時長不可超過上限", 0, "721h", "活動", domain.ErrInvalidAccessRequestDuration},
+		{"理由不可為空", 0, "4h", "  ", domain.ErrInvalidAccessRequestReason},
+		{"租戶ID不可為負", -1, "4h", "活動", domain.ErrInvalidTenantID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+			_, err := service.CreateAccessRequest(context.Background(), "jared", 2, tt.tenantID, tt.duration, tt.reason)
+
+			assert.ErrorIs(t, err, tt.err)
+			authRepo.AssertNotCalled(t, "GetByUsername", mock.Anything, mock.Anything)
+			requestRepo.AssertNotCalled(t, "CreateAccessRequest", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAccessRequestService_CreateAccessRequest_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		member  bool
+		held    bool
+		pending bool
+		err     error
+	}{
+		{"非租戶成員不可申請租戶內的角色", false, false, false, domain.ErrNotTenantMember},
+		{"已擁有該角色", true, true, false, domain.ErrAccessRequestRoleHeld},
+		{"已有待審核的申請", true, false, true, domain.ErrAccessRequestAlreadyPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, requestRepo, authRepo, roleRepo := newTestAccessRequestService()
+
+			authRepo.On("GetByUsername", mock.Anything, "jared").Return(&domain.User{ID: 1}, nil)
+			roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2}, nil)
+			authRepo.On("IsTenantMember", mock.Anything, int64(5), int64(1)).Return(tt.member, nil)
+			held := []domain.Role{}
+			if tt.held {
+				held = append(held, domain.Role{ID: 2})
+			}
+			authRepo.On("GetRolesByUserID", mock.Anything, int64(1), int64(5)).Return(held, nil)
+			requestRepo.On("HasPendingAccessRequest", mock.Anything, int64(1), int64(2), int64(5)).Return(tt.pending, nil)
+
+			_, err := service.CreateAccessRequest(context.Background(), "jared", 2, 5, "4h", "活動")
+
+			assert.ErrorIs(t, err, tt.err)
+			requestRepo.AssertNotCalled(t, "CreateAccessRequest", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAccessRequestService_ApproveAccessRequest_Approved(t *testing.T) {
+	service, requestRepo, authRepo, roleRepo := newTestAccessRequestService()
+
+	requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(pendingAccessRequest(), nil)
+	mockReviewer(authRepo, true)
+	authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+	roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2}, nil)
+	authRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{}, nil)
+	requestRepo.On("ApproveAccessRequest", mock.Anything,
+		mock.MatchedBy(func(request *domain.AccessRequest) bool {
+			return request.Status == domain.AccessRequestApproved && *request.ReviewerID == 9
+		}),
+		mock.MatchedBy(func(assignment *domain.UserRole) bool {
+			return assignment.UserID == 1 && assignment.RoleID == 2 && assignment.ValidUntil != nil
+		}),
+	).Return(nil)
+
+	request, err := service.ApproveAccessRequest(context.Background(), "10", "admin", " 活動期間使用 ")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccessRequestApproved, request.Status)
+	assert.Equal(t, "活動期間使用", request.ReviewComment)
+	assert.WithinDuration(t, time.Now().Add(4*time.Hour), *request.GrantedUntil, time.Minute)
+	requestRepo.AssertExpectations(t)
+}
+
+func TestAccessRequestService_ApproveAccessRequest_NotApprover(t *testing.T) {
+	service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+	requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(pendingAccessRequest(), nil)
+	mockReviewer(authRepo, false)
+
+	_, err := service.ApproveAccessRequest(context.Background(), "10", "admin", "")
+
+	assert.ErrorIs(t, err, domain.ErrNotAccessRequestApprover)
+	requestRepo.AssertNotCalled(t, "ApproveAccessRequest", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccessRequestService_ApproveAccessRequest_Escalation(t *testing.T) {
+	// 審核者只擁有 access_request:approve 與 user:view，不可核准包含更多權限的角色
+	tests := []struct {
+		name    string
+		granted []domain.Permission
+		parents []domain.RoleParent
+	}{
+		{
+			name:    "admin role",
+			granted: []domain.Permission{{ID: 3, Resource: "*", Action: "*", Effect: domain.EffectAllow}},
+		},
+		{
+			name:    "permission inherited from parent role",
+			granted: []domain.Permission{{ID: 4, Resource: "tenant", Action: "manage", Effect: domain.EffectAllow}},
+			parents: []domain.RoleParent{{RoleID: 2, ParentID: 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+			request := pendingAccessRequest()
+			requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(request, nil)
+			authRepo.On("GetByUsername", mock.Anything, "admin").Return(&domain.User{ID: 9, Username: "admin"}, nil)
+			authRepo.On("GetRolesByUserID", mock.Anything, int64(9), domain.GlobalTenantID).Return([]domain.Role{{ID: 1, Name: "approver"}}, nil)
+			authRepo.On("ListRoleParents", mock.Anything).Return(tt.parents, nil)
+			authRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{1}).Return([]domain.Permission{
+				{ID: 1, Resource: "user", Action: "view", Effect: domain.EffectAllow},
+				{ID: 2, Resource: "access_request", Action: "approve", Effect: domain.EffectAllow},
+			}, nil)
+			authRepo.On("GetPermissionsByUserID", mock.Anything, int64(9), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+			authRepo.On("GetPermissionsByRoleIDs", mock.Anything, domain.NewRoleHierarchy(tt.parents).Expand([]int64{2})).Return(tt.granted, nil)
+
+			_, err := service.ApproveAccessRequest(context.Background(), "10", "admin", "")
+
+			assert.ErrorIs(t, err, domain.ErrAccessRequestEscalation)
+			assert.Equal(t, domain.AccessRequestPending, request.Status)
+			assert.Nil(t, request.ReviewerID)
+			requestRepo.AssertNotCalled(t, "ApproveAccessRequest", mock.Anything, mock.Anything, mock.Anything)
+			requestRepo.AssertNotCalled(t, "UpdateAccessRequestStatus", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAccessRequestService_ApproveAccessRequest_SelfReview(t *testing.T) {
+	service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+	requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(pendingAccessRequest(), nil)
+	authRepo.On("GetByUsername", mock.Anything, "jared").Return(&domain.User{ID: 1, Username: "jared"}, nil)
+
+	_, err := service.ApproveAccessRequest(context.Background(), "10", "jared", "")
+
+	assert.ErrorIs(t, err, domain.ErrSelfReview)
+	requestRepo.AssertNotCalled(t, "ApproveAccessRequest", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccessRequestService_ApproveAccessRequest_RoleHeld(t *testing.T) {
+	tests := []struct {
+		name string
+		// held 申請人生效中的角色，包含永久與尚未過期的臨時分配
+		held []domain.Role
+		// assignErr 寫入分配時的錯誤，尚未生效的相同分配只會在寫入時衝突
+		assignErr error
+	}{
+		{name: "active assignment", held: []domain.Role{{ID: 2}}},
+		{name: "assignment not yet active", held: []domain.Role{}, assignErr: domain.ErrRoleAlreadyAssigned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, requestRepo, authRepo, roleRepo := newTestAccessRequestService()
+
+			requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(pendingAccessRequest(), nil)
+			mockReviewer(authRepo, true)
+			authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+			roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2}, nil)
+			authRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return(tt.held, nil)
+			requestRepo.On("ApproveAccessRequest", mock.Anything, mock.Anything, mock.Anything).Return(tt.assignErr)
+
+			_, err := service.ApproveAccessRequest(context.Background(), "10", "admin", "")
+
+			assert.ErrorIs(t, err, domain.ErrAccessRequestRoleHeld)
+			if tt.assignErr == nil {
+				requestRepo.AssertNotCalled(t, "ApproveAccessRequest", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAccessRequestService_RejectAccessRequest_Rejected(t *testing.T) {
+	service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+	requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(pendingAccessRequest(), nil)
+	mockReviewer(authRepo, true)
+	requestRepo.On("UpdateAccessRequestStatus", mock.Anything, mock.MatchedBy(func(request *domain.AccessRequest) bool {
+		return request.Status == domain.AccessRequestRejected && *request.ReviewerID == 9 && request.GrantedUntil == nil
+	})).Return(nil)
+
+	request, err := service.RejectAccessRequest(context.Background(), "10", "admin", "不符合資格")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccessRequestRejected, request.Status)
+	assert.Equal(t, "不符合資格", request.ReviewComment)
+	requestRepo.AssertExpectations(t)
+}
+
+func TestAccessRequestService_Review_Expired(t *testing.T) {
+	reviews := map[string]func(*AccessRequestService) error{
+		"approve": func(s *AccessRequestService) error {
+			_, err := s.ApproveAccessRequest(context.Background(), "10", "admin", "")
+			return err
+		},
+		"reject": func(s *AccessRequestService) error {
+			_, err := s.RejectAccessRequest(context.Background(), "10", "admin", "")
+			return err
+		},
+	}
+
+	for name, review := range reviews {
+		t.Run(name, func(t *testing.T) {
+			service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+			stale := pendingAccessRequest()
+			stale.ExpiresAt = time.Now().Add(-time.Minute)
+			requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(stale, nil)
+			mockReviewer(authRepo, true)
+			requestRepo.On("UpdateAccessRequestStatus", mock.Anything, mock.MatchedBy(func(request *domain.AccessRequest) bool {
+				return request.Status == domain.AccessRequestExpired && request.ReviewerID == nil
+			})).Return(nil)
+
+			err := review(service)
+
+			assert.ErrorIs(t, err, domain.ErrAccessRequestExpired)
+			assert.Equal(t, domain.AccessRequestExpired, stale.Status)
+			requestRepo.AssertNotCalled(t, "ApproveAccessRequest", mock.Anything, mock.Anything, mock.Anything)
+			requestRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessRequestService_Review_NotPending(t *testing.T) {
+	for _, status := range []string{
+		domain.AccessRequestApproved,
+		domain.AccessRequestRejected,
+		domain.AccessRequestExpired,
+		domain.AccessRequestCancelled,
+	} {
+		t.Run(status, func(t *testing.T) {
+			service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+			closed := pendingAccessRequest()
+			closed.Status = status
+			requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(closed, nil)
+			mockReviewer(authRepo, true)
+
+			_, err := service.ApproveAccessRequest(context.Background(), "10", "admin", "")
+			assert.ErrorIs(t, err, domain.ErrAccessRequestNotPending)
+			_, err = service.RejectAccessRequest(context.Background(), "10", "admin", "")
+			assert.ErrorIs(t, err, domain.ErrAccessRequestNotPending)
+
+			assert.Equal(t, status, closed.Status)
+			requestRepo.AssertNotCalled(t, "ApproveAccessRequest", mock.Anything, mock.Anything, mock.Anything)
+			requestRepo.AssertNotCalled(t, "UpdateAccessRequestStatus", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAccessRequestService_CancelAccessRequest_Cancelled(t *testing.T) {
+	service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+	requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(pendingAccessRequest(), nil)
+	authRepo.On("GetByUsername", mock.Anything, "jared").Return(&domain.User{ID: 1, Username: "jared"}, nil)
+	requestRepo.On("UpdateAccessRequestStatus", mock.Anything, mock.MatchedBy(func(request *domain.AccessRequest) bool {
+		return request.Status == domain.AccessRequestCancelled
+	})).Return(nil)
+
+	request, err := service.CancelAccessRequest(context.Background(), "10", "jared")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccessRequestCancelled, request.Status)
+	requestRepo.AssertExpectations(t)
+}
+
+func TestAccessRequestService_CancelAccessRequest_NotRequester(t *testing.T) {
+	service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+	requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(pendingAccessRequest(), nil)
+	authRepo.On("GetByUsername", mock.Anything, "admin").Return(&domain.User{ID: 9, Username: "admin"}, nil)
+
+	_, err := service.CancelAccessRequest(context.Background(), "10", "admin")
+
+	assert.ErrorIs(t, err, domain.ErrNotAccessRequestRequester)
+	requestRepo.AssertNotCalled(t, "UpdateAccessRequestStatus", mock.Anything, mock.Anything)
+}
+
+func TestAccessRequestService_UpdateConflict(t *testing.T) {
+	// 並行的審核已先變更狀態時，條件更新失敗並返回 ErrAccessRequestNotPending
+	service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+	requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(pendingAccessRequest(), nil)
+	authRepo.On("GetByUsername", mock.Anything, "jared").Return(&domain.User{ID: 1, Username: "jared"}, nil)
+	requestRepo.On("UpdateAccessRequestStatus", mock.Anything, mock.Anything).Return(domain.ErrAccessRequestNotPending)
+
+	_, err := service.CancelAccessRequest(context.Background(), "10", "jared")
+
+	assert.ErrorIs(t, err, domain.ErrAccessRequestNotPending)
+}
+
+func TestAccessRequestService_ExpireAccessRequests(t *testing.T) {
+	service, requestRepo, _, _ := newTestAccessRequestService()
+
+	expired := []domain.AccessRequest{{ID: 10, Status: domain.AccessRequestExpired}}
+	requestRepo.On("ExpirePendingAccessRequests", mock.Anything, mock.AnythingOfType("time.Time")).Return(expired, nil)
+
+	requests, err := service.ExpireAccessRequests(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, expired, requests)
+}
+
+func TestAccessRequestService_ListAccessRequests(t *testing.T) {
+	service, requestRepo, authRepo, _ := newTestAccessRequestService()
+	mockReviewer(authRepo, true)
+
+	requestRepo.On("ListAccessRequests", mock.Anything, domain.AccessRequestFilter{Status: domain.AccessRequestPending, Limit: 200}).
+		Return([]domain.AccessRequest{}, nil)
+
+	_, err := service.ListAccessRequests(context.Background(), "admin", domain.AccessRequestFilter{Status: domain.AccessRequestPending, Limit: 1000})
+	require.NoError(t, err)
+
+	for _, filter := range []domain.AccessRequestFilter{{Status: "done"}, {Limit: -1}, {RequesterID: -1}, {TenantID: -1}} {
+		_, err := service.ListAccessRequests(context.Background(), "admin", filter)
+		assert.ErrorIs(t, err, domain.ErrInvalidAccessRequestFilter)
+	}
+	requestRepo.AssertNumberOfCalls(t, "ListAccessRequests", 1)
+}
+
+func TestAccessRequestService_ListAccessRequests_OwnRequestsOnly(t *testing.T) {
+	// 不是審核者時只列出自己的申請
+	service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+	authRepo.On("GetByUsername", mock.Anything, "jared").Return(&domain.User{ID: 1, Username: "jared"}, nil)
+	authRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{}, nil)
+	authRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{}).Return([]domain.Permission{}, nil)
+	authRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+	requestRepo.On("ListAccessRequests", mock.Anything, domain.AccessRequestFilter{RequesterID: 1, Limit: 50}).
+		Return([]domain.AccessRequest{*pendingAccessRequest()}, nil)
+
+	requests, err := service.ListAccessRequests(context.Background(), "jared", domain.AccessRequestFilter{})
+	require.NoError(t, err)
+	assert.Len(t, requests, 1)
+
+	_, err = service.ListAccessRequests(context.Background(), "jared", domain.AccessRequestFilter{RequesterID: 9})
+	assert.ErrorIs(t, err, domain.ErrAccessRequestForbidden)
+	requestRepo.AssertNumberOfCalls(t, "ListAccessRequests", 1)
+}
+
+func TestAccessRequestService_ListAccessRequests_TenantApprover(t *testing.T) {
+	// 只在租戶內擁有 access_request:approve 的審核者，指定該租戶時可列出租戶內所有申請
+	service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+	authRepo.On("GetByUsername", mock.Anything, "gm").Return(&domain.User{ID: 5, Username: "gm"}, nil)
+	authRepo.On("GetRolesByUserID", mock.Anything, int64(5), int64(3)).Return([]domain.Role{{ID: 4, Name: "gm"}}, nil)
+	authRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+	authRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{4}).Return([]domain.Permission{
+		{ID: 2, Resource: "access_request", Action: "approve", Effect: domain.EffectAllow},
+	}, nil)
+	authRepo.On("GetPermissionsByUserID", mock.Anything, int64(5), int64(3)).Return([]domain.Permission{}, nil)
+	requestRepo.On("ListAccessRequests", mock.Anything, domain.AccessRequestFilter{TenantID: 3, Limit: 50}).
+		Return([]domain.AccessRequest{}, nil)
+
+	_, err := service.ListAccessRequests(context.Background(), "gm", domain.AccessRequestFilter{TenantID: 3})
+
+	require.NoError(t, err)
+	requestRepo.AssertExpectations(t)
+}
+
+func TestAccessRequestService_GetAccessRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		approver    bool
+		expectedErr error
+	}{
+		{name: "requester", username: "jared"},
+		{name: "approver", username: "admin", approver: true},
+		{name: "other user", username: "admin", expectedErr: domain.ErrAccessRequestForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, requestRepo, authRepo, _ := newTestAccessRequestService()
+
+			requestRepo.On("GetAccessRequestByID", mock.Anything, int64(10)).Return(pendingAccessRequest(), nil)
+			authRepo.On("GetByUsername", mock.Anything, "jared").Return(&domain.User{ID: 1, Username: "jared"}, nil)
+			mockReviewer(authRepo, tt.approver)
+
+			request, err := service.GetAccessRequest(context.Background(), "10", tt.username)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, request)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(10), request.ID)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"rbac-service/domain"
+)
+
+const (
+	// defaultExpiringWindow 查詢即將過期分配的預設時間範圍
+	defaultExpiringWindow = 7 * 24 * time.Hour
+	// maxExpiringWindow 查詢即將過期分配的時間範圍上限
+	maxExpiringWindow = 90 * 24 * time.Hour
+)
+
+// AssignmentService 用戶角色與角色權限的關聯管理
+type AssignmentService struct {
+	authRepo       domain.AuthRepository
+	roleRepo       domain.RoleRepository
+	permissionRepo domain.PermissionRepository
+}
+
+// NewAssignmentService 創建關聯管理服務
+func NewAssignmentService(
+	authRepo domain.AuthRepository,
+	roleRepo domain.RoleRepository,
+	permissionRepo domain.PermissionRepository,
+) *AssignmentService {
+	return &AssignmentService{
+		authRepo:       authRepo,
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+	}
+}
+
+// getUser 解析用戶ID並確認用戶存在
+func (s *AssignmentService) getUser(ctx context.Context, id string) (*domain.User, error) {
+	userID, err := parseID(id, domain.ErrInvalidUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authRepo.GetByID(ctx, strconv.FormatInt(userID, 10))
+}
+
+// getRole 解析角色ID並確認角色存在
+func (s *AssignmentService) getRole(ctx context.Context, id string) (*domain.Role, error) {
+	roleID, err := parseID(id, domain.ErrInvalidRoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.roleRepo.GetRoleByID(ctx, roleID)
+}
+
+// getPermission 解析權限ID並確認權限存在
+func (s *AssignmentService) getPermission(ctx context.Context, id string) (*domain.Permission, error) {
+	permissionID, err := parseID(id, domain.ErrInvalidPermissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.permissionRepo.GetPermissionByID(ctx, permissionID)
+}
+
+// AssignRoleToUser 在租戶內為用戶分配角色，tenantID 為空或 0 時為全域分配；
+// 租戶內的分配要求用戶為該租戶成員。validFrom 與 validUntil 為分配的有效期間，nil 表示不限
+func (s *AssignmentService) AssignRoleToUser(ctx context.Context, userID, roleID, tenantID string, validFrom, validUntil *time.Time) error {
+	scopeID, err := parseTenantID(tenantID)
+	if err != nil {
+		return err
+	}
+	assignment := &domain.UserRole{TenantID: scopeID, ValidFrom: validFrom, ValidUntil: validUntil}
+	if err := assignment.ValidateValidity(time.Now()); err != nil {
+		return err
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	role, err := s.getRole(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if err := requireTenantMember(ctx, s.authRepo, scopeID, user.ID); err != nil {
+		return err
+	}
+
+	assignment.UserID = user.ID
+	assignment.RoleID = role.ID
+	return s.roleRepo.AssignRoleToUser(ctx, assignment)
+}
+
+// RemoveRoleFromUser 移除用戶在租戶內的角色，tenantID 為空或 0 時移除全域分配
+func (s *AssignmentService) RemoveRoleFromUser(ctx context.Context, userID, roleID, tenantID string) error {
+	scopeID, err := parseTenantID(tenantID)
+	if err != nil {
+		return err
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	role, err := s.getRole(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	return s.roleRepo.RemoveRoleFromUser(ctx, user.ID, role.ID, scopeID)
+}
+
+// ListExpiringRoleAssignments 列出在 within 時間內過期的角色分配，within 為 Go duration 格式（例如 72h），
+// 空字串時為 7 天，上限 90 天
+func (s *AssignmentService) ListExpiringRoleAssignments(ctx context.Context, within string) ([]domain.RoleAssignment, error) {
+	window := defaultExpiringWindow
+	if within = strings.TrimSpace(within); within != "" {
+		parsed, err := time.ParseDuration(within)
+		if err != nil || parsed <= 0 || parsed > maxExpiringWindow {
+			return nil, domain.ErrInvalidExpiringWindow
+		}
+		window = parsed
+	}
+
+	now := time.Now()
+	return s.roleRepo.ListExpiringUserRoles(ctx, now, now.Add(window))
+}
+
+// ExpireRoleAssignments 清除已過期的角色分配，返回被清除的分配供呼叫端寫入審計日誌
+func (s *AssignmentService) ExpireRoleAssignments(ctx context.Context) ([]domain.UserRole, error) {
+	return s.roleRepo.DeleteExpiredUserRoles(ctx, time.Now())
+}
+
+// GetUserPermissions 獲取用戶在租戶內經由所有角色與直接分配取得的權限及其效果，
+// tenantID 為空或 0 時只計入全域分配
+func (s *AssignmentService) GetUserPermissions(ctx context.Context, userID, tenantID string) ([]domain.Permission, error) {
+	scopeID, err := parseTenantID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveUserPermissions(ctx, s.authRepo, user.ID, scopeID)
+}
+
+// AssignPermissionToRole 為角色分配權限，effect 為 allow 或 deny，空字串視為 allow；
+// condition 為條件運算式，儲存前驗證語法，空字串表示無條件
+func (s *AssignmentService) AssignPermissionToRole(ctx context.Context, roleID, permissionID string, effect, condition string) error {
+	effect, err := domain.NormalizeEffect(effect)
+	if err != nil {
+		return err
+	}
+	condition, err = domain.NormalizeCondition(condition)
+	if err != nil {
+		return err
+	}
+	role, err := s.getRole(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	permission, err := s.getPermission(ctx, permissionID)
+	if err != nil {
+		return err
+	}
+
+	return s.roleRepo.AssignPermissionToRole(ctx, role.ID, permission.ID, effect, condition)
+}
+
+// RemovePermissionFromRole 移除角色的權限
+func (s *AssignmentService) RemovePermissionFromRole(ctx context.Context, roleID, permissionID string) error {
+	role, err := s.getRole(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	permission, err := s.getPermission(ctx, permissionID)
+	if err != nil {
+		return err
+	}
+
+	return s.roleRepo.RemovePermissionFromRole(ctx, role.ID, permission.ID)
+}
+
+// AssignPermissionToUser 在租戶內直接為用戶分配權限，不經由角色；tenantID 為空或 0 時為全域分配，
+// 租戶內的分配要求用戶為該租戶成員。effect 為 allow 或 deny，空字串視為 allow
+func (s *AssignmentService) AssignPermissionToUser(ctx context.Context, userID, permissionID, tenantID string, effect string) error {
+	effect, err := domain.NormalizeEffect(effect)
+	if err != nil {
+		return err
+	}
+	scopeID, err := parseTenantID(tenantID)
+	if err != nil {
+		return err
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	permission, err := s.getPermission(ctx, permissionID)
+	if err != nil {
+		return err
+	}
+	if err := requireTenantMember(ctx, s.authRepo, scopeID, user.ID); err != nil {
+		return err
+	}
+
+	return s.roleRepo.AssignPermissionToUser(ctx, &domain.UserPermission{
+		UserID:       user.ID,
+		PermissionID: permission.ID,
+		TenantID:     scopeID,
+		Effect:       effect,
+	})
+}
+
+// RemovePermissionFromUser 移除用戶在租戶內直接分配的權限，tenantID 為空或 0 時移除全域分配
+func (s *AssignmentService) RemovePermissionFromUser(ctx context.Context, userID, permissionID, tenantID string) error {
+	scopeID, err := parseTenantID(tenantID)
+	if err != nil {
+		return err
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	permission, err := s.getPermission(ctx, permissionID)
+	if err != nil {
+		return err
+	}
+
+	return s.roleRepo.RemovePermissionFromUser(ctx, user.ID, permission.ID, scopeID)
+}
@@ -0,0 +1,405 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"rbac-service/domain"
+)
+
+func newTestAssignmentService() (*AssignmentService, *MockAuthRepository, *MockRoleRepository, *MockPermissionRepository) {
+	authRepo := new(MockAuthRepository)
+	roleRepo := new(MockRoleRepository)
+	permissionRepo := new(MockPermissionRepository)
+	return NewAssignmentService(authRepo, roleRepo, permissionRepo), authRepo, roleRepo, permissionRepo
+}
+
+func TestAssignmentService_AssignRoleToUser_Successful(t *testing.T) {
+	// 準備測試數據
+	service, authRepo, roleRepo, _ := newTestAssignmentService()
+
+	// 設定模擬行為
+	authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1, Username: "jared"}, nil)
+	roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2, Name: "operator"}, nil)
+	roleRepo.On("AssignRoleToUser", mock.Anything, &domain.UserRole{UserID: 1, RoleID: 2}).Return(nil)
+
+	// 執行分配角色
+	err := service.AssignRoleToUser(context.Background(), "1", "2", "", nil, nil)
+
+	// 斷言
+	assert.NoError(t, err)
+	authRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestAssignmentService_AssignRoleToUser_UserNotFound(t *testing.T) {
+	// 準備測試數據
+	service, authRepo, roleRepo, _ := newTestAssignmentService()
+
+	// 設定模擬行為
+	authRepo.On("GetByID", mock.Anything, "9").Return(nil, domain.ErrUserNotFound)
+
+	// 執行分配角色
+	err := service.AssignRoleToUser(context.Background(), "9", "2", "", nil, nil)
+
+	// 斷言
+	assert.Equal(t, domain.ErrUserNotFound, err)
+	roleRepo.AssertNotCalled(t, "AssignRoleToUser", mock.Anything, mock.Anything)
+}
+
+func TestAssignmentService_AssignRoleToUser_Tenant(t *testing.T) {
+	tests := []struct {
+		name   string
+		member bool
+		err    error
+	}{
+		{"租戶成員可在租戶內被分配角色", true, nil},
+		{"非租戶成員無法在租戶內被分配角色", false, domain.ErrNotTenantMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, authRepo, roleRepo, _ := newTestAssignmentService()
+
+			authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+			roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2}, nil)
+			authRepo.On("IsTenantMember", mock.Anything, int64(5), int64(1)).Return(tt.member, nil)
+			roleRepo.On("AssignRoleToUser", mock.Anything, &domain.UserRole{UserID: 1, RoleID: 2, TenantID: 5}).Return(nil)
+
+			err := service.AssignRoleToUser(context.Background(), "1", "2", "5", nil, nil)
+
+			assert.ErrorIs(t, err, tt.err)
+			if tt.err != nil {
+				roleRepo.AssertNotCalled(t, "AssignRoleToUser", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAssignmentService_AssignRoleToUser_InvalidTenantID(t *testing.T) {
+	service, authRepo, roleRepo, _ := newTestAssignmentService()
+
+	err := service.AssignRoleToUser(context.Background(), "1", "2", "-1", nil, nil)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidTenantID)
+	authRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	roleRepo.AssertNotCalled(t, "AssignRoleToUser", mock.Anything, mock.Anything)
+}
+
+func TestAssignmentService_AssignRoleToUser_AlreadyAssigned(t *testing.T) {
+	// 準備測試數據
+	service, authRepo, roleRepo, _ := newTestAssignmentService()
+
+	// 設定模擬行為
+	authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+	roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2}, nil)
+	roleRepo.On("AssignRoleToUser", mock.Anything, &domain.UserRole{UserID: 1, RoleID: 2}).Return(domain.ErrRoleAlreadyAssigned)
+
+	// 執行分配角色
+	err := service.AssignRoleToUser(context.Background(), "1", "2", "", nil, nil)
+
+	// 斷言
+	assert.Equal(t, domain.ErrRoleAlreadyAssigned, err)
+}
+
+func TestAssignmentService_AssignRoleToUser_TimeBound(t *testing.T) {
+	service, authRepo, roleRepo, _ := newTestAssignmentService()
+
+	validFrom := time.Now().Add(time.Hour)
+	validUntil := validFrom.Add(24 * time.Hour)
+	authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+	roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2}, nil)
+	roleRepo.On("AssignRoleToUser", mock.Anything, &domain.UserRole{UserID: 1, RoleID: 2, ValidFrom: &validFrom, ValidUntil: &validUntil}).Return(nil)
+
+	err := service.AssignRoleToUser(context.Background(), "1", "2", "", &validFrom, &validUntil)
+
+	assert.NoError(t, err)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestAssignmentService_AssignRoleToUser_InvalidValidityPeriod(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	soon := now.Add(time.Hour)
+	later := now.Add(2 * time.Hour)
+
+	tests := []struct {
+		name       string
+		validFrom  *time.Time
+		validUntil *time.Time
+	}{
+		{"失效時間已過", nil, &past},
+		{"失效時間早於生效時間", &later, &soon},
+		{"失效時間等於生效時間", &soon, &soon},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, authRepo, roleRepo, _ := newTestAssignmentService()
+
+			err := service.AssignRoleToUser(context.Background(), "1", "2", "", tt.validFrom, tt.validUntil)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidValidityPeriod)
+			authRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+			roleRepo.AssertNotCalled(t, "AssignRoleToUser", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAssignmentService_ListExpiringRoleAssignments(t *testing.T) {
+	tests := []struct {
+		name   string
+		within string
+		window time.Duration
+	}{
+		{"預設為 7 天", "", 7 * 24 * time.Hour},
+		{"指定時間範圍", "72h", 72 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, roleRepo, _ := newTestAssignmentService()
+
+			expected := []domain.RoleAssignment{{UserID: 1, Username: "jared", RoleID: 2, RoleName: "operator"}}
+			roleRepo.On("ListExpiringUserRoles", mock.Anything, mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+				return before.Sub(time.Now()) <= tt.window && before.Sub(time.Now()) > tt.window-time.Minute
+			})).Return(expected, nil)
+
+			assignments, err := service.ListExpiringRoleAssignments(context.Background(), tt.within)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, assignments)
+			roleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAssignmentService_ListExpiringRoleAssignments_InvalidWindow(t *testing.T) {
+	for _, within := range []string{"abc", "-1h", "0s", "2161h"} {
+		t.Run(within, func(t *testing.T) {
+			service, _, roleRepo, _ := newTestAssignmentService()
+
+			_, err := service.ListExpiringRoleAssignments(context.Background(), within)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidExpiringWindow)
+			roleRepo.AssertNotCalled(t, "ListExpiringUserRoles", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAssignmentService_ExpireRoleAssignments(t *testing.T) {
+	service, _, roleRepo, _ := newTestAssignmentService()
+
+	validUntil := time.Now().Add(-time.Minute)
+	expired := []domain.UserRole{{UserID: 1, RoleID: 2, ValidUntil: &validUntil}}
+	roleRepo.On("DeleteExpiredUserRoles", mock.Anything, mock.AnythingOfType("time.Time")).Return(expired, nil)
+
+	assignments, err := service.ExpireRoleAssignments(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, expired, assignments)
+}
+
+func TestAssignmentService_RemoveRoleFromUser_InvalidRoleID(t *testing.T) {
+	// 準備測試數據
+	service, authRepo, roleRepo, _ := newTestAssignmentService()
+
+	// 設定模擬行為
+	authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+
+	// 執行移除角色
+	err := service.RemoveRoleFromUser(context.Background(), "1", "x", "")
+
+	// 斷言
+	assert.Equal(t, domain.ErrInvalidRoleID, err)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestAssignmentService_GetUserPermissions(t *testing.T) {
+	// 準備測試數據
+	service, authRepo, _, _ := newTestAssignmentService()
+
+	expected := []domain.Permission{
+		{ID: 1, Resource: "user", Action: "view"},
+		{ID: 2, Resource: "notice", Action: "view"},
+	}
+
+	// 設定模擬行為
+	authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+	authRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 2}, {ID: 3}}, nil)
+	authRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+	authRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{2, 3}).Return(expected, nil)
+	authRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+
+	// 執行獲取用戶權限
+	permissions, err := service.GetUserPermissions(context.Background(), "1", "")
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, expected, permissions)
+	authRepo.AssertExpectations(t)
+}
+
+func TestAssignmentService_GetUserPermissions_Inherited(t *testing.T) {
+	// 準備測試數據：admin(1) 繼承 operator(2)，operator 繼承 cs(3)
+	service, authRepo, _, _ := newTestAssignmentService()
+
+	expected := []domain.Permission{
+		{ID: 1, Resource: "user", Action: "view"},
+		{ID: 2, Resource: "notice", Action: "view"},
+	}
+
+	// 設定模擬行為：權限查詢涵蓋所有祖先角色
+	authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+	authRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 1}}, nil)
+	authRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{
+		{RoleID: 1, ParentID: 2},
+		{RoleID: 2, ParentID: 3},
+	}, nil)
+	authRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{1, 2, 3}).Return(expected, nil)
+	authRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+
+	// 執行獲取用戶權限
+	permissions, err := service.GetUserPermissions(context.Background(), "1", "")
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, expected, permissions)
+	authRepo.AssertExpectations(t)
+}
+
+func TestAssignmentService_AssignPermissionToRole_PermissionNotFound(t *testing.T) {
+	// 準備測試數據
+	service, _, roleRepo, permissionRepo := newTestAssignmentService()
+
+	// 設定模擬行為
+	roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2}, nil)
+	permissionRepo.On("GetPermissionByID", mock.Anything, int64(7)).Return(nil, domain.ErrPermissionNotFound)
+
+	// 執行分配權限
+	err := service.AssignPermissionToRole(context.Background(), "2", "7", "", "")
+
+	// 斷言
+	assert.Equal(t, domain.ErrPermissionNotFound, err)
+	roleRepo.AssertNotCalled(t, "AssignPermissionToRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAssignmentService_AssignPermissionToRole_Effect(t *testing.T) {
+	// 準備測試數據
+	service, _, roleRepo, permissionRepo := newTestAssignmentService()
+
+	// 設定模擬行為
+	roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2}, nil)
+	permissionRepo.On("GetPermissionByID", mock.Anything, int64(7)).Return(&domain.Permission{ID: 7}, nil)
+	roleRepo.On("AssignPermissionToRole", mock.Anything, int64(2), int64(7), domain.EffectDeny, "").Return(nil)
+	roleRepo.On("AssignPermissionToRole", mock.Anything, int64(2), int64(8), domain.EffectAllow, "").Return(nil)
+	permissionRepo.On("GetPermissionByID", mock.Anything, int64(8)).Return(&domain.Permission{ID: 8}, nil)
+
+	// 執行分配權限：效果不分大小寫，省略時為 allow
+	assert.NoError(t, service.AssignPermissionToRole(context.Background(), "2", "7", "Deny", ""))
+	assert.NoError(t, service.AssignPermissionToRole(context.Background(), "2", "8", "", ""))
+	assert.Equal(t, domain.ErrInvalidEffect, service.AssignPermissionToRole(context.Background(), "2", "7", "block", ""))
+	roleRepo.AssertExpectations(t)
+}
+
+func TestAssignmentService_AssignPermissionToRole_Condition(t *testing.T) {
+	// 準備測試數據
+	service, _, roleRepo, permissionRepo := newTestAssignmentService()
+
+	// 設定模擬行為：條件去除前後空白後儲存
+	roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2}, nil)
+	permissionRepo.On("GetPermissionByID", mock.Anything, int64(7)).Return(&domain.Permission{ID: 7}, nil)
+	roleRepo.On("AssignPermissionToRole", mock.Anything, int64(2), int64(7), domain.EffectAllow, "subject.region == request.region").Return(nil)
+
+	assert.NoError(t, service.AssignPermissionToRole(context.Background(), "2", "7", "", "  subject.region == request.region "))
+
+	// 無效的條件在查詢角色與權限前即拒絕，錯誤訊息包含語法錯誤的細節
+	for _, expression := range []string{"subject.region", "region == 'tw'", "env.timezone == 'UTC'", "subject.region == 'tw' ||"} {
+		err := service.AssignPermissionToRole(context.Background(), "2", "7", "", expression)
+		assert.ErrorIs(t, err, domain.ErrInvalidCondition, expression)
+	}
+	roleRepo.AssertNumberOfCalls(t, "GetRoleByID", 1)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestAssignmentService_AssignPermissionToUser_Successful(t *testing.T) {
+	// 準備測試數據
+	service, authRepo, roleRepo, permissionRepo := newTestAssignmentService()
+
+	// 設定模擬行為
+	authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+	permissionRepo.On("GetPermissionByID", mock.Anything, int64(7)).Return(&domain.Permission{ID: 7}, nil)
+	roleRepo.On("AssignPermissionToUser", mock.Anything, &domain.UserPermission{UserID: 1, PermissionID: 7, Effect: domain.EffectDeny}).Return(nil)
+
+	// 執行直接分配權限
+	err := service.AssignPermissionToUser(context.Background(), "1", "7", "", domain.EffectDeny)
+
+	// 斷言
+	assert.NoError(t, err)
+	roleRepo.AssertExpectations(t)
+	authRepo.AssertNotCalled(t, "IsTenantMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAssignmentService_AssignPermissionToUser_Tenant(t *testing.T) {
+	tests := []struct {
+		name   string
+		member bool
+		err    error
+	}{
+		{"租戶成員可在租戶內被直接分配權限", true, nil},
+		{"非租戶成員無法在租戶內被直接分配權限", false, domain.ErrNotTenantMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, authRepo, roleRepo, permissionRepo := newTestAssignmentService()
+
+			authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+			permissionRepo.On("GetPermissionByID", mock.Anything, int64(7)).Return(&domain.Permission{ID: 7}, nil)
+			authRepo.On("IsTenantMember", mock.Anything, int64(5), int64(1)).Return(tt.member, nil)
+			roleRepo.On("AssignPermissionToUser", mock.Anything, &domain.UserPermission{UserID: 1, PermissionID: 7, TenantID: 5, Effect: domain.EffectAllow}).Return(nil)
+
+			err := service.AssignPermissionToUser(context.Background(), "1", "7", "5", "")
+
+			assert.ErrorIs(t, err, tt.err)
+			if tt.err != nil {
+				roleRepo.AssertNotCalled(t, "AssignPermissionToUser", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAssignmentService_RemovePermissionFromUser_Tenant(t *testing.T) {
+	service, authRepo, roleRepo, permissionRepo := newTestAssignmentService()
+
+	authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1}, nil)
+	permissionRepo.On("GetPermissionByID", mock.Anything, int64(7)).Return(&domain.Permission{ID: 7}, nil)
+	roleRepo.On("RemovePermissionFromUser", mock.Anything, int64(1), int64(7), int64(5)).Return(nil)
+
+	err := service.RemovePermissionFromUser(context.Background(), "1", "7", "5")
+
+	assert.NoError(t, err)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestAssignmentService_RemovePermissionFromRole_Successful(t *testing.T) {
+	// 準備測試數據
+	service, _, roleRepo, permissionRepo := newTestAssignmentService()
+
+	// 設定模擬行為
+	roleRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2}, nil)
+	permissionRepo.On("GetPermissionByID", mock.Anything, int64(7)).Return(&domain.Permission{ID: 7}, nil)
+	roleRepo.On("RemovePermissionFromRole", mock.Anything, int64(2), int64(7)).Return(nil)
+
+	// 執行移除權限
+	err := service.RemovePermissionFromRole(context.Background(), "2", "7")
+
+	// 斷言
+	assert.NoError(t, err)
+	roleRepo.AssertExpectations(t)
+	permissionRepo.AssertExpectations(t)
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+
+	"rbac-service/domain"
+)
+
+const (
+	// defaultAuditPageSize 審計日誌預設每頁筆數
+	defaultAuditPageSize = 50
+	// maxAuditPageSize 審計日誌每頁筆數上限
+	maxAuditPageSize = 200
+)
+
+// AuditService 審計日誌服務實作
+type AuditService struct {
+	repo domain.AuditLogRepository
+}
+
+// NewAuditService 創建審計日誌服務
+func NewAuditService(repo domain.AuditLogRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record 寫入一筆審計日誌，details 會序列化為 JSON。
+// 寫入失敗只輸出 log，不影響原本的操作；未注入服務時（nil）直接略過。
+func (s *AuditService) Record(ctx context.Context, entry *domain.AuditLog, details map[string]interface{}) {
+	if s == nil {
+		return
+	}
+
+	if len(details) > 0 {
+		data, err := json.Marshal(details)
+		if err != nil {
+			log.Printf("審計日誌 details 序列化失敗: %v", err)
+		} else {
+			entry.Details = data
+		}
+	}
+
+	if err := s.repo.CreateAuditLog(ctx, entry); err != nil {
+		log.Printf("寫入審計日誌失敗 (%s %s/%s): %v", entry.Operation, entry.EntityType, entry.EntityID, err)
+	}
+}
+
+// ListAuditLogs 依條件查詢審計日誌，返回下一頁的游標
+func (s *AuditService) ListAuditLogs(ctx context.Context, filter domain.AuditLogFilter) (*domain.AuditLogPage, error) {
+	if filter.Limit < 0 || filter.Cursor < 0 {
+		return nil, domain.ErrInvalidAuditFilter
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, domain.ErrInvalidAuditFilter
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditPageSize
+	}
+	if filter.Limit > maxAuditPageSize {
+		filter.Limit = maxAuditPageSize
+	}
+	pageSize := filter.Limit
+
+	// 多取一筆判斷是否還有下一頁
+	filter.Limit = pageSize + 1
+	logs, err := s.repo.ListAuditLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.AuditLogPage{Items: logs}
+	if len(logs) > pageSize {
+		page.Items = logs[:pageSize]
+		page.NextCursor = strconv.FormatInt(page.Items[pageSize-1].ID, 10)
+	}
+	return page, nil
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"rbac-service/domain"
+)
+
+// MockAuditLogRepository 模擬 AuditLogRepository
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditLogRepository) CreateAuditLog(ctx context.Context, log *domain.AuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockAuditLogRepository) ListAuditLogs(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLog, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditLog), args.Error(1)
+}
+
+func TestAuditService_Record(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuditLogRepository)
+	auditService := NewAuditService(mockRepo)
+	entry := &domain.AuditLog{
+		Operation:       domain.AuditRoleCreate,
+		EntityType:      domain.AuditEntityRole,
+		EntityID:        "3",
+		Operator:        "admin",
+		OperationResult: domain.AuditResultSuccess,
+	}
+
+	// 設定模擬行為
+	mockRepo.On("CreateAuditLog", mock.Anything, entry).Return(nil)
+
+	// 執行寫入
+	auditService.Record(context.Background(), entry, map[string]interface{}{"name": "cs"})
+
+	// 斷言
+	assert.JSONEq(t, `{"name":"cs"}`, string(entry.Details))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_Record_IgnoresRepositoryError(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuditLogRepository)
+	auditService := NewAuditService(mockRepo)
+	entry := &domain.AuditLog{Operation: domain.AuditAuthLogin, Operator: "john"}
+
+	// 設定模擬行為
+	mockRepo.On("CreateAuditLog", mock.Anything, entry).Return(errors.New("db down"))
+
+	// 斷言：寫入失敗不應 panic，也不帶 details
+	assert.NotPanics(t, func() {
+		auditService.Record(context.Background(), entry, nil)
+	})
+	assert.Nil(t, entry.Details)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_Record_NilService(t *testing.T) {
+	var auditService *AuditService
+
+	assert.NotPanics(t, func() {
+		auditService.Record(context.Background(), &domain.AuditLog{}, nil)
+	})
+}
+
+func TestAuditService_ListAuditLogs_NextCursor(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuditLogRepository)
+	auditService := NewAuditService(mockRepo)
+	logs := []domain.AuditLog{{ID: 9}, {ID: 8}, {ID: 7}}
+
+	// 設定模擬行為：每頁兩筆，倉儲多取一筆
+	mockRepo.On("ListAuditLogs", mock.Anything, domain.AuditLogFilter{Operator: "admin", Limit: 3}).Return(logs, nil)
+
+	// 執行查詢
+	page, err := auditService.ListAuditLogs(context.Background(), domain.AuditLogFilter{Operator: "admin", Limit: 2})
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.AuditLog{{ID: 9}, {ID: 8}}, page.Items)
+	assert.Equal(t, "8", page.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_ListAuditLogs_LastPage(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuditLogRepository)
+	auditService := NewAuditService(mockRepo)
+
+	// 設定模擬行為：未指定筆數時使用預設值
+	mockRepo.On("ListAuditLogs", mock.Anything, domain.AuditLogFilter{Cursor: 8, Limit: defaultAuditPageSize + 1}).
+		Return([]domain.AuditLog{{ID: 7}}, nil)
+
+	// 執行查詢
+	page, err := auditService.ListAuditLogs(context.Background(), domain.AuditLogFilter{Cursor: 8})
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_ListAuditLogs_ClampsLimit(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuditLogRepository)
+	auditService := NewAuditService(mockRepo)
+
+	// 設定模擬行為
+	mockRepo.On("ListAuditLogs", mock.Anything, domain.AuditLogFilter{Limit: maxAuditPageSize + 1}).
+		Return([]domain.AuditLog{}, nil)
+
+	// 執行查詢
+	_, err := auditService.ListAuditLogs(context.Background(), domain.AuditLogFilter{Limit: 1000})
+
+	// 斷言
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_ListAuditLogs_InvalidFilter(t *testing.T) {
+	mockRepo := new(MockAuditLogRepository)
+	auditService := NewAuditService(mockRepo)
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	filters := []domain.AuditLogFilter{
+		{Limit: -1},
+		{Cursor: -1},
+		{From: &now, To: &earlier},
+		{From: &now, To: &now},
+	}
+	for _, filter := range filters {
+		_, err := auditService.ListAuditLogs(context.Background(), filter)
+		assert.ErrorIs(t, err, domain.ErrInvalidAuditFilter)
+	}
+	mockRepo.AssertNotCalled(t, "ListAuditLogs", mock.Anything, mock.Anything)
+}
@@ -4,75 +4,655 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
 	"rbac-service/domain"
+	"rbac-service/domain/condition"
+	"rbac-service/domain/matcher"
 	"rbac-service/infrastructure/utils"
 )
 
+const (
+	// refreshThreshold 訪問令牌剩餘有效時間低於此值時提示客戶端刷新
+	refreshThreshold = 15 * time.Minute
+	// maxBatchRevokeSize 單次批量撤銷的項目上限
+	maxBatchRevokeSize = 100
+	// revokeOthersResource、revokeOthersAction 撤銷其他用戶令牌所需的權限
+	revokeOthersResource = "token"
+	revokeOthersAction   = "revoke"
+	// maxBatchAuthorizeSize 單次批量授權的檢查項目上限
+	maxBatchAuthorizeSize = 100
+	// sessionTouchInterval 會話最後活動時間的更新間隔，避免每個請求都寫入資料庫
+	sessionTouchInterval = time.Minute
+	// maxUserAgentLength 會話記錄的 User-Agent 長度上限
+	maxUserAgentLength = 255
+)
+
 // AuthService 授權服務實作
 type AuthService struct {
-	authRepo domain.AuthRepository
+	authRepo         domain.AuthRepository
+	refreshTokenRepo domain.RefreshTokenRepository
+	revocationRepo   domain.TokenRevocationRepository
+	sessionRepo      domain.SessionRepository
+	// maxSessions 每位用戶的會話上限，0 表示不限制
+	maxSessions int
+	// validationMode 訪問令牌的驗證模式，strict 或 stateless
+	validationMode string
+	// revocations stateless 模式使用的記憶體撤銷清單
+	revocations *revocationList
+	// revocationBus 通知其他實例新增的撤銷記錄，nil 表示只靠其他實例定期查詢資料庫
+	revocationBus domain.RevocationBus
 }
 
 // NewAuthService 創建新的 AuthService
-func NewAuthService(authRepo domain.AuthRepository) *AuthService {
+func NewAuthService(
+	authRepo domain.AuthRepository,
+	refreshTokenRepo domain.RefreshTokenRepository,
+	revocationRepo domain.TokenRevocationRepository,
+	sessionRepo domain.SessionRepository,
+	maxSessions int,
+	validationMode string,
+	revocationBus domain.RevocationBus,
+) *AuthService {
 	return &AuthService{
-		authRepo: authRepo,
+		authRepo:         authRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		revocationRepo:   revocationRepo,
+		sessionRepo:      sessionRepo,
+		maxSessions:      maxSessions,
+		validationMode:   validationMode,
+		revocations:      newRevocationList(),
+		revocationBus:    revocationBus,
 	}
 }
 
-// Login 處理使用者登入邏輯
-func (s *AuthService) Login(username, password string) (string, error) {
+// Login 處理使用者登入邏輯，建立新的會話並返回訪問令牌與刷新令牌。
+// tenant 為租戶代碼，不為空時用戶須為其成員，令牌綁定該租戶並帶有全域與租戶內的角色
+func (s *AuthService) Login(ctx context.Context, username, password, tenant string, client domain.SessionClient) (*domain.LoginResponse, error) {
 	// 查詢使用者
-	user, err := s.authRepo.GetByUsername(context.Background(), username)
+	user, err := s.authRepo.GetByUsername(ctx, username)
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
 	}
 
-	// 加密密碼並輸出 debug 資訊
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	// 驗證密碼
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return "", err
+		return nil, errors.New("invalid credentials")
+	}
+	if user.Status == domain.UserStatusDisabled {
+		return nil, domain.ErrUserDisabled
 	}
-	// Debug 輸出
+
+	var scope *domain.Tenant
+	if tenant != "" {
+		if scope, err = s.tenantScope(ctx, user.ID, tenant); err != nil {
+			return nil, err
+		}
+	}
+
+	session, err := s.startSession(ctx, user.ID, tenant, client)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, session.ID, scope)
+}
+
+// Refresh 以刷新令牌換取新的訪問令牌，並輪替刷新令牌。
+// 已輪替過的令牌再次被使用時視為外洩，整個 family 一併撤銷
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*domain.LoginResponse, error) {
+	if refreshToken == "" {
+		return nil, domain.ErrInvalidRefreshToken
 	}
 
-	////// 測試用 print log，屆時要移除
-	fmt.Println("輸入密碼加密結果:", string(hashedPassword))
-	fmt.Println("資料庫密碼:", user.Password)
+	stored, err := s.refreshTokenRepo.GetRefreshTokenByHash(ctx, utils.HashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
 
-	// 驗證密碼
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	if stored.RevokedAt != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if stored.RotatedAt != nil {
+		return nil, s.revokeFamily(ctx, stored.FamilyID)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, domain.ErrRefreshTokenExpired
+	}
+
+	// 會話已被終止時，刷新令牌一併失效
+	session, err := s.sessionRepo.GetSessionByID(ctx, stored.FamilyID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	// 條件更新失敗代表同一令牌已被並發請求搶先使用
+	rotated, err := s.refreshTokenRepo.RotateRefreshToken(ctx, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, s.revokeFamily(ctx, stored.FamilyID)
+	}
+
+	user, err := s.authRepo.GetByID(ctx, strconv.FormatInt(stored.UserID, 10))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if user.Status == domain.UserStatusDisabled {
+		return nil, domain.ErrUserDisabled
+	}
+
+	// 會話綁定租戶時重新確認成員資格，已被移出租戶的用戶無法再取得該租戶的令牌
+	var scope *domain.Tenant
+	if session.Tenant != "" {
+		if scope, err = s.tenantScope(ctx, user.ID, session.Tenant); err != nil {
+			return nil, err
+		}
+	}
+
+	// 刷新即視為會話仍在使用，延長會話期限
+	now := time.Now()
+	if err := s.sessionRepo.TouchSession(ctx, stored.FamilyID, now, now.Add(utils.RefreshTokenTTL)); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID, scope)
+}
+
+// TokenStatus 返回訪問令牌的剩餘秒數，以及是否已接近過期需要刷新
+func (s *AuthService) TokenStatus(token string) (expiresIn int64, needsRefresh bool) {
+	claims, err := utils.ParseJWTToken(token)
+	if err != nil {
+		return 0, true
+	}
+	remaining := time.Until(claims.ExpiresAt.Time)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return int64(remaining.Seconds()), remaining < refreshThreshold
+}
+
+// revokeFamily 偵測到刷新令牌重複使用時終止所屬會話並撤銷整個 family
+func (s *AuthService) revokeFamily(ctx context.Context, familyID string) error {
+	if err := s.sessionRepo.DeleteSession(ctx, familyID); err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+		return err
+	}
+	if err := s.revokeSessions(ctx, []string{familyID}); err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return domain.ErrRefreshTokenReused
+}
+
+// startSession 建立新的會話，已達上限時先終止最久未活動的會話
+func (s *AuthService) startSession(ctx context.Context, userID int64, tenant string, client domain.SessionClient) (*domain.Session, error) {
+	if s.maxSessions > 0 {
+		sessions, err := s.sessionRepo.ListUserSessions(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for i := s.maxSessions - 1; i < len(sessions); i++ {
+			if err := s.terminateSession(ctx, sessions[i].ID); err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+				return nil, err
+			}
+		}
+	}
+
+	sessionID, err := utils.GenerateSessionID()
+	if err != nil {
+		return nil, errors.New("token generation failed")
+	}
+
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	session := &domain.Session{
+		ID:         sessionID,
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IP:         client.IP,
+		Tenant:     tenant,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(utils.RefreshTokenTTL),
+	}
+	if err := s.sessionRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// issueTokens 簽發會話的訪問令牌，並在同一 family 下產生新的刷新令牌；tenant 為 nil 時令牌不綁定租戶
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, sessionID string, tenant *domain.Tenant) (*domain.LoginResponse, error) {
+	// 查詢使用者在租戶內生效的角色，寫入 token
+	roles, err := s.authRepo.GetRolesByUserID(ctx, user.ID, tenant.ScopeID())
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return nil, err
+	}
+
+	tenantCode := ""
+	if tenant != nil {
+		tenantCode = tenant.Code
 	}
 
 	// 產生 JWT token
-	tokenString, err := utils.GenerateJWTToken(username, user.Roles)
+	tokenString, err := utils.GenerateTenantJWTToken(user.ID, user.Username, sessionID, tenantCode, domain.RoleNames(roles))
 	if err != nil {
-		return "", errors.New("token generation failed")
+		return nil, errors.New("token generation failed")
 	}
 
-	// 使用 username 去更新剛剛建立的 jwt token
-	needToupdate := map[string]interface{}{
-		"Jwt": tokenString,
+	refreshToken, refreshTokenHash, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, errors.New("token generation failed")
 	}
-	err = s.authRepo.UpdateUser(context.Background(), username, needToupdate)
+	err = s.refreshTokenRepo.CreateRefreshToken(ctx, &domain.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: refreshTokenHash,
+		ExpiresAt: time.Now().Add(utils.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginResponse{
+		User:         user.Username,
+		Token:        tokenString,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// Logout 處理使用者登出邏輯，終止令牌所屬的會話並將其 jti 加入撤銷清單
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	claims, err := utils.ParseJWTToken(token)
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return domain.ErrInvalidJwt
+	}
+
+	if err := s.terminateSession(ctx, claims.SessionID); err != nil {
+		return err
 	}
 
-	return tokenString, nil
+	_, err = s.revokeClaims(ctx, claims)
+	return err
+}
+
+// CheckSession 確認令牌所屬的會話仍有效，並定期更新最後活動時間
+func (s *AuthService) CheckSession(ctx context.Context, claims *utils.Claims) (*domain.Session, error) {
+	if claims.SessionID == "" {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	session, err := s.sessionRepo.GetSessionByID(ctx, claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if now := time.Now(); now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		if err := s.sessionRepo.TouchSession(ctx, session.ID, now, session.ExpiresAt); err != nil {
+			return nil, err
+		}
+		session.LastSeenAt = now
+	}
+
+	return session, nil
+}
+
+// ListSessions 列出目前會話所屬用戶的所有會話，並標記目前的會話
+func (s *AuthService) ListSessions(ctx context.Context, currentSessionID string) ([]domain.Session, error) {
+	current, err := s.sessionRepo.GetSessionByID(ctx, currentSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepo.ListUserSessions(ctx, current.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == current.ID
+	}
+	return sessions, nil
+}
+
+// TerminateSession 終止目前用戶的指定會話，不屬於該用戶的會話視為不存在
+func (s *AuthService) TerminateSession(ctx context.Context, currentSessionID string, sessionID string) error {
+	current, err := s.sessionRepo.GetSessionByID(ctx, currentSessionID)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if target.UserID != current.UserID {
+		return domain.ErrSessionNotFound
+	}
+
+	return s.terminateSession(ctx, target.ID)
+}
+
+// TerminateAllSessions 終止目前用戶的所有會話，包含目前的會話
+func (s *AuthService) TerminateAllSessions(ctx context.Context, currentSessionID string) error {
+	current, err := s.sessionRepo.GetSessionByID(ctx, currentSessionID)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := s.sessionRepo.ListUserSessions(ctx, current.UserID)
+	if err != nil {
+		return err
+	}
+	if err := s.sessionRepo.DeleteUserSessions(ctx, current.UserID); err != nil {
+		return err
+	}
+	sessionIDs := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+	if err := s.revokeSessions(ctx, sessionIDs); err != nil {
+		return err
+	}
+	return s.refreshTokenRepo.RevokeUserRefreshTokens(ctx, current.UserID)
+}
+
+// PurgeExpiredSessions 清除已過期的會話
+func (s *AuthService) PurgeExpiredSessions(ctx context.Context) (int64, error) {
+	return s.sessionRepo.DeleteExpiredSessions(ctx, time.Now())
+}
+
+// terminateSession 刪除會話並撤銷其刷新令牌，會話的訪問令牌隨之無法通過驗證
+func (s *AuthService) terminateSession(ctx context.Context, sessionID string) error {
+	if err := s.sessionRepo.DeleteSession(ctx, sessionID); err != nil {
+		return err
+	}
+	if err := s.revokeSessions(ctx, []string{sessionID}); err != nil {
+		return err
+	}
+	return s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, sessionID)
+}
+
+// revokeSessions 記錄已終止的會話，stateless 驗證據此拒絕其尚未過期的訪問令牌
+func (s *AuthService) revokeSessions(ctx context.Context, sessionIDs []string) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(utils.AccessTokenTTL)
+	if err := s.revocationRepo.RevokeSessions(ctx, sessionIDs, expiresAt); err != nil {
+		return err
+	}
+	s.revocations.addSessions(sessionIDs, expiresAt)
+
+	sessions := make([]domain.RevokedSession, 0, len(sessionIDs))
+	for _, id := range sessionIDs {
+		sessions = append(sessions, domain.RevokedSession{SessionID: id, ExpiresAt: expiresAt})
+	}
+	s.publishRevocations(ctx, &domain.ActiveRevocations{Sessions: sessions})
+	return nil
+}
+
+// RevokeToken 撤銷單一訪問令牌，撤銷記錄保留至令牌過期。
+// 呼叫者 username 只能撤銷自己的令牌，撤銷其他用戶的令牌需要 token:revoke 權限
+func (s *AuthService) RevokeToken(ctx context.Context, username, token, target string) (*domain.RevokedToken, error) {
+	claims, err := utils.ParseJWTToken(target)
+	if err != nil {
+		return nil, domain.ErrInvalidJwt
+	}
+	if claims.Username != username {
+		allowed, err := s.CheckPermission(ctx, username, token, revokeOthersResource, revokeOthersAction)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, domain.ErrRevokeForbidden
+		}
+	}
+	return s.revokeClaims(ctx, claims)
+}
+
+// RevokeUserTokens 撤銷用戶目前所有的訪問令牌、刷新令牌與會話
+func (s *AuthService) RevokeUserTokens(ctx context.Context, userID string) error {
+	user, err := s.revokeTarget(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.revokeUser(ctx, user)
+}
+
+// revokeTarget 查詢要撤銷令牌的用戶
+func (s *AuthService) revokeTarget(ctx context.Context, userID string) (*domain.User, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, domain.ErrInvalidUserID
+	}
+	return s.authRepo.GetByID(ctx, userID)
+}
+
+// revokeUser 撤銷已查得用戶的所有訪問令牌、刷新令牌與會話
+func (s *AuthService) revokeUser(ctx context.Context, user *domain.User) error {
+	// JWT 的 iat 只到秒，撤銷時間點同樣取到秒
+	now := time.Now().Truncate(time.Second)
+	revocation := &domain.UserTokenRevocation{
+		Username:      user.Username,
+		RevokedBefore: now,
+		ExpiresAt:     now.Add(utils.AccessTokenTTL),
+	}
+	if err := s.revocationRepo.RevokeUserTokens(ctx, revocation); err != nil {
+		return err
+	}
+	s.revocations.addUser(revocation)
+	s.publishRevocations(ctx, &domain.ActiveRevocations{Users: []domain.UserTokenRevocation{*revocation}})
+
+	if err := s.sessionRepo.DeleteUserSessions(ctx, user.ID); err != nil {
+		return err
+	}
+	return s.refreshTokenRepo.RevokeUserRefreshTokens(ctx, user.ID)
+}
+
+// BatchRevoke 批量撤銷令牌與用戶，單一項目失敗不影響其他項目。
+// 只有撤銷其他用戶的項目才檢查 token:revoke，且整批只查詢一次；沒有權限時這些項目以 ErrRevokeForbidden 列為失敗，
+// 查詢失敗時列為內部錯誤，呼叫者 username 撤銷自己的令牌與自己不受影響
+func (s *AuthService) BatchRevoke(ctx context.Context, username, token string, tokens []string, userIDs []string) (*domain.BatchRevokeResult, error) {
+	total := len(tokens) + len(userIDs)
+	if total == 0 || total > maxBatchRevokeSize {
+		return nil, domain.ErrInvalidRevokeRequest
+	}
+
+	revokeOthers := sync.OnceValues(func() (bool, error) {
+		return s.CheckPermission(ctx, username, token, revokeOthersResource, revokeOthersAction)
+	})
+
+	result := &domain.BatchRevokeResult{
+		Revoked: []string{},
+		Failed:  []domain.BatchRevokeFailure{},
+	}
+	for i, target := range tokens {
+		revoked, err := s.batchRevokeToken(ctx, username, target, revokeOthers)
+		if err != nil {
+			result.Failed = append(result.Failed, batchRevokeFailure(fmt.Sprintf("tokens[%d]", i), err))
+			continue
+		}
+		result.Revoked = append(result.Revoked, "token:"+revoked.JTI)
+	}
+	for _, userID := range userIDs {
+		if err := s.batchRevokeUser(ctx, username, userID, revokeOthers); err != nil {
+			result.Failed = append(result.Failed, batchRevokeFailure("user:"+userID, err))
+			continue
+		}
+		result.Revoked = append(result.Revoked, "user:"+userID)
+	}
+
+	return result, nil
+}
+
+// IsTokenRevoked 檢查已解析的令牌是否被撤銷
+func (s *AuthService) IsTokenRevoked(ctx context.Context, claims *utils.Claims) (bool, error) {
+	// 沒有 iat 的令牌以零值比對，只要用戶有撤銷記錄即視為失效
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	return s.revocationRepo.IsTokenRevoked(ctx, claims.ID, claims.Username, issuedAt)
+}
+
+// ValidateClaims 確認已驗證簽章的令牌未被撤銷且會話仍有效。
+// strict 模式查詢資料庫；stateless 模式只比對記憶體撤銷清單，會話終止後最晚於下次同步時生效
+func (s *AuthService) ValidateClaims(ctx context.Context, claims *utils.Claims) error {
+	if claims.SessionID == "" {
+		return domain.ErrSessionNotFound
+	}
+
+	if s.validationMode == domain.ValidationModeStateless {
+		if s.revocations.isRevoked(claims, time.Now()) {
+			return domain.ErrTokenRevoked
+		}
+		return nil
+	}
+
+	revoked, err := s.IsTokenRevoked(ctx, claims)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return domain.ErrTokenRevoked
+	}
+	_, err = s.CheckSession(ctx, claims)
+	return err
 }
 
-// Logout 處理使用者登出邏輯
-func (s *AuthService) Logout(ctx context.Context, jwt string) error {
-	return s.authRepo.DeleteUserJwt(ctx, jwt)
+// ApplyRevocations 將其他實例傳播的撤銷記錄合併至記憶體撤銷清單
+func (s *AuthService) ApplyRevocations(revocations *domain.ActiveRevocations) {
+	s.revocations.merge(revocations, time.Now())
+}
+
+// ListenRevocations 訂閱各個撤銷傳播來源，直到 ctx 結束
+func (s *AuthService) ListenRevocations(ctx context.Context, buses ...domain.RevocationBus) error {
+	for _, bus := range buses {
+		if err := bus.Subscribe(ctx, s.ApplyRevocations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyRevocations 將其他服務已寫入資料庫的撤銷記錄併入記憶體撤銷清單並通知其他實例
+func (s *AuthService) NotifyRevocations(ctx context.Context, revocations *domain.ActiveRevocations) {
+	s.revocations.merge(revocations, time.Now())
+	s.publishRevocations(ctx, revocations)
+}
+
+// publishRevocations 通知其他實例新增的撤銷記錄。記錄已寫入資料庫，
+// 發布失敗時其他實例仍會於下次查詢資料庫時取得，因此只記錄日誌
+func (s *AuthService) publishRevocations(ctx context.Context, revocations *domain.ActiveRevocations) {
+	if s.revocationBus == nil {
+		return
+	}
+	if err := s.revocationBus.Publish(ctx, revocations); err != nil {
+		log.Printf("Failed to publish token revocations: %v", err)
+	}
+}
+
+// StatelessValidation 是否以 stateless 模式驗證訪問令牌
+func (s *AuthService) StatelessValidation() bool {
+	return s.validationMode == domain.ValidationModeStateless
+}
+
+// PurgeExpiredRevocations 清除對應令牌皆已過期的撤銷記錄
+func (s *AuthService) PurgeExpiredRevocations(ctx context.Context) (int64, error) {
+	return s.revocationRepo.DeleteExpiredRevocations(ctx, time.Now())
+}
+
+// revokeClaims 以 jti 記錄撤銷，保留至令牌過期
+func (s *AuthService) revokeClaims(ctx context.Context, claims *utils.Claims) (*domain.RevokedToken, error) {
+	revoked := &domain.RevokedToken{
+		JTI:       claims.ID,
+		Username:  claims.Username,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if err := s.revocationRepo.RevokeToken(ctx, revoked); err != nil {
+		return nil, err
+	}
+	s.revocations.addToken(revoked)
+	s.publishRevocations(ctx, &domain.ActiveRevocations{Tokens: []domain.RevokedToken{*revoked}})
+
+	return revoked, nil
+}
+
+// batchRevokeToken 撤銷批量請求中的單一令牌，其他用戶的令牌需 revokeOthers 查得 token:revoke
+func (s *AuthService) batchRevokeToken(ctx context.Context, username, target string, revokeOthers func() (bool, error)) (*domain.RevokedToken, error) {
+	claims, err := utils.ParseJWTToken(target)
+	if err != nil {
+		return nil, domain.ErrInvalidJwt
+	}
+	if claims.Username != username {
+		if err := requireRevokeOthers(revokeOthers); err != nil {
+			return nil, err
+		}
+	}
+	return s.revokeClaims(ctx, claims)
+}
+
+// batchRevokeUser 撤銷批量請求中的單一用戶，呼叫者以外的用戶需 revokeOthers 查得 token:revoke
+func (s *AuthService) batchRevokeUser(ctx context.Context, username, userID string, revokeOthers func() (bool, error)) error {
+	user, err := s.revokeTarget(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Username != username {
+		if err := requireRevokeOthers(revokeOthers); err != nil {
+			return err
+		}
+	}
+	return s.revokeUser(ctx, user)
+}
+
+// requireRevokeOthers 確認呼叫者擁有 token:revoke，沒有時返回 ErrRevokeForbidden
+func requireRevokeOthers(revokeOthers func() (bool, error)) error {
+	allowed, err := revokeOthers()
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.ErrRevokeForbidden
+	}
+	return nil
+}
+
+// batchRevokeFailure 轉換失敗原因，非領域錯誤不對外暴露細節
+func batchRevokeFailure(target string, err error) domain.BatchRevokeFailure {
+	message := domain.ErrInternalServerError.Error()
+	if errors.Is(err, domain.ErrInvalidJwt) ||
+		errors.Is(err, domain.ErrUserNotFound) ||
+		errors.Is(err, domain.ErrInvalidUserID) ||
+		errors.Is(err, domain.ErrRevokeForbidden) {
+		message = err.Error()
+	}
+	return domain.BatchRevokeFailure{Target: target, Error: message}
 }
 
 // GetUser 獲取用戶信息
@@ -92,32 +672,394 @@ func (s *AuthService) GetUser(ctx context.Context, username string) (*domain.Use
 	return user, nil
 }
 
-// CheckPermission 檢查用戶是否有權限訪問特定資源
+// ResolveTenant 決定請求所屬的租戶：令牌綁定租戶時以其為準，requested 只能省略或與其相同；
+// 令牌未綁定時使用 requested。兩者皆為空時返回 nil，只套用全域角色
+func (s *AuthService) ResolveTenant(ctx context.Context, claims *utils.Claims, requested string) (*domain.Tenant, error) {
+	code, err := requestTenant(claims.Tenant, requested)
+	if err != nil || code == "" {
+		return nil, err
+	}
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, domain.ErrInvalidJwt
+	}
+	return s.tenantScope(ctx, userID, code)
+}
+
+// requestTenant 合併令牌綁定的租戶與請求指定的租戶，兩者不同時返回 ErrTenantMismatch
+func requestTenant(claimed, requested string) (string, error) {
+	if requested == "" || requested == claimed {
+		return claimed, nil
+	}
+	if claimed != "" {
+		return "", domain.ErrTenantMismatch
+	}
+	return requested, nil
+}
+
+// tenantScope 以代碼查詢租戶並確認用戶為其成員
+func (s *AuthService) tenantScope(ctx context.Context, userID int64, code string) (*domain.Tenant, error) {
+	tenant, err := s.authRepo.GetTenantByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.authRepo.IsTenantMember(ctx, tenant.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, domain.ErrNotTenantMember
+	}
+	return tenant, nil
+}
+
+// CheckPermission 檢查用戶是否有權限訪問特定資源，供管理端點使用。
+// 管理操作跨越租戶，因此只套用全域的角色與直接權限，不受令牌綁定或請求指定的租戶影響
 func (s *AuthService) CheckPermission(ctx context.Context, userID string, token string, resource string, action string) (bool, error) {
-	// 1. 檢查輸入參數
-	if userID == "" || token == "" || resource == "" || action == "" {
+	if resource == "" || action == "" {
+		return false, domain.ErrInvalidAuthorizeRequest
+	}
+	if userID == "" || token == "" {
 		return false, errors.New("invalid input parameters")
 	}
 
-	// 2. 先解析 token
+	user, _, err := s.tokenUser(ctx, userID, token)
+	if err != nil {
+		return false, err
+	}
+	permissions, err := s.GetUserPermissions(ctx, user.ID, domain.GlobalTenantID)
+	if err != nil {
+		return false, err
+	}
+	decision := decide(permissions, domain.PermissionCheck{Resource: resource, Action: action}, conditionAttributes(user, nil, time.Now()))
+	return decision.Allowed, nil
+}
+
+// Authorize 以 deny-overrides 判定用戶能否訪問特定資源，並返回決定結果的權限規則。
+// 帶條件的權限分配只在條件成立時生效，check.Attributes 為條件中 request 命名空間的屬性；
+// check.ResourceID 不為空時另依實例授權與擁有者規則判定單一資源實例。
+// tenant 為請求指定的租戶代碼，角色依令牌綁定或請求指定的租戶解析
+func (s *AuthService) Authorize(ctx context.Context, userID string, token string, tenant string, check domain.PermissionCheck) (*domain.AuthorizeDecision, error) {
+	// 1. 檢查輸入參數
+	if check.Resource == "" || check.Action == "" {
+		return nil, domain.ErrInvalidAuthorizeRequest
+	}
+	if check.ResourceID != "" && domain.ValidateObjectID(check.ResourceID) != nil {
+		return nil, domain.ErrInvalidAuthorizeRequest
+	}
+	if userID == "" || token == "" {
+		return nil, errors.New("invalid input parameters")
+	}
+
+	// 2. 取得令牌所屬用戶的有效權限並比對資源與操作
+	user, scopeID, permissions, err := s.tokenPermissions(ctx, userID, token, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := s.decideCheck(ctx, user, scopeID, permissions, check, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+// BatchCheckPermissions 以單次權限解析判定多個資源與操作，includePermissions 時一併返回用戶完整的權限列表。
+// 只要求權限列表時 checks 可為空
+func (s *AuthService) BatchCheckPermissions(ctx context.Context, userID string, token string, tenant string, checks []domain.PermissionCheck, includePermissions bool) (*domain.BatchAuthorizeResult, error) {
+	if len(checks) > maxBatchAuthorizeSize || (len(checks) == 0 && !includePermissions) {
+		return nil, domain.ErrInvalidAuthorizeRequest
+	}
+	for _, check := range checks {
+		if check.Resource == "" || check.Action == "" {
+			return nil, domain.ErrInvalidAuthorizeRequest
+		}
+		if check.ResourceID != "" && domain.ValidateObjectID(check.ResourceID) != nil {
+			return nil, domain.ErrInvalidAuthorizeRequest
+		}
+	}
+	if userID == "" || token == "" {
+		return nil, errors.New("invalid input parameters")
+	}
+
+	user, scopeID, permissions, err := s.tokenPermissions(ctx, userID, token, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := &domain.BatchAuthorizeResult{
+		Decisions: make([]domain.AuthorizeDecision, 0, len(checks)),
+	}
+	for _, check := range checks {
+		decision, err := s.decideCheck(ctx, user, scopeID, permissions, check, now)
+		if err != nil {
+			return nil, err
+		}
+		result.Decisions = append(result.Decisions, decision)
+	}
+	if includePermissions {
+		result.Permissions = make([]string, 0, len(permissions))
+		for _, permission := range permissions {
+			// 帶條件的分配需依請求屬性判定，不列入可供客戶端快取的權限列表
+			if permission.Condition != "" {
+				result.ConditionalPermissions = append(result.ConditionalPermissions, permission.Key())
+				continue
+			}
+			if permission.Denies() {
+				result.DeniedPermissions = append(result.DeniedPermissions, permission.Key())
+				continue
+			}
+			result.Permissions = append(result.Permissions, permission.Key())
+		}
+	}
+
+	return result, nil
+}
+
+// tokenUser 確認令牌屬於該用戶且仍有效，返回用戶及令牌聲明
+func (s *AuthService) tokenUser(ctx context.Context, userID string, token string) (*domain.User, *utils.Claims, error) {
+	// 1. 先解析 token
 	claims, err := utils.ParseJWTToken(token)
 	if err != nil {
-		return false, errors.New("invalid token")
+		return nil, nil, errors.New("invalid token")
 	}
-	fmt.Println("解析後的 token:", claims)
 
-	// 3. 從資料庫取出用戶當前的 JWT
+	// 2. 確認 token 屬於該用戶，且所屬會話仍有效
+	if claims.Username != userID {
+		return nil, nil, errors.New("token has been invalidated")
+	}
+	if err := s.ValidateClaims(ctx, claims); err != nil {
+		return nil, nil, errors.New("token has been invalidated")
+	}
+
+	// 3. 從資料庫取出用戶
 	user, err := s.authRepo.GetByUsername(ctx, userID)
 	if err != nil {
-		return false, errors.New("user not found")
+		return nil, nil, errors.New("user not found")
+	}
+	return user, claims, nil
+}
+
+// tokenPermissions 確認令牌屬於該用戶且仍有效，返回用戶、請求租戶的 tenant_id 及用戶在其中的有效權限
+func (s *AuthService) tokenPermissions(ctx context.Context, userID string, token string, tenant string) (*domain.User, int64, []domain.Permission, error) {
+	user, claims, err := s.tokenUser(ctx, userID, token)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	// 決定請求所屬的租戶，用戶須為其成員
+	scope, err := s.ResolveTenant(ctx, claims, tenant)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	// 取得用戶在租戶內的有效權限
+	permissions, err := s.GetUserPermissions(ctx, user.ID, scope.ScopeID())
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return user, scope.ScopeID(), permissions, nil
+}
+
+// decideCheck 判定單一檢查。指定資源實例且沒有權限分配符合時，依實例授權與擁有者規則判定；
+// 權限分配允許該類資源時涵蓋所有實例，明確拒絕時實例授權也無法推翻
+func (s *AuthService) decideCheck(ctx context.Context, user *domain.User, tenantID int64, permissions []domain.Permission, check domain.PermissionCheck, now time.Time) (domain.AuthorizeDecision, error) {
+	decision := decide(permissions, check, conditionAttributes(user, check.Attributes, now))
+	if check.ResourceID == "" {
+		return decision, nil
+	}
+	decision.ResourceID = check.ResourceID
+	if decision.Rule != nil {
+		return decision, nil
+	}
+
+	rule, err := authorizeObject(ctx, s.authRepo, user.ID, tenantID, check)
+	if err != nil {
+		return domain.AuthorizeDecision{}, err
+	}
+	if rule != nil {
+		decision.Allowed = true
+		decision.Rule = rule
+		decision.Reason = ""
+	}
+	return decision, nil
+}
+
+// authorizeObject 依用戶在租戶內的實例授權與擁有者規則判定用戶能否對資源實例執行操作，皆不符合時返回 nil
+func authorizeObject(ctx context.Context, repo domain.AuthRepository, userID, tenantID int64, check domain.PermissionCheck) (*domain.DecidingRule, error) {
+	grants, err := repo.ListObjectGrants(ctx, userID, tenantID, check.Resource, check.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, grant := range grants {
+		if grant.Matches(check.Action) {
+			return &domain.DecidingRule{
+				Source:     domain.RuleSourceObjectGrant,
+				RuleID:     grant.ID,
+				Permission: grant.Resource + ":" + grant.Action,
+				Effect:     domain.EffectAllow,
+			}, nil
+		}
+	}
+
+	owner, err := repo.GetResourceOwner(ctx, tenantID, check.Resource, check.ResourceID)
+	if errors.Is(err, domain.ErrResourceOwnerNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if owner.OwnerID != userID {
+		return nil, nil
 	}
 
-	// 4. 比對 token
-	if user.Jwt != token {
-		return false, errors.New("token has been invalidated")
+	rules, err := repo.ListOwnerRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.Matches(check.Resource, check.Action) {
+			return &domain.DecidingRule{
+				Source:     domain.RuleSourceOwner,
+				RuleID:     rule.ID,
+				Permission: rule.Resource + ":" + rule.Action,
+				Effect:     domain.EffectAllow,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// conditionAttributes 組合條件運算式的屬性：subject 來自用戶，request 來自授權請求，env 來自 now
+func conditionAttributes(user *domain.User, requestAttributes map[string]string, now time.Time) condition.Attributes {
+	attributes := condition.EnvAttributes(now)
+	for key, value := range user.SubjectAttributes() {
+		attributes[condition.NamespaceSubject+"."+key] = value
+	}
+	for key, value := range requestAttributes {
+		attributes[condition.NamespaceRequest+"."+key] = value
+	}
+	return attributes
+}
+
+// decide 以 deny-overrides 判定權限列表是否允許指定的資源與操作，並標示決定結果的權限。
+// 帶條件的權限只在條件成立時參與判定；deny 的條件無法求值時仍視為生效，避免缺少屬性而繞過拒絕。
+// 因 allow 的條件不成立而未獲授權時，Reason 說明第一個不成立的條件
+func decide(permissions []domain.Permission, check domain.PermissionCheck, attributes condition.Attributes) domain.AuthorizeDecision {
+	applicable := make([]domain.Permission, 0, len(permissions))
+	reason := ""
+	for _, permission := range permissions {
+		if permission.Condition == "" || !permission.Matches(check.Resource, check.Action) {
+			applicable = append(applicable, permission)
+			continue
+		}
+		satisfied, err := evaluateCondition(permission.Condition, attributes)
+		if err != nil {
+			// 無法求值時忽略求值結果：deny 一律生效，allow 一律不生效
+			satisfied = permission.Denies()
+		}
+		switch {
+		case satisfied:
+			applicable = append(applicable, permission)
+		case !permission.Denies() && reason == "":
+			reason = conditionFailureReason(permission, err)
+		}
+	}
+
+	rules := make([]matcher.Rule, 0, len(applicable))
+	for _, permission := range applicable {
+		rules = append(rules, permission.Rule())
+	}
+
+	result := matcher.Decide(rules, check.Resource, check.Action)
+	decision := domain.AuthorizeDecision{Resource: check.Resource, Action: check.Action, Allowed: result.Allowed}
+	if result.Rule != nil {
+		permission := applicable[result.Index]
+		decision.Rule = &domain.DecidingRule{
+			PermissionID: permission.ID,
+			Permission:   permission.Key(),
+			Effect:       result.Rule.Effect,
+			Condition:    permission.Condition,
+		}
+	} else {
+		decision.Reason = reason
+	}
+	return decision
+}
+
+// evaluateCondition 求值權限分配的條件，儲存後才變得無效的運算式視為無法求值
+func evaluateCondition(source string, attributes condition.Attributes) (bool, error) {
+	expression, err := condition.Parse(source)
+	if err != nil {
+		return false, err
+	}
+	return expression.Evaluate(attributes)
+}
+
+// conditionFailureReason 說明權限的條件為何不成立
+func conditionFailureReason(permission domain.Permission, err error) string {
+	if err != nil {
+		return fmt.Sprintf("condition of permission %s could not be evaluated: %v", permission.Key(), err)
+	}
+	return fmt.Sprintf("condition of permission %s not satisfied: %s", permission.Key(), permission.Condition)
+}
+
+// GetUserPermissions 解析用戶在租戶內的角色與直接分配，展開為權限列表
+func (s *AuthService) GetUserPermissions(ctx context.Context, userID, tenantID int64) ([]domain.Permission, error) {
+	return resolveUserPermissions(ctx, s.authRepo, userID, tenantID)
+}
+
+// resolveUserPermissions 解析用戶在租戶內的角色及其所有祖先角色，與用戶在租戶內的直接分配權限合併。
+// 同一權限可能同時以 allow 與 deny 出現，由 deny-overrides 決定結果
+func resolveUserPermissions(ctx context.Context, repo domain.AuthRepository, userID, tenantID int64) ([]domain.Permission, error) {
+	permissions, err := resolveRolePermissions(ctx, repo, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	direct, err := repo.GetPermissionsByUserID(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 去除角色與直接分配間重複的權限、效果與條件
+	type assignment struct {
+		id        int64
+		effect    string
+		condition string
+	}
+	seen := make(map[assignment]bool, len(permissions))
+	for _, permission := range permissions {
+		seen[assignment{permission.ID, permission.Rule().Effect, permission.Condition}] = true
+	}
+	for _, permission := range direct {
+		if !seen[assignment{permission.ID, permission.Rule().Effect, permission.Condition}] {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions, nil
+}
+
+// resolveRolePermissions 解析用戶在租戶內的角色（全域加上該租戶的分配）及其所有祖先角色，展開為權限列表
+func resolveRolePermissions(ctx context.Context, repo domain.AuthRepository, userID, tenantID int64) ([]domain.Permission, error) {
+	roles, err := repo.GetRolesByUserID(ctx, userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	roleIDs := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	if len(roleIDs) == 0 {
+		return repo.GetPermissionsByRoleIDs(ctx, roleIDs)
+	}
+
+	edges, err := repo.ListRoleParents(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// 5. 進行權限檢查的邏輯
-	// TODO: 實現具體的權限檢查
-	return true, nil
+	return repo.GetPermissionsByRoleIDs(ctx, domain.NewRoleHierarchy(edges).Expand(roleIDs))
 }
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"rbac-service/domain"
+	"rbac-service/infrastructure/utils"
+)
+
+// benchRevocationRepository 以固定延遲模擬資料庫查詢的撤銷記錄倉儲，未覆寫的方法不會被呼叫
+type benchRevocationRepository struct {
+	domain.TokenRevocationRepository
+	latency time.Duration
+	queries *atomic.Int64
+}
+
+func (r *benchRevocationRepository) IsTokenRevoked(ctx context.Context, jti string, username string, issuedAt time.Time) (bool, error) {
+	// MySQL 實作依序查詢 jti 與用戶層級撤銷，各一次往返
+	r.queries.Add(2)
+	time.Sleep(2 * r.latency)
+	return false, nil
+}
+
+// benchSessionRepository 以固定延遲模擬資料庫查詢的會話倉儲
+type benchSessionRepository struct {
+	domain.SessionRepository
+	latency time.Duration
+	queries *atomic.Int64
+}
+
+func (r *benchSessionRepository) GetSessionByID(ctx context.Context, id string) (*domain.Session, error) {
+	r.queries.Add(1)
+	time.Sleep(r.latency)
+	return &domain.Session{ID: id, UserID: 1, LastSeenAt: time.Now()}, nil
+}
+
+// benchmarkValidateToken 量測中介層驗證單一請求令牌的延遲：解析簽章與效期，再確認撤銷狀態與會話
+func benchmarkValidateToken(b *testing.B, mode string) {
+	for _, latency := range []time.Duration{0, 200 * time.Microsecond} {
+		b.Run(fmt.Sprintf("db=%s", latency), func(b *testing.B) {
+			queries := &atomic.Int64{}
+			authService := NewAuthService(
+				nil,
+				nil,
+				&benchRevocationRepository{latency: latency, queries: queries},
+				&benchSessionRepository{latency: latency, queries: queries},
+				0,
+				mode,
+				nil,
+			)
+			token, err := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				claims, err := utils.ParseJWTToken(token)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := authService.ValidateClaims(context.Background(), claims); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(queries.Load())/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func BenchmarkValidateToken_Strict(b *testing.B) {
+	benchmarkValidateToken(b, domain.ValidationModeStrict)
+}
+
+func BenchmarkValidateToken_Stateless(b *testing.B) {
+	benchmarkValidateToken(b, domain.ValidationModeStateless)
+}
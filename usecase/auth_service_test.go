@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"rbac-service/domain"
 	"rbac-service/infrastructure/utils"
@@ -30,11 +32,6 @@ func (m *MockAuthRepository) UpdateUser(ctx context.Context, username string, up
 	return args.Error(0)
 }
 
-func (m *MockAuthRepository) DeleteUserJwt(ctx context.Context, jwt string) error {
-	args := m.Called(ctx, jwt)
-	return args.Error(0)
-}
-
 // 修正 CreateUser 方法的簽名
 func (m *MockAuthRepository) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
 	args := m.Called(ctx, user)
@@ -44,8 +41,8 @@ func (m *MockAuthRepository) CreateUser(ctx context.Context, user *domain.User)
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockAuthRepository) DeleteUser(ctx context.Context, username string) error {
-	args := m.Called(ctx, username)
+func (m *MockAuthRepository) DeleteUser(ctx context.Context, username string, revocation *domain.UserTokenRevocation) error {
+	args := m.Called(ctx, username, revocation)
 	return args.Error(0)
 }
 
@@ -57,38 +54,288 @@ func (m *MockAuthRepository) GetByID(ctx context.Context, id string) (*domain.Us
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockAuthRepository) GetRolesByUserID(ctx context.Context, userID, tenantID int64) ([]domain.Role, error) {
+	args := m.Called(ctx, userID, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Role), args.Error(1)
+}
+
+func (m *MockAuthRepository) NextRoleAssignmentChange(ctx context.Context, userID, tenantID int64, now time.Time) (*time.Time, error) {
+	args := m.Called(ctx, userID, tenantID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockAuthRepository) GetPermissionsByRoleIDs(ctx context.Context, roleIDs []int64) ([]domain.Permission, error) {
+	args := m.Called(ctx, roleIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Permission), args.Error(1)
+}
+
+func (m *MockAuthRepository) GetPermissionsByUserID(ctx context.Context, userID, tenantID int64) ([]domain.Permission, error) {
+	args := m.Called(ctx, userID, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Permission), args.Error(1)
+}
+
+func (m *MockAuthRepository) ListRoleParents(ctx context.Context) ([]domain.RoleParent, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.RoleParent), args.Error(1)
+}
+
+func (m *MockAuthRepository) ListObjectGrants(ctx context.Context, userID, tenantID int64, resource, objectID string) ([]domain.ObjectGrant, error) {
+	args := m.Called(ctx, userID, tenantID, resource, objectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ObjectGrant), args.Error(1)
+}
+
+func (m *MockAuthRepository) GetResourceOwner(ctx context.Context, tenantID int64, resource, objectID string) (*domain.ResourceOwner, error) {
+	args := m.Called(ctx, tenantID, resource, objectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ResourceOwner), args.Error(1)
+}
+
+func (m *MockAuthRepository) ListOwnedObjectIDs(ctx context.Context, ownerID, tenantID int64, resource string) ([]string, error) {
+	args := m.Called(ctx, ownerID, tenantID, resource)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAuthRepository) ListOwnerRules(ctx context.Context) ([]domain.OwnerRule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.OwnerRule), args.Error(1)
+}
+
+func (m *MockAuthRepository) GetTenantByCode(ctx context.Context, code string) (*domain.Tenant, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tenant), args.Error(1)
+}
+
+func (m *MockAuthRepository) IsTenantMember(ctx context.Context, tenantID, userID int64) (bool, error) {
+	args := m.Called(ctx, tenantID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockRefreshTokenRepository 模擬 RefreshTokenRepository
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RotateRefreshToken(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockTokenRevocationRepository 模擬 TokenRevocationRepository
+type MockTokenRevocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRevocationRepository) RevokeToken(ctx context.Context, token *domain.RevokedToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) RevokeUserTokens(ctx context.Context, revocation *domain.UserTokenRevocation) error {
+	args := m.Called(ctx, revocation)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) IsTokenRevoked(ctx context.Context, jti string, username string, issuedAt time.Time) (bool, error) {
+	args := m.Called(ctx, jti, username, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) RevokeSessions(ctx context.Context, sessionIDs []string, expiresAt time.Time) error {
+	args := m.Called(ctx, sessionIDs, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) ListActiveRevocations(ctx context.Context, now time.Time) (*domain.ActiveRevocations, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ActiveRevocations), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) DeleteExpiredRevocations(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockSessionRepository 模擬 SessionRepository
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetSessionByID(ctx context.Context, id string) (*domain.Session, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) ListUserSessions(ctx context.Context, userID int64) ([]domain.Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) TouchSession(ctx context.Context, id string, lastSeenAt time.Time, expiresAt time.Time) error {
+	args := m.Called(ctx, id, lastSeenAt, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteSession(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteUserSessions(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestLogin_SuccessfulLogin(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockAuthRepository)
-	authService := NewAuthService(mockRepo)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(mockRepo, mockRefreshRepo, new(MockTokenRevocationRepository), mockSessionRepo, 0, domain.ValidationModeStrict, nil)
 
 	username := "testuser"
 	rawPassword := "password123"
 	hashedPassword, _ := utils.HashPassword(rawPassword)
 
 	mockUser := &domain.User{
+		ID:       1,
 		Username: username,
 		Password: hashedPassword,
-		Roles:    []string{"user"},
 	}
 
 	// 設定模擬行為
 	mockRepo.On("GetByUsername", mock.Anything, username).Return(mockUser, nil)
-	mockRepo.On("UpdateUser", mock.Anything, username, mock.Anything).Return(nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 1, Name: "user"}}, nil)
+	var session *domain.Session
+	mockSessionRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(s *domain.Session) bool {
+		session = s
+		return s.UserID == 1 && s.ID != "" && s.UserAgent == "curl/8.0" && s.IP == "10.0.0.1"
+	})).Return(nil)
+	mockRefreshRepo.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(token *domain.RefreshToken) bool {
+		return token.UserID == 1 && token.FamilyID == session.ID && len(token.TokenHash) == 64
+	})).Return(nil)
 
 	// 執行登入
-	token, err := authService.Login(username, rawPassword)
+	resp, err := authService.Login(context.Background(), username, rawPassword, "", domain.SessionClient{UserAgent: "curl/8.0", IP: "10.0.0.1"})
 
 	// 斷言
 	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
+	assert.Equal(t, int64(utils.AccessTokenTTL.Seconds()), resp.ExpiresIn)
+	claims, _ := utils.ParseJWTToken(resp.Token)
+	assert.Equal(t, session.ID, claims.SessionID)
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+	mockSessionRepo.AssertNotCalled(t, "ListUserSessions", mock.Anything, mock.Anything)
+}
+
+func TestLogin_EvictsOldestSessions(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 2, domain.ValidationModeStrict, nil)
+
+	hashedPassword, _ := utils.HashPassword("password123")
+	mockUser := &domain.User{ID: 1, Username: "testuser", Password: hashedPassword}
+
+	// 設定模擬行為：已有兩個會話，依最後活動時間由新到舊排序
+	mockRepo.On("GetByUsername", mock.Anything, "testuser").Return(mockUser, nil)
+	mockSessionRepo.On("ListUserSessions", mock.Anything, int64(1)).Return([]domain.Session{{ID: "newer"}, {ID: "older"}}, nil)
+	mockSessionRepo.On("DeleteSession", mock.Anything, "older").Return(nil)
+	mockRevocationRepo.On("RevokeSessions", mock.Anything, []string{"older"}, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRefreshRepo.On("RevokeRefreshTokenFamily", mock.Anything, "older").Return(nil)
+	mockSessionRepo.On("CreateSession", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{}, nil)
+	mockRefreshRepo.On("CreateRefreshToken", mock.Anything, mock.Anything).Return(nil)
+
+	// 執行登入
+	_, err := authService.Login(context.Background(), "testuser", "password123", "", domain.SessionClient{})
+
+	// 斷言：只終止最舊的會話
+	assert.NoError(t, err)
+	mockSessionRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockSessionRepo.AssertNotCalled(t, "DeleteSession", mock.Anything, "newer")
 }
 
 func TestLogin_InvalidUsername(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockAuthRepository)
-	authService := NewAuthService(mockRepo)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), new(MockSessionRepository), 0, domain.ValidationModeStrict, nil)
 
 	username := "nonexistentuser"
 
@@ -96,11 +343,11 @@ func TestLogin_InvalidUsername(t *testing.T) {
 	mockRepo.On("GetByUsername", mock.Anything, username).Return(nil, errors.New("user not found"))
 
 	// 執行登入
-	token, err := authService.Login(username, "anypassword")
+	resp, err := authService.Login(context.Background(), username, "anypassword", "", domain.SessionClient{})
 
 	// 斷言
 	assert.Error(t, err)
-	assert.Empty(t, token)
+	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "invalid credentials")
 	mockRepo.AssertExpectations(t)
 }
@@ -108,7 +355,7 @@ func TestLogin_InvalidUsername(t *testing.T) {
 func TestLogin_WrongPassword(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockAuthRepository)
-	authService := NewAuthService(mockRepo)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), new(MockSessionRepository), 0, domain.ValidationModeStrict, nil)
 
 	username := "testuser"
 	correctPassword := "correctpassword"
@@ -118,116 +365,1575 @@ func TestLogin_WrongPassword(t *testing.T) {
 	mockUser := &domain.User{
 		Username: username,
 		Password: hashedPassword,
-		Roles:    []string{"user"},
+		Roles:    []domain.Role{{Name: "user"}},
 	}
 
 	// 設定模擬行為
 	mockRepo.On("GetByUsername", mock.Anything, username).Return(mockUser, nil)
 
 	// 執行登入
-	token, err := authService.Login(username, wrongPassword)
+	resp, err := authService.Login(context.Background(), username, wrongPassword, "", domain.SessionClient{})
 
 	// 斷言
 	assert.Error(t, err)
-	assert.Empty(t, token)
+	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "invalid credentials")
 	mockRepo.AssertExpectations(t)
 }
 
-func TestLogout_SuccessfulLogout(t *testing.T) {
+func TestLogin_DisabledUser(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockAuthRepository)
-	authService := NewAuthService(mockRepo)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), mockSessionRepo, 0, domain.ValidationModeStrict, nil)
 
-	jwt := "some-valid-jwt-token"
+	hashedPassword, _ := utils.HashPassword("password123")
+	mockUser := &domain.User{ID: 1, Username: "testuser", Password: hashedPassword, Status: domain.UserStatusDisabled}
 
-	// 設定模擬行為：預期 DeleteUserJwt 被呼叫並回傳 nil
-	mockRepo.On("DeleteUserJwt", mock.Anything, jwt).Return(nil)
+	// 設定模擬行為
+	mockRepo.On("GetByUsername", mock.Anything, "testuser").Return(mockUser, nil)
 
-	// 執行登出
-	err := authService.Logout(context.Background(), jwt)
+	// 執行登入
+	resp, err := authService.Login(context.Background(), "testuser", "password123", "", domain.SessionClient{})
+
+	// 斷言
+	assert.ErrorIs(t, err, domain.ErrUserDisabled)
+	assert.Nil(t, resp)
+	mockSessionRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+}
+
+func TestRefresh_RotatesToken(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(mockRepo, mockRefreshRepo, new(MockTokenRevocationRepository), mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	refreshToken := "old-refresh-token"
+	stored := &domain.RefreshToken{
+		ID:        7,
+		UserID:    1,
+		FamilyID:  "family-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	// 設定模擬行為
+	mockRefreshRepo.On("GetRefreshTokenByHash", mock.Anything, utils.HashRefreshToken(refreshToken)).Return(stored, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "family-1").Return(&domain.Session{ID: "family-1", UserID: 1}, nil)
+	mockRefreshRepo.On("RotateRefreshToken", mock.Anything, int64(7)).Return(true, nil)
+	mockRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+	mockSessionRepo.On("TouchSession", mock.Anything, "family-1", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 1, Name: "user"}}, nil)
+	mockRefreshRepo.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(token *domain.RefreshToken) bool {
+		return token.UserID == 1 && token.FamilyID == "family-1"
+	})).Return(nil)
+
+	// 執行刷新
+	resp, err := authService.Refresh(context.Background(), refreshToken)
 
 	// 斷言
 	assert.NoError(t, err)
+	assert.Equal(t, "testuser", resp.User)
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEqual(t, refreshToken, resp.RefreshToken)
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestLogin_Tenant(t *testing.T) {
+	tests := []struct {
+		name   string
+		member bool
+		err    error
+	}{
+		{"租戶成員取得綁定租戶的令牌", true, nil},
+		{"非租戶成員無法以租戶登入", false, domain.ErrNotTenantMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 準備測試數據
+			mockRepo := new(MockAuthRepository)
+			mockRefreshRepo := new(MockRefreshTokenRepository)
+			mockSessionRepo := new(MockSessionRepository)
+			authService := NewAuthService(mockRepo, mockRefreshRepo, new(MockTokenRevocationRepository), mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+			hashedPassword, _ := utils.HashPassword("password123")
+			mockUser := &domain.User{ID: 1, Username: "testuser", Password: hashedPassword}
+
+			// 設定模擬行為：全域角色與租戶內的角色一併寫入令牌
+			mockRepo.On("GetByUsername", mock.Anything, "testuser").Return(mockUser, nil)
+			mockRepo.On("GetTenantByCode", mock.Anything, "game-a").Return(&domain.Tenant{ID: 5, Code: "game-a"}, nil)
+			mockRepo.On("IsTenantMember", mock.Anything, int64(5), int64(1)).Return(tt.member, nil)
+			mockSessionRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(s *domain.Session) bool {
+				return s.Tenant == "game-a"
+			})).Return(nil)
+			mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), int64(5)).Return([]domain.Role{{ID: 1, Name: "user"}, {ID: 4, Name: "gm"}}, nil)
+			mockRefreshRepo.On("CreateRefreshToken", mock.Anything, mock.Anything).Return(nil)
+
+			// 執行登入
+			resp, err := authService.Login(context.Background(), "testuser", "password123", "game-a", domain.SessionClient{})
+
+			// 斷言
+			assert.ErrorIs(t, err, tt.err)
+			if tt.err != nil {
+				mockSessionRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+				return
+			}
+			claims, err := utils.ParseJWTToken(resp.Token)
+			require.NoError(t, err)
+			assert.Equal(t, "game-a", claims.Tenant)
+			assert.Equal(t, []string{"user", "gm"}, claims.Roles)
+		})
+	}
 }
 
-func TestLogout_FailedLogout(t *testing.T) {
+func TestRefresh_RemovedTenantMember(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockAuthRepository)
-	authService := NewAuthService(mockRepo)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(mockRepo, mockRefreshRepo, new(MockTokenRevocationRepository), mockSessionRepo, 0, domain.ValidationModeStrict, nil)
 
-	jwt := "some-invalid-jwt-token"
-	expectedErr := errors.New("failed to delete jwt")
+	stored := &domain.RefreshToken{ID: 7, UserID: 1, FamilyID: "family-1", ExpiresAt: time.Now().Add(time.Hour)}
 
-	// 設定模擬行為：預期 DeleteUserJwt 被呼叫並回傳錯誤
-	mockRepo.On("DeleteUserJwt", mock.Anything, jwt).Return(expectedErr)
+	// 設定模擬行為：會話綁定的租戶已將用戶移除
+	mockRefreshRepo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(stored, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "family-1").Return(&domain.Session{ID: "family-1", UserID: 1, Tenant: "game-a"}, nil)
+	mockRefreshRepo.On("RotateRefreshToken", mock.Anything, int64(7)).Return(true, nil)
+	mockRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+	mockRepo.On("GetTenantByCode", mock.Anything, "game-a").Return(&domain.Tenant{ID: 5, Code: "game-a"}, nil)
+	mockRepo.On("IsTenantMember", mock.Anything, int64(5), int64(1)).Return(false, nil)
 
-	// 執行登出
-	err := authService.Logout(context.Background(), jwt)
+	// 執行刷新
+	_, err := authService.Refresh(context.Background(), "refresh-token")
 
 	// 斷言
-	assert.Error(t, err)
-	assert.Equal(t, expectedErr, err)
-	mockRepo.AssertExpectations(t)
+	assert.ErrorIs(t, err, domain.ErrNotTenantMember)
+	mockRefreshRepo.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything)
 }
 
-func TestGetUser_SuccessfulRetrieval(t *testing.T) {
+func TestRefresh_TerminatedSession(t *testing.T) {
+	// 準備測試數據
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(new(MockAuthRepository), mockRefreshRepo, new(MockTokenRevocationRepository), mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	stored := &domain.RefreshToken{ID: 7, UserID: 1, FamilyID: "family-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	// 設定模擬行為：會話已被終止
+	mockRefreshRepo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(stored, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "family-1").Return(nil, domain.ErrSessionNotFound)
+
+	// 執行刷新
+	_, err := authService.Refresh(context.Background(), "refresh-token")
+
+	// 斷言
+	assert.ErrorIs(t, err, domain.ErrInvalidRefreshToken)
+	mockRefreshRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything)
+}
+
+func TestRefresh_ReusedTokenRevokesFamily(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockAuthRepository)
-	authService := NewAuthService(mockRepo)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
 
-	userID := "testuser123"
-	expectedUser := &domain.User{
-		Username: userID,
-		Roles:    []string{"user"},
+	rotatedAt := time.Now().Add(-time.Minute)
+	stored := &domain.RefreshToken{
+		ID:        7,
+		UserID:    1,
+		FamilyID:  "family-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+		RotatedAt: &rotatedAt,
 	}
 
 	// 設定模擬行為
-	mockRepo.On("GetByUsername", mock.Anything, userID).Return(expectedUser, nil)
+	mockRefreshRepo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(stored, nil)
+	mockSessionRepo.On("DeleteSession", mock.Anything, "family-1").Return(nil)
+	mockRevocationRepo.On("RevokeSessions", mock.Anything, []string{"family-1"}, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRefreshRepo.On("RevokeRefreshTokenFamily", mock.Anything, "family-1").Return(nil)
 
-	// 執行獲取用戶
-	user, err := authService.GetUser(context.Background(), userID)
+	// 執行刷新
+	resp, err := authService.Refresh(context.Background(), "stolen-refresh-token")
+
+	// 斷言：所屬會話一併終止
+	assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+	assert.Nil(t, resp)
+	mockRefreshRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestRefresh_ConcurrentRotationRevokesFamily(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	stored := &domain.RefreshToken{ID: 7, UserID: 1, FamilyID: "family-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	// 設定模擬行為：查詢時尚未輪替，但條件更新已被其他請求搶先
+	mockRefreshRepo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(stored, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "family-1").Return(&domain.Session{ID: "family-1", UserID: 1}, nil)
+	mockRefreshRepo.On("RotateRefreshToken", mock.Anything, int64(7)).Return(false, nil)
+	mockSessionRepo.On("DeleteSession", mock.Anything, "family-1").Return(nil)
+	mockRevocationRepo.On("RevokeSessions", mock.Anything, []string{"family-1"}, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRefreshRepo.On("RevokeRefreshTokenFamily", mock.Anything, "family-1").Return(nil)
+
+	// 執行刷新
+	_, err := authService.Refresh(context.Background(), "refresh-token")
+
+	// 斷言
+	assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestRefresh_RejectedTokens(t *testing.T) {
+	revokedAt := time.Now()
+	tests := []struct {
+		name    string
+		stored  *domain.RefreshToken
+		repoErr error
+		wantErr error
+	}{
+		{
+			name:    "unknown",
+			repoErr: domain.ErrInvalidRefreshToken,
+			wantErr: domain.ErrInvalidRefreshToken,
+		},
+		{
+			name:    "revoked",
+			stored:  &domain.RefreshToken{ID: 1, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt},
+			wantErr: domain.ErrInvalidRefreshToken,
+		},
+		{
+			name:    "expired",
+			stored:  &domain.RefreshToken{ID: 1, ExpiresAt: time.Now().Add(-time.Second)},
+			wantErr: domain.ErrRefreshTokenExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRefreshRepo := new(MockRefreshTokenRepository)
+			authService := NewAuthService(new(MockAuthRepository), mockRefreshRepo, new(MockTokenRevocationRepository), new(MockSessionRepository), 0, domain.ValidationModeStrict, nil)
+			if tt.stored != nil {
+				mockRefreshRepo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(tt.stored, nil)
+			} else {
+				mockRefreshRepo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(nil, tt.repoErr)
+			}
+
+			_, err := authService.Refresh(context.Background(), "refresh-token")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			mockRefreshRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestLogout_SuccessfulLogout(t *testing.T) {
+	// 準備測試數據
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(new(MockAuthRepository), mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", []string{"user"})
+	claims, _ := utils.ParseJWTToken(token)
+
+	// 設定模擬行為：終止會話並撤銷令牌的 jti
+	mockSessionRepo.On("DeleteSession", mock.Anything, "session-1").Return(nil)
+	mockRevocationRepo.On("RevokeSessions", mock.Anything, []string{"session-1"}, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRefreshRepo.On("RevokeRefreshTokenFamily", mock.Anything, "session-1").Return(nil)
+	mockRevocationRepo.On("RevokeToken", mock.Anything, mock.MatchedBy(func(revoked *domain.RevokedToken) bool {
+		return revoked.JTI == claims.ID && revoked.Username == "testuser"
+	})).Return(nil)
+
+	// 執行登出
+	err := authService.Logout(context.Background(), token)
 
 	// 斷言
 	assert.NoError(t, err)
-	assert.NotNil(t, user)
-	assert.Equal(t, expectedUser, user)
-	mockRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocationRepo.AssertExpectations(t)
 }
 
-func TestGetUser_EmptyUserID(t *testing.T) {
+func TestLogout_InvalidToken(t *testing.T) {
 	// 準備測試數據
-	mockRepo := new(MockAuthRepository)
-	authService := NewAuthService(mockRepo)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), mockSessionRepo, 0, domain.ValidationModeStrict, nil)
 
-	// 執行獲取用戶
-	user, err := authService.GetUser(context.Background(), "")
+	// 執行登出
+	err := authService.Logout(context.Background(), "some-invalid-jwt-token")
 
 	// 斷言
-	assert.Error(t, err)
-	assert.Nil(t, user)
-	assert.Equal(t, domain.ErrInvalidUserID, err)
-	mockRepo.AssertExpectations(t)
+	assert.ErrorIs(t, err, domain.ErrInvalidJwt)
+	mockSessionRepo.AssertNotCalled(t, "DeleteSession", mock.Anything, mock.Anything)
 }
 
-func TestGetUser_UserNotFound(t *testing.T) {
+func TestLogout_SessionAlreadyTerminated(t *testing.T) {
 	// 準備測試數據
-	mockRepo := new(MockAuthRepository)
-	authService := NewAuthService(mockRepo)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
 
-	userID := "nonexistentuser"
-	expectedError := errors.New("user not found")
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
 
 	// 設定模擬行為
-	mockRepo.On("GetByUsername", mock.Anything, userID).Return(nil, expectedError)
+	mockSessionRepo.On("DeleteSession", mock.Anything, "session-1").Return(domain.ErrSessionNotFound)
 
-	// 執行獲取用戶
-	user, err := authService.GetUser(context.Background(), userID)
+	// 執行登出
+	err := authService.Logout(context.Background(), token)
 
 	// 斷言
-	assert.Error(t, err)
-	assert.Nil(t, user)
-	assert.Equal(t, expectedError, err)
-	mockRepo.AssertExpectations(t)
+	assert.ErrorIs(t, err, domain.ErrSessionNotFound)
+	mockRevocationRepo.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything)
+}
+
+func TestListSessions_MarksCurrent(t *testing.T) {
+	// 準備測試數據
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	// 設定模擬行為
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-2").Return(&domain.Session{ID: "session-2", UserID: 1}, nil)
+	mockSessionRepo.On("ListUserSessions", mock.Anything, int64(1)).Return([]domain.Session{{ID: "session-1"}, {ID: "session-2"}}, nil)
+
+	// 執行查詢
+	sessions, err := authService.ListSessions(context.Background(), "session-2")
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestTerminateSession_Successful(t *testing.T) {
+	// 準備測試數據
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(new(MockAuthRepository), mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	// 設定模擬行為
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1}, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-2").Return(&domain.Session{ID: "session-2", UserID: 1}, nil)
+	mockSessionRepo.On("DeleteSession", mock.Anything, "session-2").Return(nil)
+	mockRevocationRepo.On("RevokeSessions", mock.Anything, []string{"session-2"}, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRefreshRepo.On("RevokeRefreshTokenFamily", mock.Anything, "session-2").Return(nil)
+
+	// 執行終止
+	err := authService.TerminateSession(context.Background(), "session-1", "session-2")
+
+	// 斷言
+	assert.NoError(t, err)
+	mockSessionRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestTerminateSession_OtherUsersSession(t *testing.T) {
+	// 準備測試數據
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	// 設定模擬行為：目標會話屬於其他用戶
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1}, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-9").Return(&domain.Session{ID: "session-9", UserID: 2}, nil)
+
+	// 執行終止
+	err := authService.TerminateSession(context.Background(), "session-1", "session-9")
+
+	// 斷言
+	assert.ErrorIs(t, err, domain.ErrSessionNotFound)
+	mockSessionRepo.AssertNotCalled(t, "DeleteSession", mock.Anything, mock.Anything)
+}
+
+func TestTerminateAllSessions_Successful(t *testing.T) {
+	// 準備測試數據
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(new(MockAuthRepository), mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	// 設定模擬行為：所有會話一併記錄為已終止
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1}, nil)
+	mockSessionRepo.On("ListUserSessions", mock.Anything, int64(1)).Return([]domain.Session{{ID: "session-1"}, {ID: "session-2"}}, nil)
+	mockSessionRepo.On("DeleteUserSessions", mock.Anything, int64(1)).Return(nil)
+	mockRevocationRepo.On("RevokeSessions", mock.Anything, []string{"session-1", "session-2"}, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRefreshRepo.On("RevokeUserRefreshTokens", mock.Anything, int64(1)).Return(nil)
+
+	// 執行終止
+	err := authService.TerminateAllSessions(context.Background(), "session-1")
+
+	// 斷言
+	assert.NoError(t, err)
+	mockSessionRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocationRepo.AssertExpectations(t)
+}
+
+func TestRevokeToken_InvalidToken(t *testing.T) {
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), mockRevocationRepo, new(MockSessionRepository), 0, domain.ValidationModeStrict, nil)
+
+	revoked, err := authService.RevokeToken(context.Background(), "testuser", "caller-token", "not-a-jwt")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidJwt)
+	assert.Nil(t, revoked)
+	mockRevocationRepo.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything)
+}
+
+func TestRevokeToken_OtherUserRequiresPermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []domain.Permission
+		expectedErr error
+	}{
+		{
+			name:        "without token:revoke",
+			permissions: []domain.Permission{},
+			expectedErr: domain.ErrRevokeForbidden,
+		},
+		{
+			name:        "with token:revoke",
+			permissions: []domain.Permission{{ID: 1, Resource: "token", Action: "revoke"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 準備測試數據
+			mockRepo := new(MockAuthRepository)
+			mockSessionRepo := new(MockSessionRepository)
+			mockRevocationRepo := new(MockTokenRevocationRepository)
+			authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+			callerToken, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+			target, _ := utils.GenerateJWTToken(2, "jared", "session-2", nil)
+
+			// 設定模擬行為：撤銷他人令牌前以全域權限檢查 token:revoke
+			mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, "testuser", mock.Anything).Return(false, nil)
+			mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+			mockRepo.On("GetByUsername", mock.Anything, "testuser").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+			mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{}, nil)
+			mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{}).Return([]domain.Permission{}, nil)
+			mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return(tt.permissions, nil)
+			if tt.expectedErr == nil {
+				mockRevocationRepo.On("RevokeToken", mock.Anything, mock.MatchedBy(func(revoked *domain.RevokedToken) bool {
+					return revoked.Username == "jared"
+				})).Return(nil)
+			}
+
+			// 執行撤銷
+			revoked, err := authService.RevokeToken(context.Background(), "testuser", callerToken, target)
+
+			// 斷言
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, revoked)
+				mockRevocationRepo.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "jared", revoked.Username)
+			mockRevocationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeUserTokens_Successful(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(mockRepo, mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	// 設定模擬行為
+	mockRepo.On("GetByID", mock.Anything, "2").Return(&domain.User{ID: 2, Username: "jared"}, nil)
+	mockRevocationRepo.On("RevokeUserTokens", mock.Anything, mock.MatchedBy(func(revocation *domain.UserTokenRevocation) bool {
+		return revocation.Username == "jared" &&
+			revocation.ExpiresAt.Sub(revocation.RevokedBefore) == utils.AccessTokenTTL
+	})).Return(nil)
+	mockSessionRepo.On("DeleteUserSessions", mock.Anything, int64(2)).Return(nil)
+	mockRefreshRepo.On("RevokeUserRefreshTokens", mock.Anything, int64(2)).Return(nil)
+
+	// 執行撤銷
+	err := authService.RevokeUserTokens(context.Background(), "2")
+
+	// 斷言
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocationRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestBatchRevoke_PartialFailure(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(mockRepo, mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	claims, _ := utils.ParseJWTToken(token)
+
+	// 設定模擬行為：只撤銷自己的令牌，不需檢查 token:revoke
+	mockRevocationRepo.On("RevokeToken", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, "404").Return(nil, domain.ErrUserNotFound)
+
+	// 執行批量撤銷
+	result, err := authService.BatchRevoke(context.Background(), "testuser", token, []string{token, "broken"}, []string{"404"})
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"token:" + claims.ID}, result.Revoked)
+	assert.Equal(t, []domain.BatchRevokeFailure{
+		{Target: "tokens[1]", Error: domain.ErrInvalidJwt.Error()},
+		{Target: "user:404", Error: domain.ErrUserNotFound.Error()},
+	}, result.Failed)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetByUsername", mock.Anything, mock.Anything)
+	mockRevocationRepo.AssertExpectations(t)
+}
+
+func TestBatchRevoke_PermissionErrorOnlyFailsOtherUsers(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(mockRepo, mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	callerToken, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	claims, _ := utils.ParseJWTToken(callerToken)
+	target, _ := utils.GenerateJWTToken(2, "jared", "session-2", nil)
+
+	// 設定模擬行為：查詢 token:revoke 失敗
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, "testuser", mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+	mockRepo.On("GetByUsername", mock.Anything, "testuser").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return(nil, errors.New("db down"))
+	mockRevocationRepo.On("RevokeToken", mock.Anything, mock.MatchedBy(func(revoked *domain.RevokedToken) bool {
+		return revoked.Username == "testuser"
+	})).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+	mockRepo.On("GetByID", mock.Anything, "2").Return(&domain.User{ID: 2, Username: "jared"}, nil)
+	mockRevocationRepo.On("RevokeUserTokens", mock.Anything, mock.MatchedBy(func(revocation *domain.UserTokenRevocation) bool {
+		return revocation.Username == "testuser"
+	})).Return(nil)
+	mockSessionRepo.On("DeleteUserSessions", mock.Anything, int64(1)).Return(nil)
+	mockRefreshRepo.On("RevokeUserRefreshTokens", mock.Anything, int64(1)).Return(nil)
+
+	// 執行批量撤銷：自己與其他用戶的令牌、自己與其他用戶
+	result, err := authService.BatchRevoke(context.Background(), "testuser", callerToken, []string{callerToken, target}, []string{"1", "2"})
+
+	// 斷言：自己的項目照常撤銷，其他用戶的項目列為內部錯誤，權限只查詢一次
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"token:" + claims.ID, "user:1"}, result.Revoked)
+	assert.Equal(t, []domain.BatchRevokeFailure{
+		{Target: "tokens[1]", Error: domain.ErrInternalServerError.Error()},
+		{Target: "user:2", Error: domain.ErrInternalServerError.Error()},
+	}, result.Failed)
+	mockRepo.AssertNumberOfCalls(t, "GetRolesByUserID", 1)
+	mockRevocationRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestBatchRevoke_OtherUsersRequirePermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []domain.Permission
+		forbidden   bool
+	}{
+		{
+			name:        "without token:revoke",
+			permissions: []domain.Permission{},
+			forbidden:   true,
+		},
+		{
+			name:        "with token:revoke",
+			permissions: []domain.Permission{{ID: 1, Resource: "token", Action: "revoke"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 準備測試數據
+			mockRepo := new(MockAuthRepository)
+			mockRefreshRepo := new(MockRefreshTokenRepository)
+			mockSessionRepo := new(MockSessionRepository)
+			mockRevocationRepo := new(MockTokenRevocationRepository)
+			authService := NewAuthService(mockRepo, mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+			callerToken, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+			target, _ := utils.GenerateJWTToken(2, "jared", "session-2", nil)
+			claims, _ := utils.ParseJWTToken(target)
+
+			// 設定模擬行為
+			mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, "testuser", mock.Anything).Return(false, nil)
+			mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+			mockRepo.On("GetByUsername", mock.Anything, "testuser").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+			mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{}, nil)
+			mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{}).Return([]domain.Permission{}, nil)
+			mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return(tt.permissions, nil)
+			mockRepo.On("GetByID", mock.Anything, "2").Return(&domain.User{ID: 2, Username: "jared"}, nil)
+			if !tt.forbidden {
+				mockRevocationRepo.On("RevokeToken", mock.Anything, mock.Anything).Return(nil)
+				mockRevocationRepo.On("RevokeUserTokens", mock.Anything, mock.Anything).Return(nil)
+				mockSessionRepo.On("DeleteUserSessions", mock.Anything, int64(2)).Return(nil)
+				mockRefreshRepo.On("RevokeUserRefreshTokens", mock.Anything, int64(2)).Return(nil)
+			}
+
+			// 執行批量撤銷：其他用戶的令牌與其他用戶
+			result, err := authService.BatchRevoke(context.Background(), "testuser", callerToken, []string{target}, []string{"2"})
+
+			// 斷言
+			assert.NoError(t, err)
+			if tt.forbidden {
+				assert.Empty(t, result.Revoked)
+				assert.Equal(t, []domain.BatchRevokeFailure{
+					{Target: "tokens[0]", Error: domain.ErrRevokeForbidden.Error()},
+					{Target: "user:2", Error: domain.ErrRevokeForbidden.Error()},
+				}, result.Failed)
+				mockRevocationRepo.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything)
+				mockRevocationRepo.AssertNotCalled(t, "RevokeUserTokens", mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, []string{"token:" + claims.ID, "user:2"}, result.Revoked)
+			assert.Empty(t, result.Failed)
+			mockRevocationRepo.AssertExpectations(t)
+			mockRefreshRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBatchRevoke_InvalidSize(t *testing.T) {
+	authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), new(MockSessionRepository), 0, domain.ValidationModeStrict, nil)
+
+	_, err := authService.BatchRevoke(context.Background(), "testuser", "token", nil, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidRevokeRequest)
+
+	_, err = authService.BatchRevoke(context.Background(), "testuser", "token", make([]string, maxBatchRevokeSize+1), nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidRevokeRequest)
+}
+
+func TestIsTokenRevoked_UsesClaims(t *testing.T) {
+	// 準備測試數據
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), mockRevocationRepo, new(MockSessionRepository), 0, domain.ValidationModeStrict, nil)
+
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	claims, _ := utils.ParseJWTToken(token)
+
+	// 設定模擬行為
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, claims.ID, "testuser", claims.IssuedAt.Time).Return(true, nil)
+
+	// 執行檢查
+	revoked, err := authService.IsTokenRevoked(context.Background(), claims)
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	mockRevocationRepo.AssertExpectations(t)
+}
+
+func TestGetUser_SuccessfulRetrieval(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), new(MockSessionRepository), 0, domain.ValidationModeStrict, nil)
+
+	userID := "testuser123"
+	expectedUser := &domain.User{
+		Username: userID,
+		Roles:    []domain.Role{{Name: "user"}},
+	}
+
+	// 設定模擬行為
+	mockRepo.On("GetByUsername", mock.Anything, userID).Return(expectedUser, nil)
+
+	// 執行獲取用戶
+	user, err := authService.GetUser(context.Background(), userID)
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+	assert.Equal(t, expectedUser, user)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetUser_EmptyUserID(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), new(MockSessionRepository), 0, domain.ValidationModeStrict, nil)
+
+	// 執行獲取用戶
+	user, err := authService.GetUser(context.Background(), "")
+
+	// 斷言
+	assert.Error(t, err)
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidUserID, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetUser_UserNotFound(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), new(MockSessionRepository), 0, domain.ValidationModeStrict, nil)
+
+	userID := "nonexistentuser"
+	expectedError := errors.New("user not found")
+
+	// 設定模擬行為
+	mockRepo.On("GetByUsername", mock.Anything, userID).Return(nil, expectedError)
+
+	// 執行獲取用戶
+	user, err := authService.GetUser(context.Background(), userID)
+
+	// 斷言
+	assert.Error(t, err)
+	assert.Nil(t, user)
+	assert.Equal(t, expectedError, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckPermission_Granted(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	username := "testuser"
+	token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+	mockUser := &domain.User{ID: 1, Username: username}
+
+	// 設定模擬行為
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+	mockRepo.On("GetByUsername", mock.Anything, username).Return(mockUser, nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 2, Name: "operator"}}, nil)
+	mockRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+	mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{2}).Return([]domain.Permission{
+		{ID: 1, Resource: "notice", Action: "view"},
+		{ID: 2, Resource: "notice", Action: "publish"},
+	}, nil)
+	mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+
+	// 執行權限檢查
+	allowed, err := authService.CheckPermission(context.Background(), username, token, "notice", "publish")
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthorize_ExplicitDeny(t *testing.T) {
+	tests := []struct {
+		name     string
+		rolePerm []domain.Permission
+		userPerm []domain.Permission
+		resource string
+		action   string
+		want     domain.AuthorizeDecision
+	}{
+		{
+			name: "角色的 deny 優先於其他角色的 allow",
+			rolePerm: []domain.Permission{
+				{ID: 1, Resource: "stats", Action: "*", Effect: domain.EffectAllow},
+				{ID: 2, Resource: "stats", Action: "export", Effect: domain.EffectDeny},
+			},
+			resource: "stats",
+			action:   "export",
+			want: domain.AuthorizeDecision{Resource: "stats", Action: "export", Allowed: false,
+				Rule: &domain.DecidingRule{PermissionID: 2, Permission: "stats:export", Effect: domain.EffectDeny}},
+		},
+		{
+			name:     "直接分配給用戶的 deny 優先於角色的 allow",
+			rolePerm: []domain.Permission{{ID: 1, Resource: "stats", Action: "*", Effect: domain.EffectAllow}},
+			userPerm: []domain.Permission{{ID: 2, Resource: "stats", Action: "export", Effect: domain.EffectDeny}},
+			resource: "stats",
+			action:   "export",
+			want: domain.AuthorizeDecision{Resource: "stats", Action: "export", Allowed: false,
+				Rule: &domain.DecidingRule{PermissionID: 2, Permission: "stats:export", Effect: domain.EffectDeny}},
+		},
+		{
+			name:     "deny 不影響其他操作",
+			rolePerm: []domain.Permission{{ID: 1, Resource: "stats", Action: "*", Effect: domain.EffectAllow}},
+			userPerm: []domain.Permission{{ID: 2, Resource: "stats", Action: "export", Effect: domain.EffectDeny}},
+			resource: "stats",
+			action:   "view",
+			want: domain.AuthorizeDecision{Resource: "stats", Action: "view", Allowed: true,
+				Rule: &domain.DecidingRule{PermissionID: 1, Permission: "stats:*", Effect: domain.EffectAllow}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 準備測試數據
+			mockRepo := new(MockAuthRepository)
+			mockSessionRepo := new(MockSessionRepository)
+			mockRevocationRepo := new(MockTokenRevocationRepository)
+			authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+			username := "testuser"
+			token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+
+			// 設定模擬行為
+			mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+			mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+			mockRepo.On("GetByUsername", mock.Anything, username).Return(&domain.User{ID: 1, Username: username}, nil)
+			mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 2, Name: "operator"}}, nil)
+			mockRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+			mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{2}).Return(tt.rolePerm, nil)
+			mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return(append([]domain.Permission{}, tt.userPerm...), nil)
+
+			// 執行授權判定
+			decision, err := authService.Authorize(context.Background(), username, token, "", domain.PermissionCheck{Resource: tt.resource, Action: tt.action})
+
+			// 斷言
+			require.NoError(t, err)
+			assert.Equal(t, &tt.want, decision)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthorize_Conditions(t *testing.T) {
+	sameRegion := "subject.region == request.region"
+	tests := []struct {
+		name       string
+		rolePerm   []domain.Permission
+		attributes map[string]string
+		want       domain.AuthorizeDecision
+	}{
+		{
+			name:       "條件成立時生效",
+			rolePerm:   []domain.Permission{{ID: 1, Resource: "users", Action: "edit", Effect: domain.EffectAllow, Condition: sameRegion}},
+			attributes: map[string]string{"region": "tw"},
+			want: domain.AuthorizeDecision{Resource: "users", Action: "edit", Allowed: true,
+				Rule: &domain.DecidingRule{PermissionID: 1, Permission: "users:edit", Effect: domain.EffectAllow, Condition: sameRegion}},
+		},
+		{
+			name:       "條件不成立時拒絕並說明原因",
+			rolePerm:   []domain.Permission{{ID: 1, Resource: "users", Action: "edit", Effect: domain.EffectAllow, Condition: sameRegion}},
+			attributes: map[string]string{"region": "jp"},
+			want: domain.AuthorizeDecision{Resource: "users", Action: "edit", Allowed: false,
+				Reason: "condition of permission users:edit not satisfied: " + sameRegion},
+		},
+		{
+			name:     "缺少請求屬性時 allow 不生效",
+			rolePerm: []domain.Permission{{ID: 1, Resource: "users", Action: "edit", Effect: domain.EffectAllow, Condition: sameRegion}},
+			want: domain.AuthorizeDecision{Resource: "users", Action: "edit", Allowed: false,
+				Reason: "condition of permission users:edit could not be evaluated: missing condition attribute: request.region"},
+		},
+		{
+			name:     "缺少請求屬性時否定的條件不成立",
+			rolePerm: []domain.Permission{{ID: 1, Resource: "users", Action: "edit", Effect: domain.EffectAllow, Condition: "!(request.region != subject.region)"}},
+			want: domain.AuthorizeDecision{Resource: "users", Action: "edit", Allowed: false,
+				Reason: "condition of permission users:edit could not be evaluated: missing condition attribute: request.region"},
+		},
+		{
+			name: "條件不成立的 allow 不影響其他無條件的 allow",
+			rolePerm: []domain.Permission{
+				{ID: 1, Resource: "users", Action: "edit", Effect: domain.EffectAllow, Condition: sameRegion},
+				{ID: 2, Resource: "users", Action: "*", Effect: domain.EffectAllow},
+			},
+			attributes: map[string]string{"region": "jp"},
+			want: domain.AuthorizeDecision{Resource: "users", Action: "edit", Allowed: true,
+				Rule: &domain.DecidingRule{PermissionID: 2, Permission: "users:*", Effect: domain.EffectAllow}},
+		},
+		{
+			name: "條件成立的 deny 優先於 allow",
+			rolePerm: []domain.Permission{
+				{ID: 1, Resource: "users", Action: "*", Effect: domain.EffectAllow},
+				{ID: 2, Resource: "users", Action: "edit", Effect: domain.EffectDeny, Condition: "subject.region != request.region"},
+			},
+			attributes: map[string]string{"region": "jp"},
+			want: domain.AuthorizeDecision{Resource: "users", Action: "edit", Allowed: false,
+				Rule: &domain.DecidingRule{PermissionID: 2, Permission: "users:edit", Effect: domain.EffectDeny, Condition: "subject.region != request.region"}},
+		},
+		{
+			name: "條件不成立的 deny 不生效",
+			rolePerm: []domain.Permission{
+				{ID: 1, Resource: "users", Action: "*", Effect: domain.EffectAllow},
+				{ID: 2, Resource: "users", Action: "edit", Effect: domain.EffectDeny, Condition: "subject.region != request.region"},
+			},
+			attributes: map[string]string{"region": "tw"},
+			want: domain.AuthorizeDecision{Resource: "users", Action: "edit", Allowed: true,
+				Rule: &domain.DecidingRule{PermissionID: 1, Permission: "users:*", Effect: domain.EffectAllow}},
+		},
+		{
+			name: "缺少屬性時 deny 仍生效",
+			rolePerm: []domain.Permission{
+				{ID: 1, Resource: "users", Action: "*", Effect: domain.EffectAllow},
+				{ID: 2, Resource: "users", Action: "edit", Effect: domain.EffectDeny, Condition: "subject.region != request.region"},
+			},
+			want: domain.AuthorizeDecision{Resource: "users", Action: "edit", Allowed: false,
+				Rule: &domain.DecidingRule{PermissionID: 2, Permission: "users:edit", Effect: domain.EffectDeny, Condition: "subject.region != request.region"}},
+		},
+		{
+			name:       "條件可引用用戶本身的欄位",
+			rolePerm:   []domain.Permission{{ID: 1, Resource: "users", Action: "edit", Effect: domain.EffectAllow, Condition: "subject.username == request.owner"}},
+			attributes: map[string]string{"owner": "testuser"},
+			want: domain.AuthorizeDecision{Resource: "users", Action: "edit", Allowed: true,
+				Rule: &domain.DecidingRule{PermissionID: 1, Permission: "users:edit", Effect: domain.EffectAllow, Condition: "subject.username == request.owner"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 準備測試數據
+			mockRepo := new(MockAuthRepository)
+			mockSessionRepo := new(MockSessionRepository)
+			mockRevocationRepo := new(MockTokenRevocationRepository)
+			authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+			username := "testuser"
+			token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+
+			// 設定模擬行為：用戶屬性 region 為 tw
+			mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+			mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+			mockRepo.On("GetByUsername", mock.Anything, username).Return(&domain.User{ID: 1, Username: username, Metadata: domain.UserMetadata{"region": "tw"}}, nil)
+			mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 2, Name: "cs"}}, nil)
+			mockRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+			mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{2}).Return(tt.rolePerm, nil)
+			mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+
+			// 執行授權判定
+			check := domain.PermissionCheck{Resource: "users", Action: "edit", Attributes: tt.attributes}
+			decision, err := authService.Authorize(context.Background(), username, token, "", check)
+
+			// 斷言
+			require.NoError(t, err)
+			assert.Equal(t, &tt.want, decision)
+		})
+	}
+}
+
+func TestAuthorize_ResourceInstance(t *testing.T) {
+	editGrant := []domain.ObjectGrant{{ID: 5, UserID: 1, Resource: "notice", ObjectID: "42", Action: "edit"}}
+	ownerRules := []domain.OwnerRule{{ID: 7, Resource: "notice", Action: "edit"}}
+	tests := []struct {
+		name     string
+		rolePerm []domain.Permission
+		grants   []domain.ObjectGrant
+		owner    *domain.ResourceOwner
+		want     domain.AuthorizeDecision
+	}{
+		{
+			name:   "實例授權允許該實例",
+			grants: editGrant,
+			want: domain.AuthorizeDecision{Resource: "notice", Action: "edit", ResourceID: "42", Allowed: true,
+				Rule: &domain.DecidingRule{Source: domain.RuleSourceObjectGrant, RuleID: 5, Permission: "notice:edit", Effect: domain.EffectAllow}},
+		},
+		{
+			name:   "擁有者依擁有者規則取得操作",
+			grants: []domain.ObjectGrant{},
+			owner:  &domain.ResourceOwner{Resource: "notice", ObjectID: "42", OwnerID: 1},
+			want: domain.AuthorizeDecision{Resource: "notice", Action: "edit", ResourceID: "42", Allowed: true,
+				Rule: &domain.DecidingRule{Source: domain.RuleSourceOwner, RuleID: 7, Permission: "notice:edit", Effect: domain.EffectAllow}},
+		},
+		{
+			name:   "非擁有者且無實例授權時拒絕",
+			grants: []domain.ObjectGrant{},
+			owner:  &domain.ResourceOwner{Resource: "notice", ObjectID: "42", OwnerID: 9},
+			want:   domain.AuthorizeDecision{Resource: "notice", Action: "edit", ResourceID: "42", Allowed: false},
+		},
+		{
+			name:     "類型層級的 deny 優先於實例授權",
+			rolePerm: []domain.Permission{{ID: 2, Resource: "notice", Action: "edit", Effect: domain.EffectDeny}},
+			want: domain.AuthorizeDecision{Resource: "notice", Action: "edit", ResourceID: "42", Allowed: false,
+				Rule: &domain.DecidingRule{PermissionID: 2, Permission: "notice:edit", Effect: domain.EffectDeny}},
+		},
+		{
+			name:     "類型層級的 allow 涵蓋所有實例",
+			rolePerm: []domain.Permission{{ID: 1, Resource: "notice", Action: "*", Effect: domain.EffectAllow}},
+			want: domain.AuthorizeDecision{Resource: "notice", Action: "edit", ResourceID: "42", Allowed: true,
+				Rule: &domain.DecidingRule{PermissionID: 1, Permission: "notice:*", Effect: domain.EffectAllow}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 準備測試數據
+			mockRepo := new(MockAuthRepository)
+			mockSessionRepo := new(MockSessionRepository)
+			mockRevocationRepo := new(MockTokenRevocationRepository)
+			authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+			username := "testuser"
+			token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+
+			// 設定模擬行為
+			mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+			mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+			mockRepo.On("GetByUsername", mock.Anything, username).Return(&domain.User{ID: 1, Username: username}, nil)
+			mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 2, Name: "editor"}}, nil)
+			mockRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+			mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{2}).Return(tt.rolePerm, nil)
+			mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+			mockRepo.On("ListObjectGrants", mock.Anything, int64(1), domain.GlobalTenantID, "notice", "42").Return(tt.grants, nil)
+			if tt.owner != nil {
+				mockRepo.On("GetResourceOwner", mock.Anything, domain.GlobalTenantID, "notice", "42").Return(tt.owner, nil)
+			} else {
+				mockRepo.On("GetResourceOwner", mock.Anything, domain.GlobalTenantID, "notice", "42").Return(nil, domain.ErrResourceOwnerNotFound)
+			}
+			mockRepo.On("ListOwnerRules", mock.Anything).Return(ownerRules, nil)
+
+			// 執行授權判定
+			check := domain.PermissionCheck{Resource: "notice", Action: "edit", ResourceID: "42"}
+			decision, err := authService.Authorize(context.Background(), username, token, "", check)
+
+			// 斷言
+			require.NoError(t, err)
+			assert.Equal(t, &tt.want, decision)
+			if tt.rolePerm != nil {
+				// 類型層級已有決定時不查詢實例授權
+				mockRepo.AssertNotCalled(t, "ListObjectGrants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthorize_InvalidResourceID(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+
+	// 執行授權判定
+	check := domain.PermissionCheck{Resource: "notice", Action: "edit", ResourceID: "a/b"}
+	decision, err := authService.Authorize(context.Background(), "testuser", token, "", check)
+
+	// 斷言
+	assert.ErrorIs(t, err, domain.ErrInvalidAuthorizeRequest)
+	assert.Nil(t, decision)
+	mockRepo.AssertNotCalled(t, "GetByUsername", mock.Anything, mock.Anything)
+}
+
+func TestAuthorize_TenantScopedRoles(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokenTenant string
+		requested   string
+		allowed     bool
+		err         error
+	}{
+		{"未指定租戶時只套用全域角色", "", "", false, nil},
+		{"未綁定租戶的令牌以標頭指定租戶", "", "game-a", true, nil},
+		{"綁定租戶的令牌套用其租戶", "game-a", "", true, nil},
+		{"標頭與令牌綁定的租戶相同", "game-a", "game-a", true, nil},
+		{"標頭與令牌綁定的租戶不同", "game-b", "game-a", false, domain.ErrTenantMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 準備測試數據
+			mockRepo := new(MockAuthRepository)
+			mockSessionRepo := new(MockSessionRepository)
+			mockRevocationRepo := new(MockTokenRevocationRepository)
+			authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+			token, _ := utils.GenerateTenantJWTToken(1, "testuser", "session-1", tt.tokenTenant, nil)
+
+			// 設定模擬行為：gm 角色只分配在 game-a 租戶內
+			mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, "testuser", mock.Anything).Return(false, nil)
+			mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+			mockRepo.On("GetByUsername", mock.Anything, "testuser").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+			mockRepo.On("GetTenantByCode", mock.Anything, "game-a").Return(&domain.Tenant{ID: 5, Code: "game-a"}, nil)
+			mockRepo.On("IsTenantMember", mock.Anything, int64(5), int64(1)).Return(true, nil)
+			mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{}, nil)
+			mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), int64(5)).Return([]domain.Role{{ID: 4, Name: "gm"}}, nil)
+			mockRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+			mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{}).Return([]domain.Permission{}, nil)
+			mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{4}).Return([]domain.Permission{
+				{ID: 1, Resource: "player", Action: "ban"},
+			}, nil)
+			mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+			mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), int64(5)).Return([]domain.Permission{}, nil)
+
+			// 執行授權判定
+			decision, err := authService.Authorize(context.Background(), "testuser", token, tt.requested, domain.PermissionCheck{Resource: "player", Action: "ban"})
+
+			// 斷言
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				mockRepo.AssertNotCalled(t, "GetRolesByUserID", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.allowed, decision.Allowed)
+		})
+	}
+}
+
+func TestAuthorize_TenantScopedObjectGrants(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	token, _ := utils.GenerateTenantJWTToken(1, "testuser", "session-1", "game-a", nil)
+
+	// 設定模擬行為：實例授權與擁有者登記皆依令牌綁定的租戶查詢
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, "testuser", mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+	mockRepo.On("GetByUsername", mock.Anything, "testuser").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+	mockRepo.On("GetTenantByCode", mock.Anything, "game-a").Return(&domain.Tenant{ID: 5, Code: "game-a"}, nil)
+	mockRepo.On("IsTenantMember", mock.Anything, int64(5), int64(1)).Return(true, nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), int64(5)).Return([]domain.Role{}, nil)
+	mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{}).Return([]domain.Permission{}, nil)
+	mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), int64(5)).Return([]domain.Permission{}, nil)
+	mockRepo.On("ListObjectGrants", mock.Anything, int64(1), int64(5), "notice", "42").Return([]domain.ObjectGrant{}, nil)
+	mockRepo.On("GetResourceOwner", mock.Anything, int64(5), "notice", "42").Return(&domain.ResourceOwner{TenantID: 5, Resource: "notice", ObjectID: "42", OwnerID: 1}, nil)
+	mockRepo.On("ListOwnerRules", mock.Anything).Return([]domain.OwnerRule{{ID: 1, Resource: "notice", Action: "edit"}}, nil)
+
+	// 執行授權判定
+	decision, err := authService.Authorize(context.Background(), "testuser", token, "", domain.PermissionCheck{Resource: "notice", Action: "edit", ResourceID: "42"})
+
+	// 斷言
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthorize_NotTenantMember(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+
+	// 設定模擬行為：以標頭指定不是其成員的租戶
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, "testuser", mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+	mockRepo.On("GetByUsername", mock.Anything, "testuser").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+	mockRepo.On("GetTenantByCode", mock.Anything, "game-a").Return(&domain.Tenant{ID: 5, Code: "game-a"}, nil)
+	mockRepo.On("IsTenantMember", mock.Anything, int64(5), int64(1)).Return(false, nil)
+
+	// 執行授權判定
+	_, err := authService.Authorize(context.Background(), "testuser", token, "game-a", domain.PermissionCheck{Resource: "player", Action: "ban"})
+
+	// 斷言
+	assert.ErrorIs(t, err, domain.ErrNotTenantMember)
+	mockRepo.AssertNotCalled(t, "GetRolesByUserID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckPermission_Denied(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	username := "testuser"
+	token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+	mockUser := &domain.User{ID: 1, Username: username}
+
+	// 設定模擬行為
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+	mockRepo.On("GetByUsername", mock.Anything, username).Return(mockUser, nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 3, Name: "cs"}}, nil)
+	mockRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+	mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{3}).Return([]domain.Permission{
+		{ID: 1, Resource: "notice", Action: "view"},
+	}, nil)
+	mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+
+	// 執行權限檢查
+	allowed, err := authService.CheckPermission(context.Background(), username, token, "notice", "publish")
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckPermission_Wildcard(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	username := "testuser"
+	token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+
+	// 設定模擬行為
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+	mockRepo.On("GetByUsername", mock.Anything, username).Return(&domain.User{ID: 1, Username: username}, nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 2, Name: "operator"}}, nil)
+	mockRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+	mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{2}).Return([]domain.Permission{
+		{ID: 1, Resource: "notice", Action: "*"},
+		{ID: 2, Resource: "*", Action: "view"},
+		{ID: 3, Resource: "game/*", Action: "config"},
+	}, nil)
+	mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+
+	tests := []struct {
+		resource string
+		action   string
+		want     bool
+	}{
+		{"notice", "publish", true},
+		{"stats", "view", true},
+		{"stats", "export", false},
+		{"game/123/config", "config", true},
+		{"game", "config", false},
+	}
+
+	for _, tt := range tests {
+		// 執行權限檢查
+		allowed, err := authService.CheckPermission(context.Background(), username, token, tt.resource, tt.action)
+
+		// 斷言
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, allowed, "%s:%s", tt.resource, tt.action)
+	}
+}
+
+func TestCheckPermission_NoRoles(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	username := "testuser"
+	token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+	mockUser := &domain.User{ID: 1, Username: username}
+
+	// 設定模擬行為
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+	mockRepo.On("GetByUsername", mock.Anything, username).Return(mockUser, nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{}, nil)
+	mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{}).Return([]domain.Permission{}, nil)
+	mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+
+	// 執行權限檢查
+	allowed, err := authService.CheckPermission(context.Background(), username, token, "user", "view")
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckPermission_GlobalAssignmentsOnly(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	username := "testuser"
+	token, _ := utils.GenerateTenantJWTToken(1, username, "session-1", "acme", nil)
+
+	// 設定模擬行為：令牌綁定租戶，但管理權限只採計全域分配
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+	mockRepo.On("GetByUsername", mock.Anything, username).Return(&domain.User{ID: 1, Username: username}, nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{}, nil)
+	mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{}).Return([]domain.Permission{}, nil)
+	mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+
+	// 執行權限檢查
+	allowed, err := authService.CheckPermission(context.Background(), username, token, "user", "assign")
+
+	// 斷言：不解析令牌綁定的租戶，也不查詢租戶內的角色
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	mockRepo.AssertNotCalled(t, "GetTenantByCode", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckPermission_TokenInvalidated(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	username := "testuser"
+	token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+
+	// 設定模擬行為：會話已被終止
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(nil, domain.ErrSessionNotFound)
+
+	// 執行權限檢查
+	allowed, err := authService.CheckPermission(context.Background(), username, token, "user", "view")
+
+	// 斷言
+	assert.Error(t, err)
+	assert.False(t, allowed)
+	mockRepo.AssertNotCalled(t, "GetRolesByUserID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBatchCheckPermissions_SingleResolution(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	username := "testuser"
+	token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+
+	// 設定模擬行為：權限只解析一次
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil).Once()
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil).Once()
+	mockRepo.On("GetByUsername", mock.Anything, username).Return(&domain.User{ID: 1, Username: username}, nil).Once()
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 2, Name: "operator"}}, nil).Once()
+	mockRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil).Once()
+	mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{2}).Return([]domain.Permission{
+		{ID: 1, Resource: "notice", Action: "view"},
+		{ID: 2, Resource: "notice", Action: "publish"},
+	}, nil).Once()
+	mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil).Once()
+
+	// 執行批量權限檢查
+	result, err := authService.BatchCheckPermissions(context.Background(), username, token, "", []domain.PermissionCheck{
+		{Resource: "notice", Action: "publish"},
+		{Resource: "user", Action: "delete"},
+		{Resource: "notice", Action: "view"},
+	}, true)
+
+	// 斷言：結果依請求順序排列
+	require.NoError(t, err)
+	assert.Equal(t, []domain.AuthorizeDecision{
+		{Resource: "notice", Action: "publish", Allowed: true, Rule: &domain.DecidingRule{PermissionID: 2, Permission: "notice:publish", Effect: domain.EffectAllow}},
+		{Resource: "user", Action: "delete", Allowed: false},
+		{Resource: "notice", Action: "view", Allowed: true, Rule: &domain.DecidingRule{PermissionID: 1, Permission: "notice:view", Effect: domain.EffectAllow}},
+	}, result.Decisions)
+	assert.Equal(t, []string{"notice:view", "notice:publish"}, result.Permissions)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBatchCheckPermissions_PermissionsOnly(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	username := "testuser"
+	token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+	mockRepo.On("GetByUsername", mock.Anything, username).Return(&domain.User{ID: 1, Username: username}, nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{}, nil)
+	mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{}).Return([]domain.Permission{}, nil)
+	mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{}, nil)
+
+	// 只要求權限列表，沒有角色的用戶返回空列表
+	result, err := authService.BatchCheckPermissions(context.Background(), username, token, "", nil, true)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Decisions)
+	assert.NotNil(t, result.Permissions)
+	assert.Empty(t, result.Permissions)
+}
+
+func TestBatchCheckPermissions_DeniedPermissions(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockAuthRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	authService := NewAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	username := "testuser"
+	token, _ := utils.GenerateJWTToken(1, username, "session-1", nil)
+
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, mock.Anything, username, mock.Anything).Return(false, nil)
+	mockSessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(&domain.Session{ID: "session-1", UserID: 1, LastSeenAt: time.Now()}, nil)
+	mockRepo.On("GetByUsername", mock.Anything, username).Return(&domain.User{ID: 1, Username: username}, nil)
+	mockRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 2, Name: "operator"}}, nil)
+	mockRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+	mockRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{2}).Return([]domain.Permission{
+		{ID: 1, Resource: "stats", Action: "*", Effect: domain.EffectAllow},
+	}, nil)
+	mockRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Permission{
+		{ID: 2, Resource: "stats", Action: "export", Effect: domain.EffectDeny},
+	}, nil)
+
+	// 被拒絕的權限另外列出，客戶端快取時須優先套用
+	result, err := authService.BatchCheckPermissions(context.Background(), username, token, "", []domain.PermissionCheck{
+		{Resource: "stats", Action: "export"},
+	}, true)
+
+	require.NoError(t, err)
+	assert.False(t, result.Decisions[0].Allowed)
+	assert.Equal(t, []string{"stats:*"}, result.Permissions)
+	assert.Equal(t, []string{"stats:export"}, result.DeniedPermissions)
+}
+
+func TestBatchCheckPermissions_InvalidRequest(t *testing.T) {
+	authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), new(MockSessionRepository), 0, domain.ValidationModeStrict, nil)
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+
+	tooMany := make([]domain.PermissionCheck, maxBatchAuthorizeSize+1)
+	for i := range tooMany {
+		tooMany[i] = domain.PermissionCheck{Resource: "notice", Action: "view"}
+	}
+
+	tests := []struct {
+		name               string
+		checks             []domain.PermissionCheck
+		includePermissions bool
+	}{
+		{name: "empty", checks: nil},
+		{name: "too many", checks: tooMany, includePermissions: true},
+		{name: "missing action", checks: []domain.PermissionCheck{{Resource: "notice"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := authService.BatchCheckPermissions(context.Background(), "testuser", token, "", tt.checks, tt.includePermissions)
+			assert.ErrorIs(t, err, domain.ErrInvalidAuthorizeRequest)
+		})
+	}
+}
+
+func TestValidateClaims_Strict(t *testing.T) {
+	// 準備測試數據
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStrict, nil)
+
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	claims, _ := utils.ParseJWTToken(token)
+
+	// 設定模擬行為：令牌已被撤銷
+	mockRevocationRepo.On("IsTokenRevoked", mock.Anything, claims.ID, "testuser", claims.IssuedAt.Time).Return(true, nil)
+
+	// 執行驗證
+	err := authService.ValidateClaims(context.Background(), claims)
+
+	// 斷言
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+	mockSessionRepo.AssertNotCalled(t, "GetSessionByID", mock.Anything, mock.Anything)
+}
+
+func TestValidateClaims_StatelessSkipsDatabase(t *testing.T) {
+	// 準備測試數據：未設定任何模擬行為，查詢資料庫即會失敗
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStateless, nil)
+
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	claims, _ := utils.ParseJWTToken(token)
+
+	// 執行驗證
+	err := authService.ValidateClaims(context.Background(), claims)
+
+	// 斷言
+	assert.NoError(t, err)
+	mockRevocationRepo.AssertNotCalled(t, "IsTokenRevoked", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockSessionRepo.AssertNotCalled(t, "GetSessionByID", mock.Anything, mock.Anything)
+}
+
+func TestValidateClaims_StatelessAppliedRevocations(t *testing.T) {
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	claims, _ := utils.ParseJWTToken(token)
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		revocations *domain.ActiveRevocations
+		wantErr     error
+	}{
+		{
+			name:        "token revoked",
+			revocations: &domain.ActiveRevocations{Tokens: []domain.RevokedToken{{JTI: claims.ID, ExpiresAt: expiresAt}}},
+			wantErr:     domain.ErrTokenRevoked,
+		},
+		{
+			name:        "session terminated",
+			revocations: &domain.ActiveRevocations{Sessions: []domain.RevokedSession{{SessionID: "session-1", ExpiresAt: expiresAt}}},
+			wantErr:     domain.ErrTokenRevoked,
+		},
+		{
+			name: "user revoked after issue",
+			revocations: &domain.ActiveRevocations{Users: []domain.UserTokenRevocation{
+				{Username: "testuser", RevokedBefore: claims.IssuedAt.Time, ExpiresAt: expiresAt},
+			}},
+			wantErr: domain.ErrTokenRevoked,
+		},
+		{
+			name: "user revoked before issue",
+			revocations: &domain.ActiveRevocations{Users: []domain.UserTokenRevocation{
+				{Username: "testuser", RevokedBefore: claims.IssuedAt.Add(-time.Minute), ExpiresAt: expiresAt},
+			}},
+		},
+		{
+			name:        "other token revoked",
+			revocations: &domain.ActiveRevocations{Tokens: []domain.RevokedToken{{JTI: "other", ExpiresAt: expiresAt}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService := NewAuthService(new(MockAuthRepository), new(MockRefreshTokenRepository), new(MockTokenRevocationRepository), new(MockSessionRepository), 0, domain.ValidationModeStateless, nil)
+
+			authService.ApplyRevocations(tt.revocations)
+			err := authService.ValidateClaims(context.Background(), claims)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateClaims_StatelessLocalLogout(t *testing.T) {
+	// 準備測試數據
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocationRepo := new(MockTokenRevocationRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	authService := NewAuthService(new(MockAuthRepository), mockRefreshRepo, mockRevocationRepo, mockSessionRepo, 0, domain.ValidationModeStateless, nil)
+
+	token, _ := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	claims, _ := utils.ParseJWTToken(token)
+
+	// 設定模擬行為
+	mockSessionRepo.On("DeleteSession", mock.Anything, "session-1").Return(nil)
+	mockRevocationRepo.On("RevokeSessions", mock.Anything, []string{"session-1"}, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRefreshRepo.On("RevokeRefreshTokenFamily", mock.Anything, "session-1").Return(nil)
+	mockRevocationRepo.On("RevokeToken", mock.Anything, mock.Anything).Return(nil)
+
+	// 執行登出後，同一實例不需等待同步即拒絕該令牌
+	assert.NoError(t, authService.Logout(context.Background(), token))
+	err := authService.ValidateClaims(context.Background(), claims)
+
+	// 斷言
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+	mockRevocationRepo.AssertNotCalled(t, "IsTokenRevoked", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"rbac-service/domain"
+	"rbac-service/infrastructure/utils"
+)
+
+// KeyService JWT 簽章金鑰輪替服務。金鑰內容來自設定檔，輪替狀態存於資料庫，
+// 各實例定期同步後套用至記憶體中的金鑰組
+type KeyService struct {
+	signingKeyRepo domain.SigningKeyRepository
+	// configuredCurrent 設定檔指定的簽發金鑰，資料庫尚無 current 記錄時使用
+	configuredCurrent string
+}
+
+// NewKeyService 創建新的 KeyService，須在載入金鑰組之後呼叫
+func NewKeyService(signingKeyRepo domain.SigningKeyRepository) *KeyService {
+	return &KeyService{
+		signingKeyRepo:    signingKeyRepo,
+		configuredCurrent: utils.CurrentSigningKeyID(),
+	}
+}
+
+// Sync 讀取資料庫中的輪替狀態並套用至金鑰組
+func (s *KeyService) Sync(ctx context.Context) error {
+	states, err := s.signingKeyRepo.ListSigningKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	currentID := s.configuredCurrent
+	retireAt := map[string]time.Time{}
+	for _, state := range states {
+		switch state.Status {
+		case domain.SigningKeyStatusCurrent:
+			if utils.HasSigningKey(state.Kid) {
+				currentID = state.Kid
+			} else {
+				log.Printf("簽發金鑰 %s 未載入，沿用 %s，請確認設定檔已包含該金鑰", state.Kid, currentID)
+			}
+		case domain.SigningKeyStatusRetired:
+			if state.RetireAt != nil {
+				retireAt[state.Kid] = *state.RetireAt
+			}
+		}
+	}
+
+	return utils.UpdateSigningKeyStates(currentID, retireAt)
+}
+
+// ListSigningKeys 依載入順序列出金鑰與其目前的狀態
+func (s *KeyService) ListSigningKeys(ctx context.Context) ([]domain.SigningKey, error) {
+	if err := s.Sync(ctx); err != nil {
+		return nil, err
+	}
+
+	currentID := utils.CurrentSigningKeyID()
+	keys := []domain.SigningKey{}
+	for _, kid := range utils.SigningKeyIDs() {
+		key := domain.SigningKey{Kid: kid, Status: domain.SigningKeyStatusActive}
+		if kid == currentID {
+			key.Status = domain.SigningKeyStatusCurrent
+		} else if retireAt, ok := utils.SigningKeyRetireAt(kid); ok {
+			key.Status = domain.SigningKeyStatusRetired
+			key.RetireAt = &retireAt
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Promote 將金鑰設為簽發用的金鑰，原本的金鑰仍可驗證，直到被退役
+func (s *KeyService) Promote(ctx context.Context, kid string) error {
+	if !utils.HasSigningKey(kid) {
+		return domain.ErrSigningKeyNotFound
+	}
+
+	if err := s.signingKeyRepo.PromoteSigningKey(ctx, kid); err != nil {
+		return err
+	}
+	return s.Sync(ctx)
+}
+
+// Retire 排定金鑰在 grace 之後退役，期間仍接受以其簽發的令牌；返回退役時間
+func (s *KeyService) Retire(ctx context.Context, kid string, grace time.Duration) (time.Time, error) {
+	if !utils.HasSigningKey(kid) {
+		return time.Time{}, domain.ErrSigningKeyNotFound
+	}
+	if grace < 0 {
+		return time.Time{}, errors.New("grace period must not be negative")
+	}
+
+	// 以資料庫中最新的狀態判斷是否仍用於簽發
+	if err := s.Sync(ctx); err != nil {
+		return time.Time{}, err
+	}
+	if kid == utils.CurrentSigningKeyID() {
+		return time.Time{}, domain.ErrSigningKeyInUse
+	}
+
+	retireAt := time.Now().Add(grace)
+	if err := s.signingKeyRepo.RetireSigningKey(ctx, kid, retireAt); err != nil {
+		return time.Time{}, err
+	}
+	return retireAt, s.Sync(ctx)
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"rbac-service/domain"
+	"rbac-service/infrastructure/utils"
+)
+
+// MockSigningKeyRepository 模擬 SigningKeyRepository
+type MockSigningKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockSigningKeyRepository) ListSigningKeys(ctx context.Context) ([]domain.SigningKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.SigningKey), args.Error(1)
+}
+
+func (m *MockSigningKeyRepository) PromoteSigningKey(ctx context.Context, kid string) error {
+	args := m.Called(ctx, kid)
+	return args.Error(0)
+}
+
+func (m *MockSigningKeyRepository) RetireSigningKey(ctx context.Context, kid string, retireAt time.Time) error {
+	args := m.Called(ctx, kid, retireAt)
+	return args.Error(0)
+}
+
+// useRotationKeys 載入 old、new 兩把 HS256 金鑰並以 old 簽發，結束後清除輪替狀態
+func useRotationKeys(t *testing.T) {
+	var signingKeys []*utils.SigningKey
+	for _, kid := range []string{"old", "new"} {
+		key, err := utils.NewSigningKey(kid, utils.AlgHS256, []byte(kid+"-secret-for-rotation-tests-0123456789"))
+		require.NoError(t, err)
+		signingKeys = append(signingKeys, key)
+	}
+	require.NoError(t, utils.SetSigningKeys(signingKeys, "old"))
+	t.Cleanup(func() { _ = utils.UpdateSigningKeyStates("old", nil) })
+}
+
+func TestKeyService_PromoteAndRetire(t *testing.T) {
+	useRotationKeys(t)
+	mockRepo := new(MockSigningKeyRepository)
+	keyService := NewKeyService(mockRepo)
+
+	oldToken, err := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	require.NoError(t, err)
+
+	// 提升新金鑰後以新金鑰簽發，舊令牌仍可驗證
+	mockRepo.On("PromoteSigningKey", mock.Anything, "new").Return(nil)
+	mockRepo.On("ListSigningKeys", mock.Anything).Return([]domain.SigningKey{
+		{Kid: "new", Status: domain.SigningKeyStatusCurrent},
+	}, nil).Times(2)
+
+	require.NoError(t, keyService.Promote(context.Background(), "new"))
+	assert.Equal(t, "new", utils.CurrentSigningKeyID())
+	_, err = utils.ParseJWTToken(oldToken)
+	assert.NoError(t, err)
+
+	// 寬限期內舊令牌仍有效
+	mockRepo.On("RetireSigningKey", mock.Anything, "old", mock.AnythingOfType("time.Time")).Return(nil)
+	retireAt := time.Now().Add(time.Hour)
+	mockRepo.On("ListSigningKeys", mock.Anything).Return([]domain.SigningKey{
+		{Kid: "new", Status: domain.SigningKeyStatusCurrent},
+		{Kid: "old", Status: domain.SigningKeyStatusRetired, RetireAt: &retireAt},
+	}, nil)
+
+	_, err = keyService.Retire(context.Background(), "old", time.Hour)
+	require.NoError(t, err)
+	_, err = utils.ParseJWTToken(oldToken)
+	assert.NoError(t, err)
+
+	keys, err := keyService.ListSigningKeys(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, domain.SigningKeyStatusRetired, keys[0].Status)
+	assert.Equal(t, domain.SigningKeyStatusCurrent, keys[1].Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestKeyService_Sync_RetiredKeyRejected(t *testing.T) {
+	useRotationKeys(t)
+	mockRepo := new(MockSigningKeyRepository)
+	keyService := NewKeyService(mockRepo)
+
+	oldToken, err := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	require.NoError(t, err)
+
+	// 其他實例已提升新金鑰且舊金鑰的寬限期已過
+	retireAt := time.Now().Add(-time.Minute)
+	mockRepo.On("ListSigningKeys", mock.Anything).Return([]domain.SigningKey{
+		{Kid: "new", Status: domain.SigningKeyStatusCurrent},
+		{Kid: "old", Status: domain.SigningKeyStatusRetired, RetireAt: &retireAt},
+	}, nil)
+
+	require.NoError(t, keyService.Sync(context.Background()))
+	_, err = utils.ParseJWTToken(oldToken)
+	assert.Error(t, err)
+
+	newToken, err := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	require.NoError(t, err)
+	_, err = utils.ParseJWTToken(newToken)
+	assert.NoError(t, err)
+}
+
+func TestKeyService_Sync_UnknownCurrentKey(t *testing.T) {
+	useRotationKeys(t)
+	mockRepo := new(MockSigningKeyRepository)
+	keyService := NewKeyService(mockRepo)
+
+	// 資料庫指定的金鑰尚未佈署至此實例時沿用設定檔的金鑰
+	mockRepo.On("ListSigningKeys", mock.Anything).Return([]domain.SigningKey{
+		{Kid: "next", Status: domain.SigningKeyStatusCurrent},
+	}, nil)
+
+	require.NoError(t, keyService.Sync(context.Background()))
+	assert.Equal(t, "old", utils.CurrentSigningKeyID())
+}
+
+func TestKeyService_RejectedOperations(t *testing.T) {
+	useRotationKeys(t)
+	mockRepo := new(MockSigningKeyRepository)
+	keyService := NewKeyService(mockRepo)
+	mockRepo.On("ListSigningKeys", mock.Anything).Return([]domain.SigningKey{}, nil)
+
+	err := keyService.Promote(context.Background(), "missing")
+	assert.ErrorIs(t, err, domain.ErrSigningKeyNotFound)
+
+	_, err = keyService.Retire(context.Background(), "missing", time.Hour)
+	assert.ErrorIs(t, err, domain.ErrSigningKeyNotFound)
+
+	// 簽發用的金鑰須先提升其他金鑰才能退役
+	_, err = keyService.Retire(context.Background(), "old", time.Hour)
+	assert.ErrorIs(t, err, domain.ErrSigningKeyInUse)
+
+	_, err = keyService.Retire(context.Background(), "new", -time.Hour)
+	assert.Error(t, err)
+
+	mockRepo.AssertNotCalled(t, "PromoteSigningKey", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "RetireSigningKey", mock.Anything, mock.Anything, mock.Anything)
+}
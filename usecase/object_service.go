@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"rbac-service/domain"
+	"rbac-service/domain/matcher"
+)
+
+// ObjectService 管理資源實例授權、擁有者與擁有者規則，並列出用戶可操作的資源實例
+type ObjectService struct {
+	authRepo   domain.AuthRepository
+	objectRepo domain.ObjectRepository
+}
+
+// NewObjectService 創建資源實例授權服務
+func NewObjectService(authRepo domain.AuthRepository, objectRepo domain.ObjectRepository) *ObjectService {
+	return &ObjectService{authRepo: authRepo, objectRepo: objectRepo}
+}
+
+// getUser 解析用戶ID並確認用戶存在
+func (s *ObjectService) getUser(ctx context.Context, id string) (*domain.User, error) {
+	userID, err := parseID(id, domain.ErrInvalidUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authRepo.GetByID(ctx, strconv.FormatInt(userID, 10))
+}
+
+// validateObjectResource 資源類型須為不含萬用字元的有效權限資源
+func validateObjectResource(resource string) error {
+	if !isValidPermissionField(resource) || strings.Contains(resource, matcher.Wildcard) ||
+		matcher.ValidatePattern(resource, matcher.Wildcard) != nil {
+		return domain.ErrInvalidObjectResource
+	}
+	return nil
+}
+
+// validateObjectAction 操作須為有效的權限操作，可為 "*"
+func validateObjectAction(action string) error {
+	if !isValidPermissionField(action) || matcher.ValidatePattern(matcher.Wildcard, action) != nil {
+		return domain.ErrInvalidObjectResource
+	}
+	return nil
+}
+
+// ListUserObjectGrants 列出用戶的所有實例授權
+func (s *ObjectService) ListUserObjectGrants(ctx context.Context, userID string) ([]domain.ObjectGrant, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.objectRepo.ListUserObjectGrants(ctx, user.ID)
+}
+
+// CreateObjectGrant 為用戶新增對單一資源實例的授權，grant.TenantID 為 GlobalTenantID 時在所有租戶中生效，
+// 租戶內的授權要求用戶為該租戶成員
+func (s *ObjectService) CreateObjectGrant(ctx context.Context, userID string, grant *domain.ObjectGrant) (*domain.ObjectGrant, error) {
+	grant.Resource = strings.TrimSpace(grant.Resource)
+	grant.ObjectID = strings.TrimSpace(grant.ObjectID)
+	grant.Action = strings.TrimSpace(grant.Action)
+	if err := validateObjectResource(grant.Resource); err != nil {
+		return nil, err
+	}
+	if err := validateObjectAction(grant.Action); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateObjectID(grant.ObjectID); err != nil {
+		return nil, err
+	}
+	if grant.TenantID < 0 {
+		return nil, domain.ErrInvalidTenantID
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireTenantMember(ctx, s.authRepo, grant.TenantID, user.ID); err != nil {
+		return nil, err
+	}
+
+	grant.UserID = user.ID
+	if err := s.objectRepo.CreateObjectGrant(ctx, grant); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// DeleteObjectGrant 刪除用戶的實例授權
+func (s *ObjectService) DeleteObjectGrant(ctx context.Context, userID, grantID string) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	parsedGrantID, err := parseID(grantID, domain.ErrObjectGrantNotFound)
+	if err != nil {
+		return err
+	}
+
+	return s.objectRepo.DeleteObjectGrant(ctx, user.ID, parsedGrantID)
+}
+
+// GetResourceOwner 獲取資源實例在租戶內的擁有者登記，tenantID 為空或 0 時獲取全域登記
+func (s *ObjectService) GetResourceOwner(ctx context.Context, tenantID, resource, objectID string) (*domain.ResourceOwner, error) {
+	resource, objectID = strings.TrimSpace(resource), strings.TrimSpace(objectID)
+	if err := validateObjectResource(resource); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateObjectID(objectID); err != nil {
+		return nil, err
+	}
+	scopeID, err := parseTenantID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.objectRepo.GetResourceOwner(ctx, scopeID, resource, objectID)
+}
+
+// SetResourceOwner 登記或變更資源實例在租戶內的擁有者，擁有者須為既有用戶，
+// owner.TenantID 不為 GlobalTenantID 時擁有者須為該租戶成員
+func (s *ObjectService) SetResourceOwner(ctx context.Context, owner *domain.ResourceOwner) (*domain.ResourceOwner, error) {
+	owner.Resource = strings.TrimSpace(owner.Resource)
+	owner.ObjectID = strings.TrimSpace(owner.ObjectID)
+	if err := validateObjectResource(owner.Resource); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateObjectID(owner.ObjectID); err != nil {
+		return nil, err
+	}
+	if owner.TenantID < 0 {
+		return nil, domain.ErrInvalidTenantID
+	}
+	if _, err := s.getUser(ctx, strconv.FormatInt(owner.OwnerID, 10)); err != nil {
+		return nil, err
+	}
+	if err := requireTenantMember(ctx, s.authRepo, owner.TenantID, owner.OwnerID); err != nil {
+		return nil, err
+	}
+
+	if err := s.objectRepo.SetResourceOwner(ctx, owner); err != nil {
+		return nil, err
+	}
+	return s.objectRepo.GetResourceOwner(ctx, owner.TenantID, owner.Resource, owner.ObjectID)
+}
+
+// DeleteResourceOwner 移除資源實例在租戶內的擁有者，通常於資源刪除時呼叫；tenantID 為空或 0 時移除全域登記
+func (s *ObjectService) DeleteResourceOwner(ctx context.Context, tenantID, resource, objectID string) error {
+	resource, objectID = strings.TrimSpace(resource), strings.TrimSpace(objectID)
+	if err := validateObjectResource(resource); err != nil {
+		return err
+	}
+	if err := domain.ValidateObjectID(objectID); err != nil {
+		return err
+	}
+	scopeID, err := parseTenantID(tenantID)
+	if err != nil {
+		return err
+	}
+
+	return s.objectRepo.DeleteResourceOwner(ctx, scopeID, resource, objectID)
+}
+
+// ListOwnerRules 列出所有擁有者規則
+func (s *ObjectService) ListOwnerRules(ctx context.Context) ([]domain.OwnerRule, error) {
+	return s.objectRepo.ListOwnerRules(ctx)
+}
+
+// CreateOwnerRule 新增擁有者規則，資源與操作可使用萬用字元
+func (s *ObjectService) CreateOwnerRule(ctx context.Context, rule *domain.OwnerRule) (*domain.OwnerRule, error) {
+	rule.Resource = strings.TrimSpace(rule.Resource)
+	rule.Action = strings.TrimSpace(rule.Action)
+	if !isValidPermissionField(rule.Resource) || !isValidPermissionField(rule.Action) ||
+		matcher.ValidatePattern(rule.Resource, rule.Action) != nil {
+		return nil, domain.ErrInvalidObjectResource
+	}
+
+	if err := s.objectRepo.CreateOwnerRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteOwnerRule 刪除擁有者規則
+func (s *ObjectService) DeleteOwnerRule(ctx context.Context, id string) error {
+	ruleID, err := parseID(id, domain.ErrOwnerRuleNotFound)
+	if err != nil {
+		return err
+	}
+
+	return s.objectRepo.DeleteOwnerRule(ctx, ruleID)
+}
+
+// ListAccessibleObjects 列出用戶可對某類資源執行指定操作的實例。
+// 權限分配允許該類資源時返回 All，明確拒絕時返回空列表；
+// 否則合併實例授權與擁有者規則允許的實例。條件式分配以不帶請求屬性的方式判定，
+// 權限分配、實例授權與擁有者登記依 tenantID 指定的租戶解析，空字串或 0 時只計入全域分配
+func (s *ObjectService) ListAccessibleObjects(ctx context.Context, userID, tenantID, resource, action string) (*domain.AccessibleObjects, error) {
+	resource, action = strings.TrimSpace(resource), strings.TrimSpace(action)
+	if err := validateObjectResource(resource); err != nil {
+		return nil, err
+	}
+	if !isValidPermissionField(action) || strings.Contains(action, matcher.Wildcard) {
+		return nil, domain.ErrInvalidObjectResource
+	}
+
+	scopeID, err := parseTenantID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := resolveUserPermissions(ctx, s.authRepo, user.ID, scopeID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.AccessibleObjects{Resource: resource, Action: action, ObjectIDs: []string{}}
+	check := domain.PermissionCheck{Resource: resource, Action: action}
+	decision := decide(permissions, check, conditionAttributes(user, nil, time.Now()))
+	if decision.Allowed {
+		result.All = true
+		return result, nil
+	}
+	if decision.Rule != nil {
+		return result, nil
+	}
+
+	objectIDs := map[string]bool{}
+	grants, err := s.authRepo.ListObjectGrants(ctx, user.ID, scopeID, resource, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, grant := range grants {
+		if grant.Matches(action) {
+			objectIDs[grant.ObjectID] = true
+		}
+	}
+
+	owned, err := s.ownedObjectIDs(ctx, user.ID, scopeID, resource, action)
+	if err != nil {
+		return nil, err
+	}
+	for _, objectID := range owned {
+		objectIDs[objectID] = true
+	}
+
+	for objectID := range objectIDs {
+		result.ObjectIDs = append(result.ObjectIDs, objectID)
+	}
+	sort.Strings(result.ObjectIDs)
+	return result, nil
+}
+
+// ownedObjectIDs 有擁有者規則涵蓋該資源與操作時，返回用戶在租戶內擁有的實例
+func (s *ObjectService) ownedObjectIDs(ctx context.Context, userID, tenantID int64, resource, action string) ([]string, error) {
+	rules, err := s.authRepo.ListOwnerRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.Matches(resource, action) {
+			return s.authRepo.ListOwnedObjectIDs(ctx, userID, tenantID, resource)
+		}
+	}
+	return nil, nil
+}
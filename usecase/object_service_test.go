@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"rbac-service/domain"
+)
+
+// MockObjectRepository 模擬 ObjectRepository
+type MockObjectRepository struct {
+	mock.Mock
+}
+
+func (m *MockObjectRepository) ListUserObjectGrants(ctx context.Context, userID int64) ([]domain.ObjectGrant, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ObjectGrant), args.Error(1)
+}
+
+func (m *MockObjectRepository) CreateObjectGrant(ctx context.Context, grant *domain.ObjectGrant) error {
+	args := m.Called(ctx, grant)
+	return args.Error(0)
+}
+
+func (m *MockObjectRepository) DeleteObjectGrant(ctx context.Context, userID, grantID int64) error {
+	args := m.Called(ctx, userID, grantID)
+	return args.Error(0)
+}
+
+func (m *MockObjectRepository) GetResourceOwner(ctx context.Context, tenantID int64, resource, objectID string) (*domain.ResourceOwner, error) {
+	args := m.Called(ctx, tenantID, resource, objectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ResourceOwner), args.Error(1)
+}
+
+func (m *MockObjectRepository) SetResourceOwner(ctx context.Context, owner *domain.ResourceOwner) error {
+	args := m.Called(ctx, owner)
+	return args.Error(0)
+}
+
+func (m *MockObjectRepository) DeleteResourceOwner(ctx context.Context, tenantID int64, resource, objectID string) error {
+	args := m.Called(ctx, tenantID, resource, objectID)
+	return args.Error(0)
+}
+
+func (m *MockObjectRepository) ListOwnerRules(ctx context.Context) ([]domain.OwnerRule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.OwnerRule), args.Error(1)
+}
+
+func (m *MockObjectRepository) CreateOwnerRule(ctx context.Context, rule *domain.OwnerRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockObjectRepository) DeleteOwnerRule(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newTestObjectService() (*ObjectService, *MockAuthRepository, *MockObjectRepository) {
+	authRepo := new(MockAuthRepository)
+	objectRepo := new(MockObjectRepository)
+	return NewObjectService(authRepo, objectRepo), authRepo, objectRepo
+}
+
+func TestObjectService_CreateObjectGrant_Successful(t *testing.T) {
+	service, authRepo, objectRepo := newTestObjectService()
+
+	authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+	objectRepo.On("CreateObjectGrant", mock.Anything, mock.MatchedBy(func(grant *domain.ObjectGrant) bool {
+		return grant.UserID == 1 && grant.Resource == "notice" && grant.ObjectID == "42" && grant.Action == "edit"
+	})).Return(nil)
+
+	grant, err := service.CreateObjectGrant(context.Background(), "1", &domain.ObjectGrant{Resource: " notice ", ObjectID: "42", Action: "edit"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), grant.UserID)
+	objectRepo.AssertExpectations(t)
+}
+
+func TestObjectService_CreateObjectGrant_Tenant(t *testing.T) {
+	tests := []struct {
+		name   string
+		member bool
+		err    error
+	}{
+		{"租戶成員可在租戶內被授權", true, nil},
+		{"非租戶成員無法在租戶內被授權", false, domain.ErrNotTenantMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, authRepo, objectRepo := newTestObjectService()
+
+			authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+			authRepo.On("IsTenantMember", mock.Anything, int64(5), int64(1)).Return(tt.member, nil)
+			objectRepo.On("CreateObjectGrant", mock.Anything, mock.MatchedBy(func(grant *domain.ObjectGrant) bool {
+				return grant.UserID == 1 && grant.TenantID == 5
+			})).Return(nil)
+
+			_, err := service.CreateObjectGrant(context.Background(), "1", &domain.ObjectGrant{TenantID: 5, Resource: "notice", ObjectID: "42", Action: "edit"})
+
+			assert.ErrorIs(t, err, tt.err)
+			if tt.err != nil {
+				objectRepo.AssertNotCalled(t, "CreateObjectGrant", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestObjectService_CreateObjectGrant_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		grant domain.ObjectGrant
+		err   error
+	}{
+		{"資源類型不可含萬用字元", domain.ObjectGrant{Resource: "notice.*", ObjectID: "42", Action: "edit"}, domain.ErrInvalidObjectResource},
+		{"資源類型不可為 *", domain.ObjectGrant{Resource: "*", ObjectID: "42", Action: "edit"}, domain.ErrInvalidObjectResource},
+		{"操作不可為空", domain.ObjectGrant{Resource: "notice", ObjectID: "42"}, domain.ErrInvalidObjectResource},
+		{"實例ID不可為空", domain.ObjectGrant{Resource: "notice", Action: "edit"}, domain.ErrInvalidObjectID},
+		{"實例ID不可含 /", domain.ObjectGrant{Resource: "notice", ObjectID: "a/b", Action: "edit"}, domain.ErrInvalidObjectID},
+		{"租戶ID不可為負數", domain.ObjectGrant{TenantID: -1, Resource: "notice", ObjectID: "42", Action: "edit"}, domain.ErrInvalidTenantID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, authRepo, objectRepo := newTestObjectService()
+
+			_, err := service.CreateObjectGrant(context.Background(), "1", &tt.grant)
+
+			assert.ErrorIs(t, err, tt.err)
+			authRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+			objectRepo.AssertNotCalled(t, "CreateObjectGrant", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestObjectService_SetResourceOwner_OwnerNotFound(t *testing.T) {
+	service, authRepo, objectRepo := newTestObjectService()
+
+	authRepo.On("GetByID", mock.Anything, "9").Return(nil, domain.ErrUserNotFound)
+
+	_, err := service.SetResourceOwner(context.Background(), &domain.ResourceOwner{Resource: "notice", ObjectID: "42", OwnerID: 9})
+
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	objectRepo.AssertNotCalled(t, "SetResourceOwner", mock.Anything, mock.Anything)
+}
+
+func TestObjectService_SetResourceOwner_Tenant(t *testing.T) {
+	tests := []struct {
+		name   string
+		member bool
+		err    error
+	}{
+		{"租戶成員可登記為租戶內實例的擁有者", true, nil},
+		{"非租戶成員無法登記為租戶內實例的擁有者", false, domain.ErrNotTenantMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, authRepo, objectRepo := newTestObjectService()
+			owner := &domain.ResourceOwner{TenantID: 5, Resource: "notice", ObjectID: "42", OwnerID: 3}
+
+			authRepo.On("GetByID", mock.Anything, "3").Return(&domain.User{ID: 3}, nil)
+			authRepo.On("IsTenantMember", mock.Anything, int64(5), int64(3)).Return(tt.member, nil)
+			objectRepo.On("SetResourceOwner", mock.Anything, owner).Return(nil)
+			objectRepo.On("GetResourceOwner", mock.Anything, int64(5), "notice", "42").Return(owner, nil)
+
+			_, err := service.SetResourceOwner(context.Background(), owner)
+
+			assert.ErrorIs(t, err, tt.err)
+			if tt.err != nil {
+				objectRepo.AssertNotCalled(t, "SetResourceOwner", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestObjectService_ListAccessibleObjects(t *testing.T) {
+	tests := []struct {
+		name       string
+		userPerm   []domain.Permission
+		ownerRules []domain.OwnerRule
+		want       domain.AccessibleObjects
+	}{
+		{
+			name:     "類型層級的 allow 涵蓋所有實例",
+			userPerm: []domain.Permission{{ID: 1, Resource: "notice", Action: "*", Effect: domain.EffectAllow}},
+			want:     domain.AccessibleObjects{Resource: "notice", Action: "edit", All: true, ObjectIDs: []string{}},
+		},
+		{
+			name:     "類型層級的 deny 排除所有實例",
+			userPerm: []domain.Permission{{ID: 1, Resource: "notice", Action: "edit", Effect: domain.EffectDeny}},
+			want:     domain.AccessibleObjects{Resource: "notice", Action: "edit", ObjectIDs: []string{}},
+		},
+		{
+			name:       "合併實例授權與擁有的實例",
+			userPerm:   []domain.Permission{},
+			ownerRules: []domain.OwnerRule{{ID: 1, Resource: "notice", Action: "*"}},
+			want:       domain.AccessibleObjects{Resource: "notice", Action: "edit", ObjectIDs: []string{"42", "7", "8"}},
+		},
+		{
+			name:       "無擁有者規則時只有實例授權",
+			userPerm:   []domain.Permission{},
+			ownerRules: []domain.OwnerRule{{ID: 1, Resource: "notice", Action: "view"}},
+			want:       domain.AccessibleObjects{Resource: "notice", Action: "edit", ObjectIDs: []string{"42", "7"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, authRepo, _ := newTestObjectService()
+
+			authRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1, Username: "testuser"}, nil)
+			authRepo.On("GetRolesByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return([]domain.Role{{ID: 2, Name: "editor"}}, nil)
+			authRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{}, nil)
+			authRepo.On("GetPermissionsByRoleIDs", mock.Anything, []int64{2}).Return([]domain.Permission{}, nil)
+			authRepo.On("GetPermissionsByUserID", mock.Anything, int64(1), domain.GlobalTenantID).Return(tt.userPerm, nil)
+			authRepo.On("ListObjectGrants", mock.Anything, int64(1), domain.GlobalTenantID, "notice", "").Return([]domain.ObjectGrant{
+				{ID: 1, Resource: "notice", ObjectID: "7", Action: "edit"},
+				{ID: 2, Resource: "notice", ObjectID: "42", Action: "*"},
+				{ID: 3, Resource: "notice", ObjectID: "9", Action: "view"},
+			}, nil)
+			authRepo.On("ListOwnerRules", mock.Anything).Return(tt.ownerRules, nil)
+			authRepo.On("ListOwnedObjectIDs", mock.Anything, int64(1), domain.GlobalTenantID, "notice").Return([]string{"8", "42"}, nil)
+
+			objects, err := service.ListAccessibleObjects(context.Background(), "1", "", "notice", "edit")
+
+			require.NoError(t, err)
+			assert.Equal(t, &tt.want, objects)
+		})
+	}
+}
+
+func TestObjectService_ListAccessibleObjects_InvalidAction(t *testing.T) {
+	service, authRepo, _ := newTestObjectService()
+
+	_, err := service.ListAccessibleObjects(context.Background(), "1", "", "notice", "*")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidObjectResource)
+	authRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"rbac-service/domain"
+	"rbac-service/domain/matcher"
+)
+
+// maxPermissionFieldLength 資源與操作的最大長度，與 permissions 欄位一致
+const maxPermissionFieldLength = 64
+
+// PermissionService 權限服務實作
+type PermissionService struct {
+	repo domain.PermissionRepository
+}
+
+// NewPermissionService 創建權限服務
+func NewPermissionService(repo domain.PermissionRepository) *PermissionService {
+	return &PermissionService{repo: repo}
+}
+
+// isValidPermissionField 資源與操作不可為空、不可含空白，也不可含 ":"，避免與 "resource:action" 表示法衝突
+func isValidPermissionField(value string) bool {
+	if value == "" || utf8.RuneCountInString(value) > maxPermissionFieldLength {
+		return false
+	}
+	return !strings.ContainsFunc(value, func(r rune) bool {
+		return r == ':' || unicode.IsSpace(r)
+	})
+}
+
+// validatePermission 清理並驗證權限輸入
+func validatePermission(permission *domain.Permission) error {
+	permission.Resource = strings.TrimSpace(permission.Resource)
+	permission.Action = strings.TrimSpace(permission.Action)
+	permission.Description = strings.TrimSpace(permission.Description)
+
+	if !isValidPermissionField(permission.Resource) || !isValidPermissionField(permission.Action) {
+		return domain.ErrInvalidPermission
+	}
+	// 萬用字元必須佔滿一個層級，資源的層級不可為空
+	if matcher.ValidatePattern(permission.Resource, permission.Action) != nil {
+		return domain.ErrInvalidPermission
+	}
+	if utf8.RuneCountInString(permission.Description) > maxDescriptionLength {
+		return domain.ErrInvalidDescription
+	}
+	return nil
+}
+
+// GetPermission 獲取權限信息
+func (s *PermissionService) GetPermission(ctx context.Context, id string) (*domain.Permission, error) {
+	permissionID, err := parseID(id, domain.ErrInvalidPermissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetPermissionByID(ctx, permissionID)
+}
+
+// ListPermissions 列出權限，可依資源前綴過濾
+func (s *PermissionService) ListPermissions(ctx context.Context, resourcePrefix string) ([]domain.Permission, error) {
+	return s.repo.ListPermissions(ctx, strings.TrimSpace(resourcePrefix))
+}
+
+// CreatePermission 創建權限，(resource, action) 不可重複
+func (s *PermissionService) CreatePermission(ctx context.Context, permission *domain.Permission) (*domain.Permission, error) {
+	if err := validatePermission(permission); err != nil {
+		return nil, err
+	}
+
+	// 檢查資源與操作是否已存在
+	existing, err := s.repo.GetPermissionByResourceAction(ctx, permission.Resource, permission.Action)
+	if err != nil && !errors.Is(err, domain.ErrPermissionNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrPermissionAlreadyExists
+	}
+
+	return s.repo.CreatePermission(ctx, permission)
+}
+
+// UpdatePermission 更新權限的資源、操作與描述
+func (s *PermissionService) UpdatePermission(ctx context.Context, id string, permission *domain.Permission) (*domain.Permission, error) {
+	permissionID, err := parseID(id, domain.ErrInvalidPermissionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePermission(permission); err != nil {
+		return nil, err
+	}
+
+	// 確認權限存在
+	if _, err := s.repo.GetPermissionByID(ctx, permissionID); err != nil {
+		return nil, err
+	}
+
+	// 新的資源與操作不可與其他權限重複
+	existing, err := s.repo.GetPermissionByResourceAction(ctx, permission.Resource, permission.Action)
+	if err != nil && !errors.Is(err, domain.ErrPermissionNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.ID != permissionID {
+		return nil, domain.ErrPermissionAlreadyExists
+	}
+
+	updateFields := map[string]interface{}{
+		"resource":    permission.Resource,
+		"action":      permission.Action,
+		"description": permission.Description,
+	}
+	if err := s.repo.UpdatePermission(ctx, permissionID, updateFields); err != nil {
+		return nil, err
+	}
+
+	// 重新獲取更新後的權限信息
+	return s.repo.GetPermissionByID(ctx, permissionID)
+}
+
+// DeletePermission 刪除權限，同時移除其角色分配
+func (s *PermissionService) DeletePermission(ctx context.Context, id string) error {
+	permissionID, err := parseID(id, domain.ErrInvalidPermissionID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeletePermission(ctx, permissionID)
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"rbac-service/domain"
+)
+
+// MockPermissionRepository 模擬 PermissionRepository
+type MockPermissionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPermissionRepository) GetPermissionByID(ctx context.Context, id int64) (*domain.Permission, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) GetPermissionByResourceAction(ctx context.Context, resource, action string) (*domain.Permission, error) {
+	args := m.Called(ctx, resource, action)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) ListPermissions(ctx context.Context, resourcePrefix string) ([]domain.Permission, error) {
+	args := m.Called(ctx, resourcePrefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) CreatePermission(ctx context.Context, permission *domain.Permission) (*domain.Permission, error) {
+	args := m.Called(ctx, permission)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) UpdatePermission(ctx context.Context, id int64, updates map[string]interface{}) error {
+	args := m.Called(ctx, id, updates)
+	return args.Error(0)
+}
+
+func (m *MockPermissionRepository) DeletePermission(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestPermissionService_CreatePermission_Successful(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockPermissionRepository)
+	permissionService := NewPermissionService(mockRepo)
+
+	permission := &domain.Permission{Resource: "notice", Action: "publish"}
+	created := &domain.Permission{ID: 1, Resource: "notice", Action: "publish"}
+
+	// 設定模擬行為
+	mockRepo.On("GetPermissionByResourceAction", mock.Anything, "notice", "publish").Return(nil, domain.ErrPermissionNotFound)
+	mockRepo.On("CreatePermission", mock.Anything, permission).Return(created, nil)
+
+	// 執行創建權限
+	result, err := permissionService.CreatePermission(context.Background(), permission)
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, created, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPermissionService_CreatePermission_Duplicate(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockPermissionRepository)
+	permissionService := NewPermissionService(mockRepo)
+
+	// 設定模擬行為
+	mockRepo.On("GetPermissionByResourceAction", mock.Anything, "notice", "publish").
+		Return(&domain.Permission{ID: 1, Resource: "notice", Action: "publish"}, nil)
+
+	// 執行創建權限
+	result, err := permissionService.CreatePermission(context.Background(), &domain.Permission{Resource: "notice", Action: "publish"})
+
+	// 斷言
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrPermissionAlreadyExists, err)
+	mockRepo.AssertNotCalled(t, "CreatePermission", mock.Anything, mock.Anything)
+}
+
+func TestPermissionService_CreatePermission_InvalidFields(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockPermissionRepository)
+	permissionService := NewPermissionService(mockRepo)
+
+	invalid := []*domain.Permission{
+		{Resource: "", Action: "view"},
+		{Resource: "notice", Action: ""},
+		{Resource: "notice:view", Action: "view"},
+		{Resource: "notice", Action: "pub lish"},
+		{Resource: "game/", Action: "view"},
+		{Resource: "game*", Action: "view"},
+		{Resource: "notice", Action: "pub*"},
+	}
+
+	for _, permission := range invalid {
+		// 執行創建權限
+		result, err := permissionService.CreatePermission(context.Background(), permission)
+
+		// 斷言
+		assert.Nil(t, result)
+		assert.Equal(t, domain.ErrInvalidPermission, err)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPermissionService_CreatePermission_Wildcard(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockPermissionRepository)
+	permissionService := NewPermissionService(mockRepo)
+
+	permission := &domain.Permission{Resource: "game/*", Action: "*"}
+	created := &domain.Permission{ID: 1, Resource: "game/*", Action: "*"}
+
+	// 設定模擬行為
+	mockRepo.On("GetPermissionByResourceAction", mock.Anything, "game/*", "*").Return(nil, domain.ErrPermissionNotFound)
+	mockRepo.On("CreatePermission", mock.Anything, permission).Return(created, nil)
+
+	// 執行創建權限
+	result, err := permissionService.CreatePermission(context.Background(), permission)
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, created, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPermissionService_ListPermissions_ResourcePrefix(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockPermissionRepository)
+	permissionService := NewPermissionService(mockRepo)
+
+	expected := []domain.Permission{
+		{ID: 1, Resource: "notice", Action: "publish"},
+		{ID: 2, Resource: "notice", Action: "view"},
+	}
+
+	// 設定模擬行為
+	mockRepo.On("ListPermissions", mock.Anything, "notice").Return(expected, nil)
+
+	// 執行列出權限
+	result, err := permissionService.ListPermissions(context.Background(), " notice ")
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPermissionService_UpdatePermission_ConflictWithAnother(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockPermissionRepository)
+	permissionService := NewPermissionService(mockRepo)
+
+	// 設定模擬行為
+	mockRepo.On("GetPermissionByID", mock.Anything, int64(2)).Return(&domain.Permission{ID: 2, Resource: "notice", Action: "edit"}, nil)
+	mockRepo.On("GetPermissionByResourceAction", mock.Anything, "notice", "view").
+		Return(&domain.Permission{ID: 1, Resource: "notice", Action: "view"}, nil)
+
+	// 執行更新權限
+	result, err := permissionService.UpdatePermission(context.Background(), "2", &domain.Permission{Resource: "notice", Action: "view"})
+
+	// 斷言
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrPermissionAlreadyExists, err)
+	mockRepo.AssertNotCalled(t, "UpdatePermission", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPermissionService_DeletePermission_InvalidID(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockPermissionRepository)
+	permissionService := NewPermissionService(mockRepo)
+
+	// 執行刪除權限
+	err := permissionService.DeletePermission(context.Background(), "-1")
+
+	// 斷言
+	assert.Equal(t, domain.ErrInvalidPermissionID, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"rbac-service/domain"
+	"rbac-service/infrastructure/utils"
+)
+
+// revocationList 記憶體中的撤銷清單，stateless 驗證模式以此取代每個請求的資料庫查詢。
+// 撤銷不會被取消，因此同步時只合併新記錄並移除已過期的項目
+type revocationList struct {
+	mu sync.RWMutex
+	// tokens jti 對應令牌的過期時間
+	tokens map[string]time.Time
+	// users 用戶名稱對應撤銷時間點與記錄的過期時間
+	users map[string]domain.UserTokenRevocation
+	// sessions 會話 ID 對應記錄的過期時間
+	sessions map[string]time.Time
+}
+
+func newRevocationList() *revocationList {
+	return &revocationList{
+		tokens:   map[string]time.Time{},
+		users:    map[string]domain.UserTokenRevocation{},
+		sessions: map[string]time.Time{},
+	}
+}
+
+// merge 合併資料庫中的撤銷記錄，並移除已過期的項目
+func (l *revocationList) merge(revocations *domain.ActiveRevocations, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, token := range revocations.Tokens {
+		l.tokens[token.JTI] = token.ExpiresAt
+	}
+	for _, user := range revocations.Users {
+		l.addUserLocked(user)
+	}
+	for _, session := range revocations.Sessions {
+		l.sessions[session.SessionID] = session.ExpiresAt
+	}
+
+	for jti, expiresAt := range l.tokens {
+		if !expiresAt.After(now) {
+			delete(l.tokens, jti)
+		}
+	}
+	for username, user := range l.users {
+		if !user.ExpiresAt.After(now) {
+			delete(l.users, username)
+		}
+	}
+	for id, expiresAt := range l.sessions {
+		if !expiresAt.After(now) {
+			delete(l.sessions, id)
+		}
+	}
+}
+
+// addToken 記錄本實例撤銷的令牌，不需等待下次同步
+func (l *revocationList) addToken(token *domain.RevokedToken) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens[token.JTI] = token.ExpiresAt
+}
+
+// addUser 記錄本實例的用戶層級撤銷
+func (l *revocationList) addUser(revocation *domain.UserTokenRevocation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.addUserLocked(*revocation)
+}
+
+// addUserLocked 保留較晚的撤銷時間點
+func (l *revocationList) addUserLocked(revocation domain.UserTokenRevocation) {
+	if existing, ok := l.users[revocation.Username]; ok && existing.RevokedBefore.After(revocation.RevokedBefore) {
+		return
+	}
+	l.users[revocation.Username] = revocation
+}
+
+// addSessions 記錄本實例終止的會話
+func (l *revocationList) addSessions(sessionIDs []string, expiresAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range sessionIDs {
+		l.sessions[id] = expiresAt
+	}
+}
+
+// isRevoked 檢查令牌的 jti、所屬用戶或會話是否已被撤銷
+func (l *revocationList) isRevoked(claims *utils.Claims, now time.Time) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if expiresAt, ok := l.tokens[claims.ID]; ok && expiresAt.After(now) {
+		return true
+	}
+	if expiresAt, ok := l.sessions[claims.SessionID]; ok && expiresAt.After(now) {
+		return true
+	}
+	if user, ok := l.users[claims.Username]; ok && user.ExpiresAt.After(now) {
+		// 與資料庫的比對一致：簽發時間不晚於撤銷時間點即失效
+		return claims.IssuedAt == nil || !claims.IssuedAt.Time.After(user.RevokedBefore)
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"rbac-service/domain"
+	"rbac-service/infrastructure/utils"
+)
+
+func TestRevocationList_MergePrunesExpired(t *testing.T) {
+	now := time.Now()
+	list := newRevocationList()
+	list.addToken(&domain.RevokedToken{JTI: "expired", ExpiresAt: now.Add(-time.Second)})
+	list.addSessions([]string{"session-1"}, now.Add(time.Hour))
+
+	// 合併不會移除本機新增但尚未寫入快照的記錄，只移除已過期的項目
+	list.merge(&domain.ActiveRevocations{
+		Tokens: []domain.RevokedToken{{JTI: "jti-1", ExpiresAt: now.Add(time.Hour)}},
+	}, now)
+
+	assert.NotContains(t, list.tokens, "expired")
+	assert.Contains(t, list.tokens, "jti-1")
+	assert.Contains(t, list.sessions, "session-1")
+}
+
+func TestRevocationList_KeepsLatestUserRevocation(t *testing.T) {
+	now := time.Now()
+	list := newRevocationList()
+	list.addUser(&domain.UserTokenRevocation{Username: "testuser", RevokedBefore: now, ExpiresAt: now.Add(time.Hour)})
+
+	// 較舊的快照不會覆蓋較新的撤銷時間點
+	list.merge(&domain.ActiveRevocations{Users: []domain.UserTokenRevocation{
+		{Username: "testuser", RevokedBefore: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+	}}, now)
+
+	claims := &utils.Claims{Username: "testuser"}
+	claims.IssuedAt = jwt.NewNumericDate(now.Add(-time.Minute))
+	assert.True(t, list.isRevoked(claims, now))
+}
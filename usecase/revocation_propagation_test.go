@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rbac-service/domain"
+	"rbac-service/infrastructure/pubsub"
+	"rbac-service/infrastructure/utils"
+)
+
+// sharedRevocationRepository 多個實例共用的記憶體撤銷記錄，模擬同一個資料庫
+type sharedRevocationRepository struct {
+	domain.TokenRevocationRepository
+	mu          sync.Mutex
+	revocations domain.ActiveRevocations
+}
+
+func (r *sharedRevocationRepository) RevokeToken(ctx context.Context, token *domain.RevokedToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revocations.Tokens = append(r.revocations.Tokens, *token)
+	return nil
+}
+
+func (r *sharedRevocationRepository) RevokeUserTokens(ctx context.Context, revocation *domain.UserTokenRevocation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revocations.Users = append(r.revocations.Users, *revocation)
+	return nil
+}
+
+func (r *sharedRevocationRepository) RevokeSessions(ctx context.Context, sessionIDs []string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range sessionIDs {
+		r.revocations.Sessions = append(r.revocations.Sessions, domain.RevokedSession{SessionID: id, ExpiresAt: expiresAt})
+	}
+	return nil
+}
+
+func (r *sharedRevocationRepository) ListActiveRevocations(ctx context.Context, now time.Time) (*domain.ActiveRevocations, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &domain.ActiveRevocations{
+		Tokens:   append([]domain.RevokedToken(nil), r.revocations.Tokens...),
+		Users:    append([]domain.UserTokenRevocation(nil), r.revocations.Users...),
+		Sessions: append([]domain.RevokedSession(nil), r.revocations.Sessions...),
+	}, nil
+}
+
+// propagationUserRepository 只提供撤銷用戶令牌所需的查詢
+type propagationUserRepository struct {
+	domain.AuthRepository
+}
+
+func (r *propagationUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	userID, _ := strconv.ParseInt(id, 10, 64)
+	return &domain.User{ID: userID, Username: "testuser"}, nil
+}
+
+// propagationDeletedUserRepository 刪除用戶時將撤銷記錄寫入共用的撤銷記錄，其餘方法不會被呼叫
+type propagationDeletedUserRepository struct {
+	domain.UserRepository
+	revocations *sharedRevocationRepository
+}
+
+func (r *propagationDeletedUserRepository) DeleteUser(ctx context.Context, username string, revocation *domain.UserTokenRevocation) error {
+	return r.revocations.RevokeUserTokens(ctx, revocation)
+}
+
+// propagationSessionRepository 所有會話都屬於同一位用戶，其餘方法不會被呼叫
+type propagationSessionRepository struct {
+	domain.SessionRepository
+}
+
+func (r *propagationSessionRepository) GetSessionByID(ctx context.Context, id string) (*domain.Session, error) {
+	return &domain.Session{ID: id, UserID: 1}, nil
+}
+
+func (r *propagationSessionRepository) DeleteUserSessions(ctx context.Context, userID int64) error {
+	return nil
+}
+
+func (r *propagationSessionRepository) DeleteSession(ctx context.Context, id string) error {
+	return nil
+}
+
+// propagationRefreshTokenRepository 撤銷刷新令牌，其餘方法不會被呼叫
+type propagationRefreshTokenRepository struct {
+	domain.RefreshTokenRepository
+}
+
+func (r *propagationRefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID int64) error {
+	return nil
+}
+
+func (r *propagationRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	return nil
+}
+
+// newPropagationInstance 建立一個 stateless 模式的實例並訂閱撤銷傳播
+func newPropagationInstance(t *testing.T, ctx context.Context, repo domain.TokenRevocationRepository, pollInterval time.Duration, redisBus domain.RevocationBus) *AuthService {
+	buses := []domain.RevocationBus{pubsub.NewPollingRevocationBus(repo, pollInterval)}
+	if redisBus != nil {
+		buses = append(buses, redisBus)
+	}
+
+	authService := NewAuthService(
+		&propagationUserRepository{},
+		&propagationRefreshTokenRepository{},
+		repo,
+		&propagationSessionRepository{},
+		0,
+		domain.ValidationModeStateless,
+		redisBus,
+	)
+	require.NoError(t, authService.ListenRevocations(ctx, buses...))
+	return authService
+}
+
+func TestRevocationPropagation_Redis(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := miniredis.RunT(t)
+	newBus := func() domain.RevocationBus {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+		return pubsub.NewRedisRevocationBus(client, "rbac:revocations")
+	}
+
+	// 資料庫查詢的間隔遠大於測試時間，撤銷只能經由 Redis 傳播
+	repo := &sharedRevocationRepository{}
+	instanceA := newPropagationInstance(t, ctx, repo, time.Hour, newBus())
+	instanceB := newPropagationInstance(t, ctx, repo, time.Hour, newBus())
+
+	tests := []struct {
+		name      string
+		sessionID string
+		revoke    func(token string) error
+	}{
+		{
+			name:      "token",
+			sessionID: "session-1",
+			revoke: func(token string) error {
+				_, err := instanceA.RevokeToken(ctx, "testuser", token, token)
+				return err
+			},
+		},
+		{
+			name:      "session",
+			sessionID: "session-2",
+			revoke: func(token string) error {
+				return instanceA.TerminateSession(ctx, "session-current", "session-2")
+			},
+		},
+		{
+			name:      "deleted user",
+			sessionID: "session-3",
+			revoke: func(token string) error {
+				userService := NewUserService(&propagationDeletedUserRepository{revocations: repo}, instanceA)
+				return userService.DeleteUser(ctx, &domain.User{ID: 1, Username: "testuser"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := utils.GenerateJWTToken(1, "testuser", tt.sessionID, nil)
+			require.NoError(t, err)
+			claims, err := utils.ParseJWTToken(token)
+			require.NoError(t, err)
+			require.NoError(t, instanceB.ValidateClaims(ctx, claims))
+
+			require.NoError(t, tt.revoke(token))
+
+			assert.Eventually(t, func() bool {
+				return instanceB.ValidateClaims(ctx, claims) != nil
+			}, time.Second, 10*time.Millisecond)
+			assert.ErrorIs(t, instanceB.ValidateClaims(ctx, claims), domain.ErrTokenRevoked)
+		})
+	}
+}
+
+func TestRevocationPropagation_PollingFallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 未使用 Redis 時，其他實例於下次查詢資料庫時取得撤銷記錄
+	repo := &sharedRevocationRepository{}
+	instanceA := newPropagationInstance(t, ctx, repo, 20*time.Millisecond, nil)
+	instanceB := newPropagationInstance(t, ctx, repo, 20*time.Millisecond, nil)
+
+	token, err := utils.GenerateJWTToken(1, "testuser", "session-1", nil)
+	require.NoError(t, err)
+	claims, err := utils.ParseJWTToken(token)
+	require.NoError(t, err)
+	require.NoError(t, instanceB.ValidateClaims(ctx, claims))
+
+	require.NoError(t, instanceA.RevokeUserTokens(ctx, "1"))
+	assert.ErrorIs(t, instanceA.ValidateClaims(ctx, claims), domain.ErrTokenRevoked)
+
+	assert.Eventually(t, func() bool {
+		return instanceB.ValidateClaims(ctx, claims) != nil
+	}, time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, instanceB.ValidateClaims(ctx, claims), domain.ErrTokenRevoked)
+}
@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"rbac-service/domain"
+)
+
+const (
+	// maxRoleNameLength 角色名稱最大長度，與 roles.name 欄位一致
+	maxRoleNameLength = 64
+	// maxDescriptionLength 描述最大長度，與 description 欄位一致
+	maxDescriptionLength = 255
+	// maxRoleParents 單一角色可直接繼承的父角色上限
+	maxRoleParents = 20
+)
+
+// RoleService 角色服務實作
+type RoleService struct {
+	repo domain.RoleRepository
+}
+
+// NewRoleService 創建角色服務
+func NewRoleService(repo domain.RoleRepository) *RoleService {
+	return &RoleService{repo: repo}
+}
+
+// parseID 解析路徑中的數字 ID
+func parseID(id string, invalidErr error) (int64, error) {
+	parsed, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+	if err != nil || parsed <= 0 {
+		return 0, invalidErr
+	}
+	return parsed, nil
+}
+
+// validateRole 清理並驗證角色輸入
+func validateRole(role *domain.Role) error {
+	role.Name = strings.TrimSpace(role.Name)
+	role.Description = strings.TrimSpace(role.Description)
+
+	if role.Name == "" || utf8.RuneCountInString(role.Name) > maxRoleNameLength {
+		return domain.ErrInvalidRoleName
+	}
+	if utf8.RuneCountInString(role.Description) > maxDescriptionLength {
+		return domain.ErrInvalidDescription
+	}
+	return nil
+}
+
+// GetRole 獲取角色信息
+func (s *RoleService) GetRole(ctx context.Context, id string) (*domain.Role, error) {
+	roleID, err := parseID(id, domain.ErrInvalidRoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetRoleByID(ctx, roleID)
+}
+
+// ListRoles 列出所有角色
+func (s *RoleService) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	return s.repo.ListRoles(ctx)
+}
+
+// CreateRole 創建角色，名稱不可重複
+func (s *RoleService) CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+
+	// 檢查名稱是否已存在
+	existing, err := s.repo.GetRoleByName(ctx, role.Name)
+	if err != nil && !errors.Is(err, domain.ErrRoleNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrRoleAlreadyExists
+	}
+
+	return s.repo.CreateRole(ctx, role)
+}
+
+// UpdateRole 更新角色名稱與描述
+func (s *RoleService) UpdateRole(ctx context.Context, id string, role *domain.Role) (*domain.Role, error) {
+	roleID, err := parseID(id, domain.ErrInvalidRoleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+
+	// 確認角色存在
+	if _, err := s.repo.GetRoleByID(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	// 新名稱不可與其他角色重複
+	existing, err := s.repo.GetRoleByName(ctx, role.Name)
+	if err != nil && !errors.Is(err, domain.ErrRoleNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.ID != roleID {
+		return nil, domain.ErrRoleAlreadyExists
+	}
+
+	updateFields := map[string]interface{}{
+		"name":        role.Name,
+		"description": role.Description,
+	}
+	if err := s.repo.UpdateRole(ctx, roleID, updateFields); err != nil {
+		return nil, err
+	}
+
+	// 重新獲取更新後的角色信息
+	return s.repo.GetRoleByID(ctx, roleID)
+}
+
+// DeleteRole 刪除角色；角色仍被分配給用戶時，必須指定 force 才會連同分配一併刪除
+func (s *RoleService) DeleteRole(ctx context.Context, id string, force bool) error {
+	roleID, err := parseID(id, domain.ErrInvalidRoleID)
+	if err != nil {
+		return err
+	}
+
+	// 確認角色存在
+	if _, err := s.repo.GetRoleByID(ctx, roleID); err != nil {
+		return err
+	}
+
+	if !force {
+		count, err := s.repo.CountRoleUsers(ctx, roleID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrRoleInUse
+		}
+	}
+
+	return s.repo.DeleteRole(ctx, roleID)
+}
+
+// SetRoleParents 設定角色直接繼承的父角色，取代原有設定；傳入空列表會移除所有繼承。
+// 父角色必須存在，且設定後不可形成循環
+func (s *RoleService) SetRoleParents(ctx context.Context, id string, parentIDs []int64) (*domain.Role, error) {
+	roleID, err := parseID(id, domain.ErrInvalidRoleID)
+	if err != nil {
+		return nil, err
+	}
+	if len(parentIDs) > maxRoleParents {
+		return nil, domain.ErrInvalidRoleParents
+	}
+
+	// 確認角色存在
+	if _, err := s.repo.GetRoleByID(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	unique := make([]int64, 0, len(parentIDs))
+	seen := make(map[int64]bool, len(parentIDs))
+	for _, parentID := range parentIDs {
+		if parentID <= 0 {
+			return nil, domain.ErrInvalidRoleParents
+		}
+		if parentID == roleID {
+			return nil, domain.ErrRoleHierarchyCycle
+		}
+		if seen[parentID] {
+			continue
+		}
+		seen[parentID] = true
+
+		if _, err := s.repo.GetRoleByID(ctx, parentID); err != nil {
+			return nil, err
+		}
+		unique = append(unique, parentID)
+	}
+
+	// 循環檢查在倉儲的交易中進行，與寫入之間不會有其他變更插入
+	if err := s.repo.SetRoleParents(ctx, roleID, unique); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetRoleByID(ctx, roleID)
+}
+
+// GetEffectivePermissions 獲取角色經由自身與所有祖先角色取得的有效權限，並標示每項權限的來源角色與繼承路徑
+func (s *RoleService) GetEffectivePermissions(ctx context.Context, id string) ([]domain.EffectivePermission, error) {
+	roleID, err := parseID(id, domain.ErrInvalidRoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 確認角色存在
+	if _, err := s.repo.GetRoleByID(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	edges, err := s.repo.ListRoleParents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 依廣度優先順序逐一載入祖先角色，較近的來源排在前面；同一權限的效果或條件不同時分開列出
+	type assignment struct {
+		id        int64
+		effect    string
+		condition string
+	}
+	names := map[int64]string{}
+	effective := []domain.EffectivePermission{}
+	index := map[assignment]int{}
+	for _, path := range domain.NewRoleHierarchy(edges).Paths(roleID) {
+		source, err := s.repo.GetRoleByID(ctx, path[len(path)-1])
+		if err != nil {
+			return nil, err
+		}
+		names[source.ID] = source.Name
+
+		pathNames := make([]string, 0, len(path))
+		for _, ancestorID := range path {
+			pathNames = append(pathNames, names[ancestorID])
+		}
+
+		for _, permission := range source.Permissions {
+			key := assignment{permission.ID, permission.Rule().Effect, permission.Condition}
+			i, ok := index[key]
+			if !ok {
+				i = len(effective)
+				index[key] = i
+				effective = append(effective, domain.EffectivePermission{Permission: permission})
+			}
+			effective[i].Sources = append(effective[i].Sources, domain.PermissionSource{
+				RoleID:   source.ID,
+				RoleName: source.Name,
+				Path:     pathNames,
+			})
+		}
+	}
+
+	sort.SliceStable(effective, func(i, j int) bool { return effective[i].ID < effective[j].ID })
+	return effective, nil
+}
@@ -0,0 +1,402 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"rbac-service/domain"
+)
+
+// MockRoleRepository 模擬 RoleRepository
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) GetRoleByID(ctx context.Context, id int64) (*domain.Role, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetRoleByName(ctx context.Context, name string) (*domain.Role, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	args := m.Called(ctx, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) UpdateRole(ctx context.Context, id int64, updates map[string]interface{}) error {
+	args := m.Called(ctx, id, updates)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) DeleteRole(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) CountRoleUsers(ctx context.Context, id int64) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRoleRepository) AssignRoleToUser(ctx context.Context, assignment *domain.UserRole) error {
+	args := m.Called(ctx, assignment)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) RemoveRoleFromUser(ctx context.Context, userID, roleID, tenantID int64) error {
+	args := m.Called(ctx, userID, roleID, tenantID)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) ListExpiringUserRoles(ctx context.Context, now, before time.Time) ([]domain.RoleAssignment, error) {
+	args := m.Called(ctx, now, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.RoleAssignment), args.Error(1)
+}
+
+func (m *MockRoleRepository) DeleteExpiredUserRoles(ctx context.Context, now time.Time) ([]domain.UserRole, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.UserRole), args.Error(1)
+}
+
+func (m *MockRoleRepository) AssignPermissionToRole(ctx context.Context, roleID, permissionID int64, effect, condition string) error {
+	args := m.Called(ctx, roleID, permissionID, effect, condition)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) AssignPermissionToUser(ctx context.Context, assignment *domain.UserPermission) error {
+	args := m.Called(ctx, assignment)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) RemovePermissionFromUser(ctx context.Context, userID, permissionID, tenantID int64) error {
+	args := m.Called(ctx, userID, permissionID, tenantID)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) RemovePermissionFromRole(ctx context.Context, roleID, permissionID int64) error {
+	args := m.Called(ctx, roleID, permissionID)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) ListRoleParents(ctx context.Context) ([]domain.RoleParent, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.RoleParent), args.Error(1)
+}
+
+func (m *MockRoleRepository) SetRoleParents(ctx context.Context, roleID int64, parentIDs []int64) error {
+	args := m.Called(ctx, roleID, parentIDs)
+	return args.Error(0)
+}
+
+func TestRoleService_CreateRole_Successful(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	role := &domain.Role{Name: " operator ", Description: "運營"}
+	created := &domain.Role{ID: 1, Name: "operator", Description: "運營"}
+
+	// 設定模擬行為
+	mockRepo.On("GetRoleByName", mock.Anything, "operator").Return(nil, domain.ErrRoleNotFound)
+	mockRepo.On("CreateRole", mock.Anything, role).Return(created, nil)
+
+	// 執行創建角色
+	result, err := roleService.CreateRole(context.Background(), role)
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, created, result)
+	assert.Equal(t, "operator", role.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRoleService_CreateRole_DuplicateName(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	// 設定模擬行為
+	mockRepo.On("GetRoleByName", mock.Anything, "admin").Return(&domain.Role{ID: 1, Name: "admin"}, nil)
+
+	// 執行創建角色
+	result, err := roleService.CreateRole(context.Background(), &domain.Role{Name: "admin"})
+
+	// 斷言
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrRoleAlreadyExists, err)
+	mockRepo.AssertNotCalled(t, "CreateRole", mock.Anything, mock.Anything)
+}
+
+func TestRoleService_CreateRole_EmptyName(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	// 執行創建角色
+	result, err := roleService.CreateRole(context.Background(), &domain.Role{Name: "   "})
+
+	// 斷言
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInvalidRoleName, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRoleService_GetRole_InvalidID(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	// 執行獲取角色
+	result, err := roleService.GetRole(context.Background(), "abc")
+
+	// 斷言
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInvalidRoleID, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRoleService_UpdateRole_NameTakenByAnotherRole(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	// 設定模擬行為
+	mockRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2, Name: "cs"}, nil)
+	mockRepo.On("GetRoleByName", mock.Anything, "admin").Return(&domain.Role{ID: 1, Name: "admin"}, nil)
+
+	// 執行更新角色
+	result, err := roleService.UpdateRole(context.Background(), "2", &domain.Role{Name: "admin"})
+
+	// 斷言
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrRoleAlreadyExists, err)
+	mockRepo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRoleService_UpdateRole_KeepSameName(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	updated := &domain.Role{ID: 2, Name: "cs", Description: "客服"}
+
+	// 設定模擬行為
+	mockRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2, Name: "cs"}, nil).Once()
+	mockRepo.On("GetRoleByName", mock.Anything, "cs").Return(&domain.Role{ID: 2, Name: "cs"}, nil)
+	mockRepo.On("UpdateRole", mock.Anything, int64(2), map[string]interface{}{
+		"name":        "cs",
+		"description": "客服",
+	}).Return(nil)
+	mockRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(updated, nil).Once()
+
+	// 執行更新角色
+	result, err := roleService.UpdateRole(context.Background(), "2", &domain.Role{Name: "cs", Description: "客服"})
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, updated, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRoleService_DeleteRole_StillAssigned(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	// 設定模擬行為
+	mockRepo.On("GetRoleByID", mock.Anything, int64(3)).Return(&domain.Role{ID: 3, Name: "cs"}, nil)
+	mockRepo.On("CountRoleUsers", mock.Anything, int64(3)).Return(int64(2), nil)
+
+	// 執行刪除角色
+	err := roleService.DeleteRole(context.Background(), "3", false)
+
+	// 斷言
+	assert.Equal(t, domain.ErrRoleInUse, err)
+	mockRepo.AssertNotCalled(t, "DeleteRole", mock.Anything, mock.Anything)
+}
+
+func TestRoleService_DeleteRole_Forced(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	// 設定模擬行為
+	mockRepo.On("GetRoleByID", mock.Anything, int64(3)).Return(&domain.Role{ID: 3, Name: "cs"}, nil)
+	mockRepo.On("DeleteRole", mock.Anything, int64(3)).Return(nil)
+
+	// 執行刪除角色
+	err := roleService.DeleteRole(context.Background(), "3", true)
+
+	// 斷言
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "CountRoleUsers", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRoleService_DeleteRole_NotFound(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	// 設定模擬行為
+	mockRepo.On("GetRoleByID", mock.Anything, int64(9)).Return(nil, domain.ErrRoleNotFound)
+
+	// 執行刪除角色
+	err := roleService.DeleteRole(context.Background(), "9", false)
+
+	// 斷言
+	assert.Equal(t, domain.ErrRoleNotFound, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRoleService_SetRoleParents_Successful(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	updated := &domain.Role{ID: 1, Name: "admin", Parents: []domain.Role{{ID: 2, Name: "operator"}}}
+
+	// 設定模擬行為：重複的父角色只寫入一次
+	mockRepo.On("GetRoleByID", mock.Anything, int64(1)).Return(&domain.Role{ID: 1, Name: "admin"}, nil).Once()
+	mockRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2, Name: "operator"}, nil).Once()
+	mockRepo.On("SetRoleParents", mock.Anything, int64(1), []int64{2}).Return(nil)
+	mockRepo.On("GetRoleByID", mock.Anything, int64(1)).Return(updated, nil).Once()
+
+	// 執行設定父角色
+	result, err := roleService.SetRoleParents(context.Background(), "1", []int64{2, 2})
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, updated, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRoleService_SetRoleParents_Rejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		parentIDs []int64
+		setup     func(mockRepo *MockRoleRepository)
+		wantErr   error
+	}{
+		{
+			name:      "繼承自身",
+			parentIDs: []int64{1},
+			wantErr:   domain.ErrRoleHierarchyCycle,
+		},
+		{
+			name:      "無效的父角色ID",
+			parentIDs: []int64{0},
+			wantErr:   domain.ErrInvalidRoleParents,
+		},
+		{
+			name:      "父角色不存在",
+			parentIDs: []int64{9},
+			setup: func(mockRepo *MockRoleRepository) {
+				mockRepo.On("GetRoleByID", mock.Anything, int64(9)).Return(nil, domain.ErrRoleNotFound)
+			},
+			wantErr: domain.ErrRoleNotFound,
+		},
+		{
+			name:      "經由祖先形成循環",
+			parentIDs: []int64{3},
+			setup: func(mockRepo *MockRoleRepository) {
+				mockRepo.On("GetRoleByID", mock.Anything, int64(3)).Return(&domain.Role{ID: 3, Name: "cs"}, nil)
+				mockRepo.On("SetRoleParents", mock.Anything, int64(1), []int64{3}).Return(domain.ErrRoleHierarchyCycle)
+			},
+			wantErr: domain.ErrRoleHierarchyCycle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 準備測試數據
+			mockRepo := new(MockRoleRepository)
+			roleService := NewRoleService(mockRepo)
+			mockRepo.On("GetRoleByID", mock.Anything, int64(1)).Return(&domain.Role{ID: 1, Name: "admin"}, nil)
+			if tt.setup != nil {
+				tt.setup(mockRepo)
+			}
+
+			// 執行設定父角色
+			result, err := roleService.SetRoleParents(context.Background(), "1", tt.parentIDs)
+
+			// 斷言
+			assert.Nil(t, result)
+			assert.Equal(t, tt.wantErr, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoleService_GetEffectivePermissions(t *testing.T) {
+	// 準備測試數據：admin(1) 繼承 operator(2) 與 cs(3)，operator 也繼承 cs
+	mockRepo := new(MockRoleRepository)
+	roleService := NewRoleService(mockRepo)
+
+	userView := domain.Permission{ID: 1, Resource: "user", Action: "view"}
+	noticeView := domain.Permission{ID: 2, Resource: "notice", Action: "view"}
+	noticePublish := domain.Permission{ID: 3, Resource: "notice", Action: "publish"}
+
+	// 設定模擬行為
+	mockRepo.On("GetRoleByID", mock.Anything, int64(1)).Return(&domain.Role{ID: 1, Name: "admin", Permissions: []domain.Permission{noticePublish}}, nil)
+	mockRepo.On("GetRoleByID", mock.Anything, int64(2)).Return(&domain.Role{ID: 2, Name: "operator", Permissions: []domain.Permission{noticePublish, noticeView}}, nil)
+	mockRepo.On("GetRoleByID", mock.Anything, int64(3)).Return(&domain.Role{ID: 3, Name: "cs", Permissions: []domain.Permission{userView}}, nil)
+	mockRepo.On("ListRoleParents", mock.Anything).Return([]domain.RoleParent{
+		{RoleID: 1, ParentID: 3},
+		{RoleID: 1, ParentID: 2},
+		{RoleID: 2, ParentID: 3},
+	}, nil)
+
+	// 執行獲取有效權限
+	permissions, err := roleService.GetEffectivePermissions(context.Background(), "1")
+
+	// 斷言：依權限 ID 排序，cs 經由最短路徑直接繼承
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.EffectivePermission{
+		{Permission: userView, Sources: []domain.PermissionSource{
+			{RoleID: 3, RoleName: "cs", Path: []string{"admin", "cs"}},
+		}},
+		{Permission: noticeView, Sources: []domain.PermissionSource{
+			{RoleID: 2, RoleName: "operator", Path: []string{"admin", "operator"}},
+		}},
+		{Permission: noticePublish, Sources: []domain.PermissionSource{
+			{RoleID: 1, RoleName: "admin", Path: []string{"admin"}},
+			{RoleID: 2, RoleName: "operator", Path: []string{"admin", "operator"}},
+		}},
+	}, permissions)
+	mockRepo.AssertExpectations(t)
+}
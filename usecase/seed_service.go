@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"rbac-service/domain"
+)
+
+// SeedService 將設定檔中的角色與權限同步至資料庫，並可為指定用戶建立初始的角色分配
+type SeedService struct {
+	roleRepo       domain.RoleRepository
+	permissionRepo domain.PermissionRepository
+	authRepo       domain.AuthRepository
+}
+
+// NewSeedService 創建種子資料服務
+func NewSeedService(roleRepo domain.RoleRepository, permissionRepo domain.PermissionRepository, authRepo domain.AuthRepository) *SeedService {
+	return &SeedService{
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		authRepo:       authRepo,
+	}
+}
+
+// validateSeeds 在寫入前檢查整份設定，避免同步到一半才失敗
+func validateSeeds(seeds []domain.RoleSeed) error {
+	for _, seed := range seeds {
+		role := &domain.Role{Name: seed.Role, Description: seed.Name}
+		if err := validateRole(role); err != nil {
+			return fmt.Errorf("角色 %q: %w", seed.Role, err)
+		}
+
+		for _, key := range seed.Permissions {
+			resource, action, err := domain.ParsePermissionKey(key)
+			if err != nil {
+				return fmt.Errorf("角色 %q 的權限 %q: %w", seed.Role, key, err)
+			}
+			if err := validatePermission(&domain.Permission{Resource: resource, Action: action}); err != nil {
+				return fmt.Errorf("角色 %q 的權限 %q: %w", seed.Role, key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Seed 冪等地新增或更新設定中的角色與權限，並補上缺少的角色權限分配。
+// 執行期間透過 API 新增的分配預設保留，prune 為 true 時才移除設定中未列出的分配。
+func (s *SeedService) Seed(ctx context.Context, seeds []domain.RoleSeed, prune bool) (*domain.SeedReport, error) {
+	if err := validateSeeds(seeds); err != nil {
+		return nil, err
+	}
+
+	report := &domain.SeedReport{
+		Added:     []string{},
+		Changed:   []string{},
+		Unchanged: []string{},
+		Removed:   []string{},
+	}
+
+	// 1. 確保所有權限存在
+	permissions := make(map[string]*domain.Permission)
+	for _, seed := range seeds {
+		for _, key := range seed.Permissions {
+			key = strings.TrimSpace(key)
+			if _, ok := permissions[key]; ok {
+				continue
+			}
+			permission, err := s.ensurePermission(ctx, key, report)
+			if err != nil {
+				return report, err
+			}
+			permissions[key] = permission
+		}
+	}
+
+	// 2. 確保所有角色存在並同步權限分配
+	for _, seed := range seeds {
+		role, err := s.ensureRole(ctx, seed, report)
+		if err != nil {
+			return report, err
+		}
+		if err := s.syncRolePermissions(ctx, role, seed, permissions, prune, report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// AssignGlobalRole 冪等地將角色以全域、永久有效的分配給予指定的用戶，用於部署後指派第一位管理員。
+// 用戶已擁有該角色的全域分配時列為未變動；角色須已存在，因此應在 Seed 之後呼叫
+func (s *SeedService) AssignGlobalRole(ctx context.Context, roleName string, usernames []string) (*domain.SeedReport, error) {
+	role, err := s.roleRepo.GetRoleByName(ctx, strings.TrimSpace(roleName))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.SeedReport{
+		Added:     []string{},
+		Changed:   []string{},
+		Unchanged: []string{},
+		Removed:   []string{},
+	}
+	for _, username := range usernames {
+		username = strings.TrimSpace(username)
+		if username == "" {
+			continue
+		}
+		user, err := s.authRepo.GetByUsername(ctx, username)
+		if err != nil {
+			return report, fmt.Errorf("用戶 %q: %w", username, err)
+		}
+
+		label := fmt.Sprintf("user %s -> %s", user.Username, role.Name)
+		err = s.roleRepo.AssignRoleToUser(ctx, &domain.UserRole{UserID: user.ID, RoleID: role.ID, TenantID: domain.GlobalTenantID})
+		if errors.Is(err, domain.ErrRoleAlreadyAssigned) {
+			report.Unchanged = append(report.Unchanged, label)
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+		report.Added = append(report.Added, label)
+	}
+	return report, nil
+}
+
+// ensurePermission 權限不存在時新增
+func (s *SeedService) ensurePermission(ctx context.Context, key string, report *domain.SeedReport) (*domain.Permission, error) {
+	// 格式已於 validateSeeds 驗證
+	resource, action, _ := domain.ParsePermissionKey(key)
+	label := "permission " + key
+
+	existing, err := s.permissionRepo.GetPermissionByResourceAction(ctx, resource, action)
+	if err == nil {
+		report.Unchanged = append(report.Unchanged, label)
+		return existing, nil
+	}
+	if !errors.Is(err, domain.ErrPermissionNotFound) {
+		return nil, err
+	}
+
+	created, err := s.permissionRepo.CreatePermission(ctx, &domain.Permission{Resource: resource, Action: action})
+	if err != nil {
+		return nil, err
+	}
+	report.Added = append(report.Added, label)
+	return created, nil
+}
+
+// ensureRole 角色不存在時新增，描述與設定不同時更新
+func (s *SeedService) ensureRole(ctx context.Context, seed domain.RoleSeed, report *domain.SeedReport) (*domain.Role, error) {
+	desired := &domain.Role{
+		Name:        strings.TrimSpace(seed.Role),
+		Description: strings.TrimSpace(seed.Name),
+	}
+	label := "role " + desired.Name
+
+	existing, err := s.roleRepo.GetRoleByName(ctx, desired.Name)
+	if err != nil && !errors.Is(err, domain.ErrRoleNotFound) {
+		return nil, err
+	}
+	if existing == nil {
+		created, err := s.roleRepo.CreateRole(ctx, desired)
+		if err != nil {
+			return nil, err
+		}
+		report.Added = append(report.Added, label)
+		return created, nil
+	}
+
+	if existing.Description != desired.Description {
+		err := s.roleRepo.UpdateRole(ctx, existing.ID, map[string]interface{}{
+			"description": desired.Description,
+		})
+		if err != nil {
+			return nil, err
+		}
+		report.Changed = append(report.Changed, label)
+	} else {
+		report.Unchanged = append(report.Unchanged, label)
+	}
+	return existing, nil
+}
+
+// syncRolePermissions 補上設定中列出但尚未分配的權限，prune 時移除多餘的分配
+func (s *SeedService) syncRolePermissions(
+	ctx context.Context,
+	role *domain.Role,
+	seed domain.RoleSeed,
+	permissions map[string]*domain.Permission,
+	prune bool,
+	report *domain.SeedReport,
+) error {
+	// 重新讀取角色以取得目前的權限分配
+	current, err := s.roleRepo.GetRoleByID(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	assigned := make(map[int64]bool, len(current.Permissions))
+	for _, permission := range current.Permissions {
+		assigned[permission.ID] = true
+	}
+
+	desired := make(map[int64]bool, len(seed.Permissions))
+	for _, key := range seed.Permissions {
+		key = strings.TrimSpace(key)
+		permission := permissions[key]
+		if desired[permission.ID] {
+			continue
+		}
+		desired[permission.ID] = true
+
+		label := fmt.Sprintf("role %s -> %s", role.Name, key)
+		if assigned[permission.ID] {
+			report.Unchanged = append(report.Unchanged, label)
+			continue
+		}
+		if err := s.roleRepo.AssignPermissionToRole(ctx, role.ID, permission.ID, domain.EffectAllow, ""); err != nil {
+			return err
+		}
+		report.Added = append(report.Added, label)
+	}
+
+	if !prune {
+		return nil
+	}
+	for _, permission := range current.Permissions {
+		if desired[permission.ID] {
+			continue
+		}
+		if err := s.roleRepo.RemovePermissionFromRole(ctx, role.ID, permission.ID); err != nil {
+			return err
+		}
+		report.Removed = append(report.Removed, fmt.Sprintf("role %s -> %s", role.Name, permission.Key()))
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"rbac-service/domain"
+)
+
+func TestSeedService_Seed_FreshDatabase(t *testing.T) {
+	// 準備測試數據
+	roleRepo := new(MockRoleRepository)
+	permissionRepo := new(MockPermissionRepository)
+	seedService := NewSeedService(roleRepo, permissionRepo, new(MockAuthRepository))
+
+	seeds := []domain.RoleSeed{
+		{Role: "cs", Name: "客服", Permissions: []string{"user:view", "notice:view"}},
+	}
+
+	// 設定模擬行為
+	permissionRepo.On("GetPermissionByResourceAction", mock.Anything, "user", "view").Return(nil, domain.ErrPermissionNotFound)
+	permissionRepo.On("GetPermissionByResourceAction", mock.Anything, "notice", "view").Return(nil, domain.ErrPermissionNotFound)
+	permissionRepo.On("CreatePermission", mock.Anything, &domain.Permission{Resource: "user", Action: "view"}).
+		Return(&domain.Permission{ID: 1, Resource: "user", Action: "view"}, nil)
+	permissionRepo.On("CreatePermission", mock.Anything, &domain.Permission{Resource: "notice", Action: "view"}).
+		Return(&domain.Permission{ID: 2, Resource: "notice", Action: "view"}, nil)
+	roleRepo.On("GetRoleByName", mock.Anything, "cs").Return(nil, domain.ErrRoleNotFound)
+	roleRepo.On("CreateRole", mock.Anything, &domain.Role{Name: "cs", Description: "客服"}).
+		Return(&domain.Role{ID: 3, Name: "cs", Description: "客服"}, nil)
+	roleRepo.On("GetRoleByID", mock.Anything, int64(3)).Return(&domain.Role{ID: 3, Name: "cs"}, nil)
+	roleRepo.On("AssignPermissionToRole", mock.Anything, int64(3), int64(1), domain.EffectAllow, "").Return(nil)
+	roleRepo.On("AssignPermissionToRole", mock.Anything, int64(3), int64(2), domain.EffectAllow, "").Return(nil)
+
+	// 執行同步
+	report, err := seedService.Seed(context.Background(), seeds, false)
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"permission user:view",
+		"permission notice:view",
+		"role cs",
+		"role cs -> user:view",
+		"role cs -> notice:view",
+	}, report.Added)
+	assert.Empty(t, report.Changed)
+	assert.Empty(t, report.Unchanged)
+	assert.Empty(t, report.Removed)
+	roleRepo.AssertExpectations(t)
+	permissionRepo.AssertExpectations(t)
+}
+
+func TestSeedService_Seed_KeepsRuntimeAssignments(t *testing.T) {
+	// 準備測試數據
+	roleRepo := new(MockRoleRepository)
+	permissionRepo := new(MockPermissionRepository)
+	seedService := NewSeedService(roleRepo, permissionRepo, new(MockAuthRepository))
+
+	seeds := []domain.RoleSeed{
+		{Role: "cs", Name: "客服人員", Permissions: []string{"user:view"}},
+	}
+	userView := domain.Permission{ID: 1, Resource: "user", Action: "view"}
+	logExport := domain.Permission{ID: 9, Resource: "log", Action: "export"}
+
+	// 設定模擬行為：權限與角色都已存在，角色另有執行期間分配的 log:export
+	permissionRepo.On("GetPermissionByResourceAction", mock.Anything, "user", "view").Return(&userView, nil)
+	roleRepo.On("GetRoleByName", mock.Anything, "cs").Return(&domain.Role{ID: 3, Name: "cs", Description: "客服"}, nil)
+	roleRepo.On("UpdateRole", mock.Anything, int64(3), map[string]interface{}{"description": "客服人員"}).Return(nil)
+	roleRepo.On("GetRoleByID", mock.Anything, int64(3)).
+		Return(&domain.Role{ID: 3, Name: "cs", Permissions: []domain.Permission{userView, logExport}}, nil)
+
+	// 執行同步
+	report, err := seedService.Seed(context.Background(), seeds, false)
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Empty(t, report.Added)
+	assert.Equal(t, []string{"role cs"}, report.Changed)
+	assert.Equal(t, []string{"permission user:view", "role cs -> user:view"}, report.Unchanged)
+	assert.Empty(t, report.Removed)
+	roleRepo.AssertNotCalled(t, "RemovePermissionFromRole", mock.Anything, mock.Anything, mock.Anything)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestSeedService_Seed_PruneRemovesUnlistedAssignments(t *testing.T) {
+	// 準備測試數據
+	roleRepo := new(MockRoleRepository)
+	permissionRepo := new(MockPermissionRepository)
+	seedService := NewSeedService(roleRepo, permissionRepo, new(MockAuthRepository))
+
+	seeds := []domain.RoleSeed{
+		{Role: "cs", Name: "客服", Permissions: []string{"user:view"}},
+	}
+	userView := domain.Permission{ID: 1, Resource: "user", Action: "view"}
+	logExport := domain.Permission{ID: 9, Resource: "log", Action: "export"}
+
+	// 設定模擬行為
+	permissionRepo.On("GetPermissionByResourceAction", mock.Anything, "user", "view").Return(&userView, nil)
+	roleRepo.On("GetRoleByName", mock.Anything, "cs").Return(&domain.Role{ID: 3, Name: "cs", Description: "客服"}, nil)
+	roleRepo.On("GetRoleByID", mock.Anything, int64(3)).
+		Return(&domain.Role{ID: 3, Name: "cs", Permissions: []domain.Permission{userView, logExport}}, nil)
+	roleRepo.On("RemovePermissionFromRole", mock.Anything, int64(3), int64(9)).Return(nil)
+
+	// 執行同步
+	report, err := seedService.Seed(context.Background(), seeds, true)
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"role cs -> log:export"}, report.Removed)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestSeedService_Seed_InvalidPermissionKey(t *testing.T) {
+	// 準備測試數據
+	roleRepo := new(MockRoleRepository)
+	permissionRepo := new(MockPermissionRepository)
+	seedService := NewSeedService(roleRepo, permissionRepo, new(MockAuthRepository))
+
+	seeds := []domain.RoleSeed{
+		{Role: "cs", Name: "客服", Permissions: []string{"user:view", "broken"}},
+	}
+
+	// 執行同步
+	report, err := seedService.Seed(context.Background(), seeds, false)
+
+	// 斷言：格式錯誤時不寫入任何資料
+	assert.Nil(t, report)
+	assert.ErrorIs(t, err, domain.ErrInvalidPermission)
+	roleRepo.AssertExpectations(t)
+	permissionRepo.AssertExpectations(t)
+}
+
+func TestSeedService_AssignGlobalRole(t *testing.T) {
+	// 準備測試數據
+	roleRepo := new(MockRoleRepository)
+	authRepo := new(MockAuthRepository)
+	seedService := NewSeedService(roleRepo, new(MockPermissionRepository), authRepo)
+
+	// 設定模擬行為：admin 尚未分配，jared 已擁有全域分配
+	roleRepo.On("GetRoleByName", mock.Anything, "admin").Return(&domain.Role{ID: 1, Name: "admin"}, nil)
+	authRepo.On("GetByUsername", mock.Anything, "admin").Return(&domain.User{ID: 1, Username: "admin"}, nil)
+	authRepo.On("GetByUsername", mock.Anything, "jared").Return(&domain.User{ID: 2, Username: "jared"}, nil)
+	roleRepo.On("AssignRoleToUser", mock.Anything, &domain.UserRole{UserID: 1, RoleID: 1, TenantID: domain.GlobalTenantID}).Return(nil)
+	roleRepo.On("AssignRoleToUser", mock.Anything, &domain.UserRole{UserID: 2, RoleID: 1, TenantID: domain.GlobalTenantID}).Return(domain.ErrRoleAlreadyAssigned)
+
+	// 執行分配
+	report, err := seedService.AssignGlobalRole(context.Background(), "admin", []string{"admin", " jared ", ""})
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user admin -> admin"}, report.Added)
+	assert.Equal(t, []string{"user jared -> admin"}, report.Unchanged)
+	roleRepo.AssertExpectations(t)
+	authRepo.AssertExpectations(t)
+}
+
+func TestSeedService_AssignGlobalRole_UserNotFound(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	authRepo := new(MockAuthRepository)
+	seedService := NewSeedService(roleRepo, new(MockPermissionRepository), authRepo)
+
+	roleRepo.On("GetRoleByName", mock.Anything, "admin").Return(&domain.Role{ID: 1, Name: "admin"}, nil)
+	authRepo.On("GetByUsername", mock.Anything, "nobody").Return(nil, domain.ErrUserNotFound)
+
+	_, err := seedService.AssignGlobalRole(context.Background(), "admin", []string{"nobody"})
+
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	roleRepo.AssertNotCalled(t, "AssignRoleToUser", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"rbac-service/domain"
+)
+
+// TenantService 租戶與成員管理
+type TenantService struct {
+	tenantRepo domain.TenantRepository
+	authRepo   domain.AuthRepository
+}
+
+// NewTenantService 創建租戶服務
+func NewTenantService(tenantRepo domain.TenantRepository, authRepo domain.AuthRepository) *TenantService {
+	return &TenantService{tenantRepo: tenantRepo, authRepo: authRepo}
+}
+
+// parseTenantID 解析租戶 ID，空字串與 0 皆表示全域（GlobalTenantID）
+func parseTenantID(id string) (int64, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return domain.GlobalTenantID, nil
+	}
+	parsed, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || parsed < 0 {
+		return 0, domain.ErrInvalidTenantID
+	}
+	return parsed, nil
+}
+
+// requireTenantMember 確認用戶為租戶成員，全域範圍不需檢查
+func requireTenantMember(ctx context.Context, repo domain.AuthRepository, tenantID, userID int64) error {
+	if tenantID == domain.GlobalTenantID {
+		return nil
+	}
+	member, err := repo.IsTenantMember(ctx, tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return domain.ErrNotTenantMember
+	}
+	return nil
+}
+
+// validateTenant 清理並驗證租戶名稱與描述
+func validateTenant(tenant *domain.Tenant) error {
+	tenant.Name = strings.TrimSpace(tenant.Name)
+	tenant.Description = strings.TrimSpace(tenant.Description)
+
+	if tenant.Name == "" || utf8.RuneCountInString(tenant.Name) > domain.MaxTenantNameLength {
+		return domain.ErrInvalidTenantName
+	}
+	if utf8.RuneCountInString(tenant.Description) > maxDescriptionLength {
+		return domain.ErrInvalidDescription
+	}
+	return nil
+}
+
+// ListTenants 列出所有租戶
+func (s *TenantService) ListTenants(ctx context.Context) ([]domain.Tenant, error) {
+	return s.tenantRepo.ListTenants(ctx)
+}
+
+// GetTenant 獲取租戶信息
+func (s *TenantService) GetTenant(ctx context.Context, id string) (*domain.Tenant, error) {
+	tenantID, err := parseID(id, domain.ErrInvalidTenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tenantRepo.GetTenantByID(ctx, tenantID)
+}
+
+// CreateTenant 創建租戶，代碼不可重複且建立後不可變更
+func (s *TenantService) CreateTenant(ctx context.Context, tenant *domain.Tenant) (*domain.Tenant, error) {
+	tenant.Code = strings.TrimSpace(tenant.Code)
+	if err := domain.ValidateTenantCode(tenant.Code); err != nil {
+		return nil, err
+	}
+	if err := validateTenant(tenant); err != nil {
+		return nil, err
+	}
+
+	if err := s.tenantRepo.CreateTenant(ctx, tenant); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// UpdateTenant 更新租戶名稱與描述
+func (s *TenantService) UpdateTenant(ctx context.Context, id string, tenant *domain.Tenant) (*domain.Tenant, error) {
+	tenantID, err := parseID(id, domain.ErrInvalidTenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTenant(tenant); err != nil {
+		return nil, err
+	}
+
+	// 確認租戶存在
+	if _, err := s.tenantRepo.GetTenantByID(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	updateFields := map[string]interface{}{
+		"name":        tenant.Name,
+		"description": tenant.Description,
+	}
+	if err := s.tenantRepo.UpdateTenant(ctx, tenantID, updateFields); err != nil {
+		return nil, err
+	}
+
+	return s.tenantRepo.GetTenantByID(ctx, tenantID)
+}
+
+// DeleteTenant 刪除租戶，其成員資格與租戶內的角色分配一併移除
+func (s *TenantService) DeleteTenant(ctx context.Context, id string) error {
+	tenantID, err := parseID(id, domain.ErrInvalidTenantID)
+	if err != nil {
+		return err
+	}
+
+	return s.tenantRepo.DeleteTenant(ctx, tenantID)
+}
+
+// ListTenantMembers 列出租戶的成員及其角色
+func (s *TenantService) ListTenantMembers(ctx context.Context, id string) ([]domain.User, error) {
+	tenantID, err := parseID(id, domain.ErrInvalidTenantID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.tenantRepo.GetTenantByID(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	return s.tenantRepo.ListTenantMembers(ctx, tenantID)
+}
+
+// AddTenantMember 將用戶加入租戶，用戶與租戶皆須存在
+func (s *TenantService) AddTenantMember(ctx context.Context, id string, userID int64) error {
+	tenantID, err := parseID(id, domain.ErrInvalidTenantID)
+	if err != nil {
+		return err
+	}
+	if userID <= 0 {
+		return domain.ErrInvalidUserID
+	}
+	if _, err := s.tenantRepo.GetTenantByID(ctx, tenantID); err != nil {
+		return err
+	}
+	if _, err := s.authRepo.GetByID(ctx, strconv.FormatInt(userID, 10)); err != nil {
+		return err
+	}
+
+	return s.tenantRepo.AddTenantMember(ctx, tenantID, userID)
+}
+
+// RemoveTenantMember 將用戶移出租戶，其在該租戶內的角色分配一併移除
+func (s *TenantService) RemoveTenantMember(ctx context.Context, id, userID string) error {
+	tenantID, err := parseID(id, domain.ErrInvalidTenantID)
+	if err != nil {
+		return err
+	}
+	memberID, err := parseID(userID, domain.ErrInvalidUserID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.tenantRepo.GetTenantByID(ctx, tenantID); err != nil {
+		return err
+	}
+
+	return s.tenantRepo.RemoveTenantMember(ctx, tenantID, memberID)
+}
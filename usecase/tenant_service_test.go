@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"rbac-service/domain"
+)
+
+// MockTenantRepository 模擬 TenantRepository
+type MockTenantRepository struct {
+	mock.Mock
+}
+
+func (m *MockTenantRepository) ListTenants(ctx context.Context) ([]domain.Tenant, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) GetTenantByID(ctx context.Context, id int64) (*domain.Tenant, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) CreateTenant(ctx context.Context, tenant *domain.Tenant) error {
+	args := m.Called(ctx, tenant)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) UpdateTenant(ctx context.Context, id int64, updateFields map[string]interface{}) error {
+	args := m.Called(ctx, id, updateFields)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) DeleteTenant(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) ListTenantMembers(ctx context.Context, tenantID int64) ([]domain.User, error) {
+	args := m.Called(ctx, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *MockTenantRepository) AddTenantMember(ctx context.Context, tenantID, userID int64) error {
+	args := m.Called(ctx, tenantID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) RemoveTenantMember(ctx context.Context, tenantID, userID int64) error {
+	args := m.Called(ctx, tenantID, userID)
+	return args.Error(0)
+}
+
+func newTestTenantService() (*TenantService, *MockTenantRepository, *MockAuthRepository) {
+	tenantRepo := new(MockTenantRepository)
+	authRepo := new(MockAuthRepository)
+	return NewTenantService(tenantRepo, authRepo), tenantRepo, authRepo
+}
+
+func TestTenantService_CreateTenant_Successful(t *testing.T) {
+	service, tenantRepo, _ := newTestTenantService()
+
+	tenantRepo.On("CreateTenant", mock.Anything, mock.MatchedBy(func(tenant *domain.Tenant) bool {
+		return tenant.Code == "game-a" && tenant.Name == "Game A"
+	})).Return(nil)
+
+	tenant, err := service.CreateTenant(context.Background(), &domain.Tenant{Code: " game-a ", Name: " Game A "})
+
+	require.NoError(t, err)
+	assert.Equal(t, "game-a", tenant.Code)
+	tenantRepo.AssertExpectations(t)
+}
+
+func TestTenantService_CreateTenant_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant domain.Tenant
+		err    error
+	}{
+		{"代碼不可含大寫字母", domain.Tenant{Code: "Game-A", Name: "Game A"}, domain.ErrInvalidTenantCode},
+		{"代碼不可以 - 開頭", domain.Tenant{Code: "-game", Name: "Game A"}, domain.ErrInvalidTenantCode},
+		{"代碼不可為空", domain.Tenant{Name: "Game A"}, domain.ErrInvalidTenantCode},
+		{"名稱不可為空", domain.Tenant{Code: "game-a", Name: " "}, domain.ErrInvalidTenantName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, tenantRepo, _ := newTestTenantService()
+
+			_, err := service.CreateTenant(context.Background(), &tt.tenant)
+
+			assert.ErrorIs(t, err, tt.err)
+			tenantRepo.AssertNotCalled(t, "CreateTenant", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTenantService_AddTenantMember_UserNotFound(t *testing.T) {
+	service, tenantRepo, authRepo := newTestTenantService()
+
+	tenantRepo.On("GetTenantByID", mock.Anything, int64(5)).Return(&domain.Tenant{ID: 5, Code: "game-a"}, nil)
+	authRepo.On("GetByID", mock.Anything, "9").Return(nil, domain.ErrUserNotFound)
+
+	err := service.AddTenantMember(context.Background(), "5", 9)
+
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	tenantRepo.AssertNotCalled(t, "AddTenantMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTenantService_RemoveTenantMember_TenantNotFound(t *testing.T) {
+	service, tenantRepo, _ := newTestTenantService()
+
+	tenantRepo.On("GetTenantByID", mock.Anything, int64(5)).Return(nil, domain.ErrTenantNotFound)
+
+	err := service.RemoveTenantMember(context.Background(), "5", "1")
+
+	assert.ErrorIs(t, err, domain.ErrTenantNotFound)
+	tenantRepo.AssertNotCalled(t, "RemoveTenantMember", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -2,21 +2,38 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"rbac-service/domain"
 	"rbac-service/infrastructure/utils"
 )
 
+const (
+	// defaultUserPageSize 用戶列表預設每頁筆數
+	defaultUserPageSize = 20
+	// maxUserPageSize 用戶列表每頁筆數上限
+	maxUserPageSize = 100
+)
+
+// RevocationNotifier 將已寫入資料庫的撤銷記錄套用至本實例的撤銷清單並通知其他實例，由 AuthService 實作
+type RevocationNotifier interface {
+	NotifyRevocations(ctx context.Context, revocations *domain.ActiveRevocations)
+}
+
 // UserService 用戶服務實作
 type UserService struct {
-	repo domain.UserRepository
+	repo        domain.UserRepository
+	revocations RevocationNotifier
 }
 
-// NewUserService 創建用戶服務
-func NewUserService(repo domain.UserRepository) *UserService {
-	return &UserService{repo: repo}
+// NewUserService 創建用戶服務，revocations 用於傳播刪除用戶時寫入的撤銷記錄
+func NewUserService(repo domain.UserRepository, revocations RevocationNotifier) *UserService {
+	return &UserService{repo: repo, revocations: revocations}
 }
 
 // GetUser 獲取用戶信息
@@ -98,18 +115,151 @@ func (s *UserService) UpdateUser(ctx context.Context, user *domain.User) (*domai
 	return updatedUser, nil
 }
 
-// DeleteUser 刪除用戶
+// UpdateUserMetadata 以 metadata 取代用戶的所有屬性，空物件清除所有屬性
+func (s *UserService) UpdateUserMetadata(ctx context.Context, id string, metadata domain.UserMetadata) (*domain.User, error) {
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateUser(ctx, user.Username, map[string]interface{}{"metadata": metadata}); err != nil {
+		return nil, err
+	}
+
+	// 重新獲取更新後的用戶信息
+	return s.repo.GetByID(ctx, id)
+}
+
+// DeleteUser 刪除用戶並撤銷其已簽發的訪問令牌，同一用戶名稱重新註冊後取得的令牌不受影響
 func (s *UserService) DeleteUser(ctx context.Context, user *domain.User) error {
-	// 檢查 jwt 是否跟 db 內相同，final check
-	isTrue, err := utils.CompareJWTToken(ctx, user.Username, user.Jwt)
-	if err != nil || !isTrue {
-		return domain.ErrInvalidJwt
+	// JWT 的 iat 只到秒，撤銷時間點同樣取到秒
+	now := time.Now().Truncate(time.Second)
+	revocation := &domain.UserTokenRevocation{
+		Username:      user.Username,
+		RevokedBefore: now,
+		ExpiresAt:     now.Add(utils.AccessTokenTTL),
 	}
+
 	// 調用倉儲層刪除用戶
-	err = s.repo.DeleteUser(ctx, user.Username)
+	err := s.repo.DeleteUser(ctx, user.Username, revocation)
 	if err != nil {
 		return err
 	}
 
+	s.revocations.NotifyRevocations(ctx, &domain.ActiveRevocations{Users: []domain.UserTokenRevocation{*revocation}})
 	return nil
 }
+
+// ListUsers 依條件查詢用戶列表，cursor 為上一頁返回的 next_cursor，不可與 offset 同時使用
+func (s *UserService) ListUsers(ctx context.Context, filter domain.UserListFilter, cursor string) (*domain.UserPage, error) {
+	if err := normalizeUserFilter(&filter); err != nil {
+		return nil, err
+	}
+	if cursor != "" {
+		if filter.Offset > 0 {
+			return nil, domain.ErrInvalidUserFilter
+		}
+		after, err := decodeUserCursor(cursor, filter.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+	pageSize := filter.Limit
+
+	// 多取一筆判斷是否還有下一頁
+	filter.Limit = pageSize + 1
+	users, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.UserPage{Items: users, Total: total}
+	if len(users) > pageSize {
+		page.Items = users[:pageSize]
+		page.NextCursor = encodeUserCursor(page.Items[pageSize-1], filter.SortBy)
+	}
+	return page, nil
+}
+
+// normalizeUserFilter 驗證查詢條件並補上預設值
+func normalizeUserFilter(filter *domain.UserListFilter) error {
+	if filter.Limit < 0 || filter.Offset < 0 || filter.RoleID < 0 || filter.TenantID < 0 {
+		return domain.ErrInvalidUserFilter
+	}
+
+	switch filter.UsernameMatch {
+	case "":
+		filter.UsernameMatch = domain.UsernameMatchPrefix
+	case domain.UsernameMatchPrefix, domain.UsernameMatchContains:
+	default:
+		return domain.ErrInvalidUserFilter
+	}
+
+	switch filter.Status {
+	case "", domain.UserStatusActive, domain.UserStatusDisabled:
+	default:
+		return domain.ErrInvalidUserFilter
+	}
+
+	switch filter.SortBy {
+	case "":
+		filter.SortBy = domain.UserSortCreatedAt
+	case domain.UserSortCreatedAt, domain.UserSortUpdatedAt:
+	default:
+		return domain.ErrInvalidUserFilter
+	}
+
+	switch filter.SortOrder {
+	case "":
+		filter.SortOrder = domain.SortOrderDesc
+	case domain.SortOrderAsc, domain.SortOrderDesc:
+	default:
+		return domain.ErrInvalidUserFilter
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultUserPageSize
+	}
+	if filter.Limit > maxUserPageSize {
+		filter.Limit = maxUserPageSize
+	}
+	return nil
+}
+
+// encodeUserCursor 將排序欄位、排序值與 ID 編碼為不透明的游標
+func encodeUserCursor(user domain.User, sortBy string) string {
+	sortValue := user.CreatedAt
+	if sortBy == domain.UserSortUpdatedAt {
+		sortValue = user.UpdatedAt
+	}
+	raw := fmt.Sprintf("%s:%d:%d", sortBy, sortValue.UnixNano(), user.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeUserCursor 解析游標，排序欄位與本次查詢不同時視為無效
+func decodeUserCursor(cursor string, sortBy string) (*domain.UserCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, domain.ErrInvalidUserFilter
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || parts[0] != sortBy {
+		return nil, domain.ErrInvalidUserFilter
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, domain.ErrInvalidUserFilter
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || id <= 0 {
+		return nil, domain.ErrInvalidUserFilter
+	}
+
+	return &domain.UserCursor{SortValue: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
@@ -3,12 +3,17 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"rbac-service/domain"
+	"rbac-service/infrastructure/utils"
 )
 
 // MockUserRepository 模擬 UserRepository
@@ -46,25 +51,37 @@ func (m *MockUserRepository) UpdateUser(ctx context.Context, username string, up
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) DeleteUser(ctx context.Context, username string) error {
-	args := m.Called(ctx, username)
+func (m *MockUserRepository) DeleteUser(ctx context.Context, username string, revocation *domain.UserTokenRevocation) error {
+	args := m.Called(ctx, username, revocation)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) DeleteUserJwt(ctx context.Context, jwt string) error {
-	args := m.Called(ctx, jwt)
-	return args.Error(0)
+func (m *MockUserRepository) List(ctx context.Context, filter domain.UserListFilter) ([]domain.User, int64, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+// MockRevocationNotifier 模擬撤銷記錄的傳播
+type MockRevocationNotifier struct {
+	mock.Mock
+}
+
+func (m *MockRevocationNotifier) NotifyRevocations(ctx context.Context, revocations *domain.ActiveRevocations) {
+	m.Called(ctx, revocations)
 }
 
 func TestUserService_GetUser_SuccessfulRetrieval(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockUserRepository)
-	userService := NewUserService(mockRepo)
+	userService := NewUserService(mockRepo, nil)
 
 	userID := "testuser123"
 	expectedUser := &domain.User{
 		Username: userID,
-		Roles:    []string{"user"},
+		Roles:    []domain.Role{{Name: "user"}},
 	}
 
 	// 設定模擬行為
@@ -83,7 +100,7 @@ func TestUserService_GetUser_SuccessfulRetrieval(t *testing.T) {
 func TestUserService_GetUser_EmptyUserID(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockUserRepository)
-	userService := NewUserService(mockRepo)
+	userService := NewUserService(mockRepo, nil)
 
 	// 執行獲取用戶
 	user, err := userService.GetUser(context.Background(), "")
@@ -98,7 +115,7 @@ func TestUserService_GetUser_EmptyUserID(t *testing.T) {
 func TestUserService_GetUser_UserNotFound(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockUserRepository)
-	userService := NewUserService(mockRepo)
+	userService := NewUserService(mockRepo, nil)
 
 	userID := "nonexistentuser"
 	expectedError := errors.New("user not found")
@@ -119,12 +136,12 @@ func TestUserService_GetUser_UserNotFound(t *testing.T) {
 func TestUserService_GetByUsername_SuccessfulRetrieval(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockUserRepository)
-	userService := NewUserService(mockRepo)
+	userService := NewUserService(mockRepo, nil)
 
 	username := "testuser123"
 	expectedUser := &domain.User{
 		Username: username,
-		Roles:    []string{"user"},
+		Roles:    []domain.Role{{Name: "user"}},
 	}
 
 	// 設定模擬行為
@@ -143,7 +160,7 @@ func TestUserService_GetByUsername_SuccessfulRetrieval(t *testing.T) {
 func TestUserService_GetByUsername_EmptyUsername(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockUserRepository)
-	userService := NewUserService(mockRepo)
+	userService := NewUserService(mockRepo, nil)
 
 	// 執行獲取用戶
 	user, err := userService.GetByUsername(context.Background(), "")
@@ -158,7 +175,7 @@ func TestUserService_GetByUsername_EmptyUsername(t *testing.T) {
 func TestUserService_GetByUsername_UserNotFound(t *testing.T) {
 	// 準備測試數據
 	mockRepo := new(MockUserRepository)
-	userService := NewUserService(mockRepo)
+	userService := NewUserService(mockRepo, nil)
 
 	username := "nonexistentuser"
 	expectedError := errors.New("user not found")
@@ -175,3 +192,187 @@ func TestUserService_GetByUsername_UserNotFound(t *testing.T) {
 	assert.Equal(t, expectedError, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestUserService_UpdateUserMetadata_Successful(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockUserRepository)
+	userService := NewUserService(mockRepo, nil)
+
+	metadata := domain.UserMetadata{"region": "tw", "level": "3"}
+	updatedUser := &domain.User{ID: 1, Username: "alice", Metadata: metadata}
+
+	// 設定模擬行為
+	mockRepo.On("GetByID", mock.Anything, "1").Return(&domain.User{ID: 1, Username: "alice"}, nil).Once()
+	mockRepo.On("UpdateUser", mock.Anything, "alice", map[string]interface{}{"metadata": metadata}).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, "1").Return(updatedUser, nil).Once()
+
+	// 執行更新用戶屬性
+	user, err := userService.UpdateUserMetadata(context.Background(), "1", metadata)
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, updatedUser, user)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_UpdateUserMetadata_Invalid(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockUserRepository)
+	userService := NewUserService(mockRepo, nil)
+
+	tooMany := domain.UserMetadata{}
+	for i := 0; i <= domain.MaxUserMetadataKeys; i++ {
+		tooMany[fmt.Sprintf("key_%d", i)] = "x"
+	}
+	invalid := []domain.UserMetadata{
+		tooMany,
+		{"region.code": "tw"},
+		{"1region": "tw"},
+		{"": "tw"},
+		// id 與 username 保留給用戶本身的欄位
+		{"username": "bob"},
+		{"region": strings.Repeat("x", domain.MaxUserMetadataValueLength+1)},
+	}
+
+	for _, metadata := range invalid {
+		// 執行更新用戶屬性
+		_, err := userService.UpdateUserMetadata(context.Background(), "1", metadata)
+
+		// 斷言：驗證失敗時不查詢資料庫
+		assert.ErrorIs(t, err, domain.ErrInvalidUserMetadata)
+	}
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestUserService_ListUsers_Defaults(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockUserRepository)
+	userService := NewUserService(mockRepo, nil)
+	expectedFilter := domain.UserListFilter{
+		Username:      "ja",
+		UsernameMatch: domain.UsernameMatchPrefix,
+		SortBy:        domain.UserSortCreatedAt,
+		SortOrder:     domain.SortOrderDesc,
+		Limit:         defaultUserPageSize + 1,
+	}
+
+	// 設定模擬行為
+	mockRepo.On("List", mock.Anything, expectedFilter).Return([]domain.User{{ID: 2, Username: "jared"}}, int64(1), nil)
+
+	// 執行查詢
+	page, err := userService.ListUsers(context.Background(), domain.UserListFilter{Username: "ja"}, "")
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_ListUsers_CursorRoundTrip(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockUserRepository)
+	userService := NewUserService(mockRepo, nil)
+	updatedAt := time.Date(2025, 5, 11, 7, 7, 26, 0, time.UTC)
+	users := []domain.User{{ID: 3, UpdatedAt: updatedAt.Add(time.Hour)}, {ID: 2, UpdatedAt: updatedAt}, {ID: 1}}
+
+	// 設定模擬行為：第一頁多取一筆，第二頁帶上游標
+	mockRepo.On("List", mock.Anything, mock.MatchedBy(func(filter domain.UserListFilter) bool {
+		return filter.After == nil
+	})).Return(users, int64(3), nil)
+	mockRepo.On("List", mock.Anything, mock.MatchedBy(func(filter domain.UserListFilter) bool {
+		return filter.After != nil && filter.After.ID == 2 && filter.After.SortValue.Equal(updatedAt)
+	})).Return([]domain.User{{ID: 1}}, int64(3), nil)
+
+	// 執行查詢
+	filter := domain.UserListFilter{SortBy: domain.UserSortUpdatedAt, Limit: 2}
+	first, err := userService.ListUsers(context.Background(), filter, "")
+	assert.NoError(t, err)
+	assert.Len(t, first.Items, 2)
+	assert.NotEmpty(t, first.NextCursor)
+
+	second, err := userService.ListUsers(context.Background(), filter, first.NextCursor)
+
+	// 斷言
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.User{{ID: 1}}, second.Items)
+	assert.Empty(t, second.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_ListUsers_InvalidFilter(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	userService := NewUserService(mockRepo, nil)
+	createdCursor := encodeUserCursor(domain.User{ID: 1}, domain.UserSortCreatedAt)
+
+	tests := []struct {
+		name   string
+		filter domain.UserListFilter
+		cursor string
+	}{
+		{name: "negative limit", filter: domain.UserListFilter{Limit: -1}},
+		{name: "negative offset", filter: domain.UserListFilter{Offset: -1}},
+		{name: "unknown match", filter: domain.UserListFilter{UsernameMatch: "regex"}},
+		{name: "unknown status", filter: domain.UserListFilter{Status: "locked"}},
+		{name: "unknown sort", filter: domain.UserListFilter{SortBy: "username"}},
+		{name: "unknown order", filter: domain.UserListFilter{SortOrder: "up"}},
+		{name: "offset with cursor", filter: domain.UserListFilter{Offset: 10}, cursor: createdCursor},
+		{name: "malformed cursor", cursor: "not-a-cursor"},
+		{name: "cursor from other sort", filter: domain.UserListFilter{SortBy: domain.UserSortUpdatedAt}, cursor: createdCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := userService.ListUsers(context.Background(), tt.filter, tt.cursor)
+			assert.ErrorIs(t, err, domain.ErrInvalidUserFilter)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestUserService_DeleteUser_RevokesTokens(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockUserRepository)
+	mockNotifier := new(MockRevocationNotifier)
+	userService := NewUserService(mockRepo, mockNotifier)
+	user := &domain.User{ID: 1, Username: "testuser"}
+	before := time.Now().Truncate(time.Second)
+
+	// 設定模擬行為
+	var revocation *domain.UserTokenRevocation
+	mockRepo.On("DeleteUser", mock.Anything, "testuser", mock.AnythingOfType("*domain.UserTokenRevocation")).
+		Run(func(args mock.Arguments) {
+			revocation = args.Get(2).(*domain.UserTokenRevocation)
+		}).
+		Return(nil)
+	mockNotifier.On("NotifyRevocations", mock.Anything, mock.AnythingOfType("*domain.ActiveRevocations")).Return()
+
+	// 執行測試
+	err := userService.DeleteUser(context.Background(), user)
+
+	// 驗證結果
+	require.NoError(t, err)
+	require.NotNil(t, revocation)
+	assert.Equal(t, "testuser", revocation.Username)
+	assert.False(t, revocation.RevokedBefore.Before(before))
+	assert.Equal(t, revocation.RevokedBefore.Add(utils.AccessTokenTTL), revocation.ExpiresAt)
+	mockNotifier.AssertCalled(t, "NotifyRevocations", mock.Anything, &domain.ActiveRevocations{Users: []domain.UserTokenRevocation{*revocation}})
+}
+
+func TestUserService_DeleteUser_NotFound(t *testing.T) {
+	// 準備測試數據
+	mockRepo := new(MockUserRepository)
+	mockNotifier := new(MockRevocationNotifier)
+	userService := NewUserService(mockRepo, mockNotifier)
+
+	// 設定模擬行為
+	mockRepo.On("DeleteUser", mock.Anything, "testuser", mock.Anything).Return(domain.ErrUserNotFound)
+
+	// 執行測試
+	err := userService.DeleteUser(context.Background(), &domain.User{ID: 1, Username: "testuser"})
+
+	// 驗證結果：刪除失敗時不傳播撤銷記錄
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	mockNotifier.AssertNotCalled(t, "NotifyRevocations", mock.Anything, mock.Anything)
+}